@@ -0,0 +1,447 @@
+// Package allocator provides IP address management (IPAM) of subnets carved from a pool of parent networks.
+//
+// A Pool hands out free subnets of a requested prefix length, tracking allocations and rejecting
+// overlapping ones, the Strategy picking among free subnets is chosen with WithStrategy:
+//
+//	p, err := allocator.New([]net.IPNet{*parent}, allocator.WithStrategy(allocator.BestFit))
+//	n, err := p.AllocatePrefix(24)
+//	err = p.Occupy(*existing)
+//	err = p.Release(n)
+//
+// Allocations can carry a name and labels, making a Pool usable as a minimal embedded IPAM:
+//
+//	a, err := p.AllocateNamed(24, "eu-west-1", map[string]string{"env": "prod"})
+//	a, ok := p.Find("eu-west-1")
+//	prod := p.List(allocator.HasLabel("env", "prod"))
+//
+// A HostPool hands out individual addresses of a single subnet instead, DHCP-style.
+// IPv4-mapped IPv6 networks are treated as IPv4 networks.
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/ipset"
+)
+
+// Errors returned by Pool methods, they're wrapped with details about the request.
+var (
+	ErrExhausted = errors.New("allocator: pool exhausted")
+	ErrOverlap   = errors.New("allocator: overlapping allocation")
+)
+
+// Strategy selects which free subnet a Pool allocates.
+type Strategy int
+
+// Allocation strategies, every strategy allocates the lowest subnet within the free space it picks.
+const (
+	// FirstFit allocates from the lowest free space which fits, it's the fastest strategy.
+	FirstFit Strategy = iota
+	// BestFit allocates from the smallest contiguous free range which fits,
+	// keeping large gaps between allocations intact to minimize fragmentation.
+	BestFit
+	// Buddy allocates from the smallest free aligned block which fits, splitting it in halves as a buddy allocator does,
+	// so larger blocks stay whole and freed subnets coalesce with their buddies.
+	Buddy
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case FirstFit:
+		return "first-fit"
+	case BestFit:
+		return "best-fit"
+	case Buddy:
+		return "buddy"
+	}
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// AllocatorOption configures a Pool.
+type AllocatorOption func(*Pool)
+
+// WithStrategy sets the allocation Strategy of a Pool, the default is FirstFit.
+func WithStrategy(s Strategy) AllocatorOption {
+	return func(p *Pool) {
+		p.strategy = s
+	}
+}
+
+// Synchronized makes a Pool safe for concurrent use by serializing its methods with a mutex,
+// e.g., when called from many controller goroutines.
+func Synchronized() AllocatorOption {
+	return func(p *Pool) {
+		p.synchronized = true
+	}
+}
+
+// Allocation is an allocated subnet and its optional name and labels.
+type Allocation struct {
+	Prefix net.IPNet
+	// Name is unique within a Pool, unless empty.
+	Name   string
+	Labels map[string]string
+}
+
+// Filter selects Allocations returned by List.
+type Filter func(a Allocation) bool
+
+// HasLabel returns a Filter selecting Allocations with a label set to the given value.
+func HasLabel(key, value string) Filter {
+	return func(a Allocation) bool {
+		v, ok := a.Labels[key]
+		return ok && v == value
+	}
+}
+
+// Within returns a Filter selecting Allocations inside a network.
+func Within(n net.IPNet) Filter {
+	return func(a Allocation) bool {
+		return ipcalc.Contains(n, a.Prefix)
+	}
+}
+
+// parent is a parent network and its free space.
+type parent struct {
+	prefix net.IPNet
+	free   ipset.Set
+}
+
+// Pool allocates subnets from one or more parent networks,
+// it's not safe for concurrent use unless created with the Synchronized option.
+type Pool struct {
+	parents []*parent
+	// allocs is sorted by prefix as by ipcalc.CompareNet, allocations never overlap.
+	allocs []Allocation
+	// names maps allocation names to prefixes.
+	names    map[string]net.IPNet
+	strategy Strategy
+
+	synchronized bool
+	mu           sync.Mutex
+}
+
+// New returns a Pool allocating subnets from a list of parent networks, which must not overlap.
+// Subnets are allocated from parents in the order defined by ipcalc.CompareNet, i.e., lowest address first.
+func New(parents []net.IPNet, opts ...AllocatorOption) (*Pool, error) {
+	p := &Pool{names: make(map[string]net.IPNet)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, n := range parents {
+		norm, ok := normalize(n)
+		if !ok {
+			return nil, fmt.Errorf("allocator: invalid parent network %v", &n)
+		}
+		for _, x := range p.parents {
+			if ipcalc.Overlaps(x.prefix, norm) {
+				return nil, fmt.Errorf("allocator: parent network %v overlaps %v", &n, &x.prefix)
+			}
+		}
+		x := &parent{prefix: norm}
+		x.free.AddPrefix(norm)
+		p.parents = append(p.parents, x)
+	}
+	slices.SortFunc(p.parents, func(a, b *parent) int { return ipcalc.CompareNet(a.prefix, b.prefix) })
+	return p, nil
+}
+
+// Parents returns the parent networks of the Pool, in allocation order.
+func (p *Pool) Parents() []net.IPNet {
+	nets := make([]net.IPNet, len(p.parents))
+	for i, x := range p.parents {
+		nets[i] = x.prefix
+	}
+	return nets
+}
+
+// AllocatePrefix allocates a free subnet with the given prefix length, chosen by the Pool's Strategy.
+// e.g., AllocatePrefix(26) -> 10.0.0.64/26 for a first-fit 10.0.0.0/24 pool with 10.0.0.0/26 allocated.
+func (p *Pool) AllocatePrefix(prefixLen int) (net.IPNet, error) {
+	a, err := p.AllocateNamed(prefixLen, "", nil)
+	return a.Prefix, err
+}
+
+// AllocateNamed is like AllocatePrefix but records a name, which must be unique unless empty, and labels.
+func (p *Pool) AllocateNamed(prefixLen int, name string, labels map[string]string) (Allocation, error) {
+	defer p.lock()()
+	if prefixLen < 0 || prefixLen > 8*net.IPv6len {
+		return Allocation{}, fmt.Errorf("allocator: invalid prefix length %v", prefixLen)
+	}
+	if err := p.checkName(name); err != nil {
+		return Allocation{}, err
+	}
+	x, b, ok := p.find(prefixLen)
+	if !ok {
+		return Allocation{}, fmt.Errorf("%w: no free /%v subnet", ErrExhausted, prefixLen)
+	}
+	_, bits := b.Mask.Size()
+	a := Allocation{Prefix: net.IPNet{IP: b.IP, Mask: net.CIDRMask(prefixLen, bits)}, Name: name, Labels: maps.Clone(labels)}
+	p.occupy(x, a)
+	return a.clone(), nil
+}
+
+// Occupy allocates a specific subnet, e.g., one assigned before the Pool was created.
+// It returns an error wrapping ErrOverlap if the subnet overlaps an existing allocation,
+// or an error if it isn't inside a parent network.
+func (p *Pool) Occupy(n net.IPNet) error {
+	return p.OccupyNamed(n, "", nil)
+}
+
+// OccupyNamed is like Occupy but records a name, which must be unique unless empty, and labels.
+func (p *Pool) OccupyNamed(n net.IPNet, name string, labels map[string]string) error {
+	defer p.lock()()
+	norm, ok := normalize(n)
+	if !ok {
+		return fmt.Errorf("allocator: invalid network %v", &n)
+	}
+	x := p.parentOf(norm)
+	if x == nil {
+		return fmt.Errorf("allocator: %v not in pool", &n)
+	}
+	if a, ok := p.overlapping(norm); ok {
+		return fmt.Errorf("%w: %v overlaps %v", ErrOverlap, &n, &a)
+	}
+	if err := p.checkName(name); err != nil {
+		return err
+	}
+	p.occupy(x, Allocation{Prefix: norm, Name: name, Labels: maps.Clone(labels)})
+	return nil
+}
+
+// Find returns the Allocation with the given name.
+func (p *Pool) Find(name string) (Allocation, bool) {
+	defer p.lock()()
+	n, ok := p.names[name]
+	if !ok {
+		return Allocation{}, false
+	}
+	i, _ := p.search(n)
+	return p.allocs[i].clone(), true
+}
+
+// List returns the Allocations selected by every Filter, in the order defined by ipcalc.CompareNet.
+// Filters must not call the Pool's methods.
+func (p *Pool) List(filters ...Filter) []Allocation {
+	defer p.lock()()
+	var out []Allocation
+	for _, a := range p.allocs {
+		if a.matches(filters) {
+			out = append(out, a.clone())
+		}
+	}
+	return out
+}
+
+// Release returns an allocated subnet to the Pool, it must match an allocation exactly.
+// Freed space merges with adjacent free space, so released buddies coalesce back into larger free blocks.
+func (p *Pool) Release(n net.IPNet) error {
+	defer p.lock()()
+	norm, ok := normalize(n)
+	if !ok {
+		return fmt.Errorf("allocator: invalid network %v", &n)
+	}
+	i, found := p.search(norm)
+	if !found {
+		return fmt.Errorf("allocator: %v not allocated", &n)
+	}
+	delete(p.names, p.allocs[i].Name)
+	p.allocs = slices.Delete(p.allocs, i, i+1)
+	p.parentOf(norm).free.AddPrefix(norm)
+	return nil
+}
+
+// Fragmentation describes how chopped-up the free space of a Pool is.
+type Fragmentation struct {
+	// Total is the number of addresses in the parent networks.
+	Total *big.Int
+	// Free is the number of unallocated addresses.
+	Free *big.Int
+	// FreeBlocks is the number of networks returned by Free.
+	FreeBlocks int
+	// Largest is the largest free network, or a zero IPNet if there's no free space.
+	Largest net.IPNet
+	// Ratio is the fraction of free addresses outside Largest, from 0 (a single free block) towards 1 (many small blocks).
+	Ratio float64
+}
+
+// Fragmentation returns a report of the Pool's free space.
+func (p *Pool) Fragmentation() Fragmentation {
+	defer p.lock()()
+	f := Fragmentation{Total: new(big.Int), Free: new(big.Int)}
+	largest := new(big.Int)
+	for _, x := range p.parents {
+		f.Total.Add(f.Total, ipcalc.CIDRToRange(x.prefix).Len())
+	}
+	for _, n := range p.free() {
+		size := ipcalc.CIDRToRange(n).Len()
+		f.Free.Add(f.Free, size)
+		f.FreeBlocks++
+		if size.Cmp(largest) > 0 {
+			largest, f.Largest = size, n
+		}
+	}
+	if f.Free.Sign() > 0 {
+		r, _ := new(big.Rat).SetFrac(largest, f.Free).Float64()
+		f.Ratio = 1 - r
+	}
+	return f
+}
+
+// Allocated returns the allocated subnets in the order defined by ipcalc.CompareNet.
+func (p *Pool) Allocated() []net.IPNet {
+	defer p.lock()()
+	nets := make([]net.IPNet, len(p.allocs))
+	for i, a := range p.allocs {
+		nets[i] = a.Prefix
+	}
+	return nets
+}
+
+// Len returns the number of allocated subnets.
+func (p *Pool) Len() int {
+	defer p.lock()()
+	return len(p.allocs)
+}
+
+// Free returns the minimal list of networks which aren't allocated, in ascending order.
+func (p *Pool) Free() []net.IPNet {
+	defer p.lock()()
+	return p.free()
+}
+
+func (p *Pool) free() []net.IPNet {
+	var nets []net.IPNet
+	for _, x := range p.parents {
+		nets = append(nets, x.free.Prefixes()...)
+	}
+	return nets
+}
+
+// lock locks the Pool if it's synchronized, returning the function unlocking it.
+func (p *Pool) lock() func() {
+	if !p.synchronized {
+		return func() {}
+	}
+	p.mu.Lock()
+	return p.mu.Unlock
+}
+
+// find returns the free block a subnet with the given prefix length should be allocated from, and its parent.
+// Free space is scanned as contiguous ranges split into aligned blocks, in ascending order.
+func (p *Pool) find(prefixLen int) (*parent, net.IPNet, bool) {
+	var (
+		best      *parent
+		bestBlock net.IPNet
+		bestOnes  int
+		bestSize  *big.Int
+	)
+	for _, x := range p.parents {
+		for _, r := range x.free.Ranges() {
+			var size *big.Int
+			if p.strategy == BestFit {
+				size = r.Len()
+			}
+			for _, b := range ipcalc.RangeToCIDRs(r) {
+				ones, bits := b.Mask.Size()
+				if ones > prefixLen || prefixLen > bits {
+					continue
+				}
+				switch p.strategy {
+				case BestFit:
+					if best == nil || size.Cmp(bestSize) < 0 {
+						best, bestBlock, bestSize = x, b, size
+					}
+				case Buddy:
+					if best == nil || ones > bestOnes {
+						best, bestBlock, bestOnes = x, b, ones
+					}
+				default:
+					return x, b, true
+				}
+				if p.strategy == BestFit {
+					// Later blocks of the same range can't be better.
+					break
+				}
+			}
+		}
+	}
+	return best, bestBlock, best != nil
+}
+
+// occupy records a free subnet of a parent network as allocated.
+func (p *Pool) occupy(x *parent, a Allocation) {
+	x.free.Remove(a.Prefix)
+	i, _ := p.search(a.Prefix)
+	p.allocs = slices.Insert(p.allocs, i, a)
+	if a.Name != "" {
+		p.names[a.Name] = a.Prefix
+	}
+}
+
+// search returns the position of a subnet in allocs, and whether it's allocated.
+func (p *Pool) search(n net.IPNet) (int, bool) {
+	return slices.BinarySearchFunc(p.allocs, n, func(a Allocation, n net.IPNet) int {
+		return ipcalc.CompareNet(a.Prefix, n)
+	})
+}
+
+// checkName returns an error if a non-empty name is already used.
+func (p *Pool) checkName(name string) error {
+	if n, ok := p.names[name]; ok {
+		return fmt.Errorf("allocator: name %q already allocated %v", name, &n)
+	}
+	return nil
+}
+
+// parentOf returns the parent network containing a subnet, or nil.
+func (p *Pool) parentOf(n net.IPNet) *parent {
+	for _, x := range p.parents {
+		if ipcalc.Contains(x.prefix, n) {
+			return x
+		}
+	}
+	return nil
+}
+
+// overlapping returns an allocation overlapping a subnet, if any.
+// Allocations are sorted and disjoint, so only the ones right before and after the subnet's position can overlap it.
+func (p *Pool) overlapping(n net.IPNet) (net.IPNet, bool) {
+	i, _ := p.search(n)
+	for _, j := range []int{i - 1, i} {
+		if j >= 0 && j < len(p.allocs) && ipcalc.Overlaps(p.allocs[j].Prefix, n) {
+			return p.allocs[j].Prefix, true
+		}
+	}
+	return net.IPNet{}, false
+}
+
+// clone returns a copy of an Allocation which doesn't share its labels.
+func (a Allocation) clone() Allocation {
+	a.Labels = maps.Clone(a.Labels)
+	return a
+}
+
+// matches returns whether an Allocation is selected by every Filter.
+func (a Allocation) matches(filters []Filter) bool {
+	for _, f := range filters {
+		if !f(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize returns a normalized network, ok is false if it's invalid or has a non-contiguous mask.
+func normalize(n net.IPNet) (net.IPNet, bool) {
+	n = ipcalc.Normalize(n)
+	_, bits := n.Mask.Size()
+	return n, n.IP != nil && bits != 0
+}
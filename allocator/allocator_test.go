@@ -0,0 +1,322 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func netStrings(nets []net.IPNet) []string {
+	var s []string
+	for _, n := range nets {
+		s = append(s, n.String())
+	}
+	return s
+}
+
+func newPool(t *testing.T, parents ...string) *Pool {
+	t.Helper()
+	var nets []net.IPNet
+	for _, s := range parents {
+		nets = append(nets, mustCIDR(t, s))
+	}
+	p, err := New(nets)
+	if err != nil {
+		t.Fatalf("New(%v) error = %v", parents, err)
+	}
+	return p
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		parents []net.IPNet
+		want    []string
+		ok      bool
+	}{
+		{[]net.IPNet{mustCIDR(t, "10.1.0.0/16"), mustCIDR(t, "10.0.0.0/16")}, []string{"10.0.0.0/16", "10.1.0.0/16"}, true},
+		{[]net.IPNet{mustCIDR(t, "::ffff:10.0.0.0/104"), mustCIDR(t, "2001:db8::/32")}, []string{"10.0.0.0/8", "2001:db8::/32"}, true},
+		{nil, nil, true},
+		{[]net.IPNet{mustCIDR(t, "10.0.0.0/8"), mustCIDR(t, "10.1.0.0/16")}, nil, false},
+		{[]net.IPNet{{IP: net.ParseIP("10.0.0.0"), Mask: net.IPMask{255, 0, 255, 0}}}, nil, false},
+		{[]net.IPNet{{}}, nil, false},
+	}
+	for _, tt := range tests {
+		p, err := New(tt.parents)
+		if !tt.ok {
+			if err == nil {
+				t.Errorf("New(%v) error = nil, want error", tt.parents)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("New(%v) error = %v", tt.parents, err)
+			continue
+		}
+		if got := netStrings(p.Parents()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("New(%v).Parents() = %v, want %v", tt.parents, got, tt.want)
+		}
+	}
+}
+
+func TestAllocatePrefix(t *testing.T) {
+	p := newPool(t, "10.0.1.0/24", "10.0.0.0/24", "2001:db8::/48")
+	tests := []struct {
+		prefixLen int
+		want      string
+	}{
+		{26, "10.0.0.0/26"},
+		{24, "10.0.1.0/24"},
+		{25, "10.0.0.128/25"},
+		{26, "10.0.0.64/26"},
+		{24, ""},
+		{30, ""},
+		{64, "2001:db8::/64"},
+		{56, "2001:db8:0:100::/56"},
+		{64, "2001:db8:0:1::/64"},
+		{129, ""},
+		{-1, ""},
+	}
+	for _, tt := range tests {
+		got, err := p.AllocatePrefix(tt.prefixLen)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("AllocatePrefix(%v) = %v, want error", tt.prefixLen, &got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("AllocatePrefix(%v) = %v, %v, want %v", tt.prefixLen, &got, err, tt.want)
+		}
+	}
+	if _, err := p.AllocatePrefix(30); !errors.Is(err, ErrExhausted) {
+		t.Errorf("AllocatePrefix(30) error = %v, want %v", err, ErrExhausted)
+	}
+	want := []string{"10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/25", "10.0.1.0/24", "2001:db8::/64", "2001:db8:0:1::/64", "2001:db8:0:100::/56"}
+	if got := netStrings(p.Allocated()); !reflect.DeepEqual(got, want) || p.Len() != len(want) {
+		t.Errorf("Allocated() = %v, want %v", got, want)
+	}
+}
+
+func TestOccupy(t *testing.T) {
+	p := newPool(t, "10.0.0.0/24")
+	for _, tt := range []struct {
+		n   string
+		err error
+		ok  bool
+	}{
+		{"10.0.0.64/26", nil, true},
+		{"10.0.0.128/25", nil, true},
+		{"10.0.0.64/27", ErrOverlap, false},
+		{"10.0.0.0/24", ErrOverlap, false},
+		{"10.0.0.0/25", ErrOverlap, false},
+		{"10.0.1.0/24", nil, false},
+		{"10.0.0.0/26", nil, true},
+	} {
+		err := p.Occupy(mustCIDR(t, tt.n))
+		if tt.ok != (err == nil) || tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("Occupy(%v) error = %v, want ok = %v, %v", tt.n, err, tt.ok, tt.err)
+		}
+	}
+	if got := p.Free(); got != nil {
+		t.Errorf("Free() = %v, want none", netStrings(got))
+	}
+	if _, err := p.AllocatePrefix(32); !errors.Is(err, ErrExhausted) {
+		t.Errorf("AllocatePrefix(32) error = %v, want %v", err, ErrExhausted)
+	}
+}
+
+func TestFree(t *testing.T) {
+	p := newPool(t, "10.0.0.0/24", "2001:db8::/32")
+	p.Occupy(mustCIDR(t, "10.0.0.64/26"))
+	p.AllocatePrefix(33)
+	want := []string{"10.0.0.0/26", "10.0.0.128/25", "2001:db8:8000::/33"}
+	if got := netStrings(p.Free()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Free() = %v, want %v", got, want)
+	}
+}
+
+func TestStrategy(t *testing.T) {
+	// Free space is 10.0.0.0-10.0.1.127, i.e., [10.0.0.0/24 10.0.1.0/25], and 10.0.2.128/25.
+	tests := []struct {
+		strategy Strategy
+		want     []string
+	}{
+		{FirstFit, []string{"10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/25"}},
+		{BestFit, []string{"10.0.2.128/26", "10.0.2.192/26", "10.0.0.0/25"}},
+		{Buddy, []string{"10.0.1.0/26", "10.0.1.64/26", "10.0.2.128/25"}},
+	}
+	for _, tt := range tests {
+		p, err := New([]net.IPNet{mustCIDR(t, "10.0.0.0/22")}, WithStrategy(tt.strategy))
+		if err != nil {
+			t.Fatalf("New(%v) error = %v", tt.strategy, err)
+		}
+		for _, n := range []string{"10.0.1.128/25", "10.0.2.0/25", "10.0.3.0/24"} {
+			if err := p.Occupy(mustCIDR(t, n)); err != nil {
+				t.Fatalf("Occupy(%v) error = %v", n, err)
+			}
+		}
+		var got []string
+		for _, prefixLen := range []int{26, 26, 25} {
+			n, err := p.AllocatePrefix(prefixLen)
+			if err != nil {
+				t.Fatalf("%v: AllocatePrefix(%v) error = %v", tt.strategy, prefixLen, err)
+			}
+			got = append(got, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: AllocatePrefix() = %v, want %v", tt.strategy, got, tt.want)
+		}
+	}
+}
+
+func TestStrategyString(t *testing.T) {
+	for s, want := range map[Strategy]string{FirstFit: "first-fit", BestFit: "best-fit", Buddy: "buddy", 7: "Strategy(7)"} {
+		if got := s.String(); got != want {
+			t.Errorf("String(%d) = %v, want %v", int(s), got, want)
+		}
+	}
+}
+
+func TestRelease(t *testing.T) {
+	p := newPool(t, "10.0.0.0/24")
+	var nets []net.IPNet
+	for i := 0; i < 4; i++ {
+		n, err := p.AllocatePrefix(26)
+		if err != nil {
+			t.Fatalf("AllocatePrefix(26) error = %v", err)
+		}
+		nets = append(nets, n)
+	}
+	for _, n := range []string{"10.0.0.0/25", "10.0.1.0/26", "10.0.0.64/27"} {
+		if err := p.Release(mustCIDR(t, n)); err == nil {
+			t.Errorf("Release(%v) error = nil, want error", n)
+		}
+	}
+	releases := []struct {
+		n    net.IPNet
+		free []string
+	}{
+		{nets[1], []string{"10.0.0.64/26"}},
+		{nets[2], []string{"10.0.0.64/26", "10.0.0.128/26"}},
+		{nets[0], []string{"10.0.0.0/25", "10.0.0.128/26"}},
+		{nets[3], []string{"10.0.0.0/24"}},
+	}
+	for _, tt := range releases {
+		if err := p.Release(tt.n); err != nil {
+			t.Fatalf("Release(%v) error = %v", &tt.n, err)
+		}
+		if got := netStrings(p.Free()); !reflect.DeepEqual(got, tt.free) {
+			t.Errorf("Release(%v) Free() = %v, want %v", &tt.n, got, tt.free)
+		}
+	}
+	if err := p.Release(nets[0]); err == nil {
+		t.Errorf("Release(%v) twice error = nil, want error", &nets[0])
+	}
+	if p.Len() != 0 {
+		t.Errorf("Len() = %v, want 0", p.Len())
+	}
+	if n, err := p.AllocatePrefix(24); err != nil || n.String() != "10.0.0.0/24" {
+		t.Errorf("AllocatePrefix(24) after Release = %v, %v, want 10.0.0.0/24", &n, err)
+	}
+}
+
+func TestFragmentation(t *testing.T) {
+	p := newPool(t, "10.0.0.0/24", "10.0.1.0/24")
+	f := p.Fragmentation()
+	if f.Total.Int64() != 512 || f.Free.Int64() != 512 || f.FreeBlocks != 2 || f.Largest.String() != "10.0.0.0/24" || f.Ratio != 0.5 {
+		t.Errorf("Fragmentation() = %+v, want 512 total and free in 2 blocks, ratio 0.5", f)
+	}
+	for _, n := range []string{"10.0.0.0/26", "10.0.0.128/26", "10.0.1.0/25"} {
+		p.Occupy(mustCIDR(t, n))
+	}
+	f = p.Fragmentation()
+	if f.Free.Int64() != 256 || f.FreeBlocks != 3 || f.Largest.String() != "10.0.1.128/25" || f.Ratio != 0.5 {
+		t.Errorf("Fragmentation() = %+v, want 256 free in 3 blocks, largest 10.0.1.128/25, ratio 0.5", f)
+	}
+	p.Occupy(mustCIDR(t, "10.0.0.64/26"))
+	p.Occupy(mustCIDR(t, "10.0.0.192/26"))
+	f = p.Fragmentation()
+	if f.Free.Int64() != 128 || f.FreeBlocks != 1 || f.Ratio != 0 {
+		t.Errorf("Fragmentation() = %+v, want 128 free in 1 block, ratio 0", f)
+	}
+	p.Occupy(mustCIDR(t, "10.0.1.128/25"))
+	if f = p.Fragmentation(); f.Free.Sign() != 0 || f.FreeBlocks != 0 || f.Largest.IP != nil || f.Ratio != 0 {
+		t.Errorf("Fragmentation() = %+v, want no free space", f)
+	}
+}
+
+func allocStrings(allocs []Allocation) []string {
+	var s []string
+	for _, a := range allocs {
+		s = append(s, fmt.Sprintf("%v=%v", &a.Prefix, a.Name))
+	}
+	return s
+}
+
+func TestNamed(t *testing.T) {
+	p := newPool(t, "10.0.0.0/16")
+	prod := map[string]string{"env": "prod"}
+	a, err := p.AllocateNamed(24, "web", prod)
+	if err != nil || a.Prefix.String() != "10.0.0.0/24" || a.Name != "web" || a.Labels["env"] != "prod" {
+		t.Fatalf("AllocateNamed(24, web) = %+v, %v, want 10.0.0.0/24", a, err)
+	}
+	// Labels are copied, neither the caller's nor the returned map alias the Pool's.
+	prod["env"] = "dev"
+	a.Labels["env"] = "test"
+	if err := p.OccupyNamed(mustCIDR(t, "10.0.128.0/17"), "db", map[string]string{"env": "prod", "tier": "data"}); err != nil {
+		t.Fatalf("OccupyNamed(db) error = %v", err)
+	}
+	if _, err := p.AllocateNamed(24, "web", nil); err == nil {
+		t.Errorf("AllocateNamed(24, web) twice error = nil, want error")
+	}
+	if err := p.OccupyNamed(mustCIDR(t, "10.0.2.0/24"), "db", nil); err == nil {
+		t.Errorf("OccupyNamed(db) twice error = nil, want error")
+	}
+	if _, err := p.AllocateNamed(24, "", nil); err != nil {
+		t.Errorf("AllocateNamed(24, \"\") error = %v", err)
+	}
+	if _, err := p.AllocateNamed(24, "", nil); err != nil {
+		t.Errorf("AllocateNamed(24, \"\") twice error = %v", err)
+	}
+	if a, ok := p.Find("web"); !ok || a.Prefix.String() != "10.0.0.0/24" || a.Labels["env"] != "prod" {
+		t.Errorf("Find(web) = %+v, %v, want 10.0.0.0/24 with env=prod", a, ok)
+	}
+	if a, ok := p.Find(""); ok {
+		t.Errorf("Find(\"\") = %+v, want none", a)
+	}
+	tests := []struct {
+		filters []Filter
+		want    []string
+	}{
+		{nil, []string{"10.0.0.0/24=web", "10.0.1.0/24=", "10.0.2.0/24=", "10.0.128.0/17=db"}},
+		{[]Filter{HasLabel("env", "prod")}, []string{"10.0.0.0/24=web", "10.0.128.0/17=db"}},
+		{[]Filter{HasLabel("env", "prod"), HasLabel("tier", "data")}, []string{"10.0.128.0/17=db"}},
+		{[]Filter{HasLabel("env", "prod"), Within(mustCIDR(t, "10.0.0.0/17"))}, []string{"10.0.0.0/24=web"}},
+		{[]Filter{HasLabel("env", "")}, nil},
+	}
+	for i, tt := range tests {
+		if got := allocStrings(p.List(tt.filters...)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: List() = %v, want %v", i, got, tt.want)
+		}
+	}
+	if err := p.Release(mustCIDR(t, "10.0.0.0/24")); err != nil {
+		t.Fatalf("Release(10.0.0.0/24) error = %v", err)
+	}
+	if _, ok := p.Find("web"); ok {
+		t.Errorf("Find(web) after Release ok = true, want false")
+	}
+	if a, err := p.AllocateNamed(24, "web", nil); err != nil || a.Prefix.String() != "10.0.0.0/24" {
+		t.Errorf("AllocateNamed(24, web) after Release = %+v, %v, want 10.0.0.0/24", a, err)
+	}
+}
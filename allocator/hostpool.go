@@ -0,0 +1,205 @@
+package allocator
+
+import (
+	"fmt"
+	"iter"
+	"net"
+	"slices"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/ipset"
+)
+
+// HostPool hands out individual addresses of a subnet to clients, DHCP-style.
+// Addresses can be reserved for specific clients, and ranges excluded altogether, e.g., gateway or anycast addresses.
+// A HostPool is not safe for concurrent use.
+type HostPool struct {
+	subnet net.IPNet
+	// avail holds the addresses free for dynamic allocation, i.e., usable hosts which aren't excluded, reserved or leased.
+	avail        ipset.Set
+	excluded     ipset.Set
+	leases       map[string]net.IP
+	reservations map[string]net.IP
+	// policy overrides the default usable hosts of the subnet if set.
+	policy *ipcalc.ReservePolicy
+}
+
+// HostPoolOption configures a HostPool.
+type HostPoolOption func(*HostPool)
+
+// WithReservePolicy sets the addresses of the subnet which can't be assigned to hosts,
+// e.g., WithReservePolicy(ipcalc.ReserveAWS) for a VPC subnet.
+func WithReservePolicy(p ipcalc.ReservePolicy) HostPoolOption {
+	return func(h *HostPool) {
+		h.policy = &p
+	}
+}
+
+// NewHostPool returns a HostPool for the usable host addresses of a subnet,
+// see ipcalc.NthHost unless the WithReservePolicy option is given.
+func NewHostPool(subnet net.IPNet, opts ...HostPoolOption) (*HostPool, error) {
+	norm, ok := normalize(subnet)
+	if !ok {
+		return nil, fmt.Errorf("allocator: invalid network %v", &subnet)
+	}
+	h := &HostPool{
+		subnet:       norm,
+		leases:       make(map[string]net.IP),
+		reservations: make(map[string]net.IP),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.policy == nil {
+		h.avail.AddRange(ipcalc.Range{First: ipcalc.FirstHost(norm), Last: ipcalc.LastHost(norm)})
+		return h, nil
+	}
+	if first, ok := h.policy.FirstHost(norm); ok {
+		last, _ := h.policy.LastHost(norm)
+		h.avail.AddRange(ipcalc.Range{First: first, Last: last})
+	}
+	return h, nil
+}
+
+// Subnet returns the subnet of the HostPool.
+func (h *HostPool) Subnet() net.IPNet {
+	return h.subnet
+}
+
+// Exclude removes a range of addresses from the HostPool, it returns an error if any of them is leased or reserved.
+// Addresses outside the subnet are ignored.
+func (h *HostPool) Exclude(r ipcalc.Range) error {
+	if r.First == nil || r.Last == nil {
+		return fmt.Errorf("allocator: invalid range %v", r)
+	}
+	for _, m := range []map[string]net.IP{h.leases, h.reservations} {
+		for client, ip := range m {
+			if r.Contains(ip) {
+				return fmt.Errorf("%w: %v is assigned to %q", ErrOverlap, ip, client)
+			}
+		}
+	}
+	if err := h.avail.RemoveRange(r); err != nil {
+		return fmt.Errorf("allocator: invalid range %v", r)
+	}
+	h.excluded.AddRange(r)
+	return nil
+}
+
+// Reserve sets aside an address for a client, which gets it when calling Allocate.
+// It returns an error if the address isn't a usable host, is excluded, or is assigned to another client.
+func (h *HostPool) Reserve(client string, ip net.IP) error {
+	ip = ipcalc.IP(ip)
+	if ip == nil {
+		return fmt.Errorf("allocator: invalid address %v", ip)
+	}
+	if cur, ok := h.reservations[client]; ok {
+		if cur.Equal(ip) {
+			return nil
+		}
+		return fmt.Errorf("allocator: client %q already has %v reserved", client, cur)
+	}
+	if cur, ok := h.leases[client]; ok && !cur.Equal(ip) {
+		return fmt.Errorf("allocator: client %q already leased %v", client, cur)
+	}
+	if !h.avail.Contains(ip) && !h.leases[client].Equal(ip) {
+		return fmt.Errorf("allocator: %v not available", ip)
+	}
+	h.avail.RemoveIP(ip)
+	h.reservations[client] = ip
+	return nil
+}
+
+// Unreserve removes a client's reservation, returning whether it had one.
+// A reserved address which is leased stays leased until released.
+func (h *HostPool) Unreserve(client string) bool {
+	ip, ok := h.reservations[client]
+	if !ok {
+		return false
+	}
+	delete(h.reservations, client)
+	if !h.leases[client].Equal(ip) {
+		h.avail.AddIP(ip)
+	}
+	return true
+}
+
+// Allocate leases an address to a client: its current lease if any, else its reserved address, else the lowest free one.
+func (h *HostPool) Allocate(client string) (net.IP, error) {
+	if ip, ok := h.leases[client]; ok {
+		return ipcalc.CopyIP(ip), nil
+	}
+	ip, ok := h.reservations[client]
+	if !ok {
+		ranges := h.avail.Ranges()
+		if len(ranges) == 0 {
+			return nil, fmt.Errorf("%w: no free address in %v", ErrExhausted, &h.subnet)
+		}
+		ip = ranges[0].First
+		h.avail.RemoveIP(ip)
+	}
+	h.leases[client] = ip
+	return ipcalc.CopyIP(ip), nil
+}
+
+// Lease returns the address leased to a client, if any.
+func (h *HostPool) Lease(client string) (net.IP, bool) {
+	ip, ok := h.leases[client]
+	return ipcalc.CopyIP(ip), ok
+}
+
+// Release ends a client's lease, returning whether it had one.
+// Reserved addresses stay reserved for the client.
+func (h *HostPool) Release(client string) bool {
+	ip, ok := h.leases[client]
+	if !ok {
+		return false
+	}
+	delete(h.leases, client)
+	if !h.reservations[client].Equal(ip) {
+		h.avail.AddIP(ip)
+	}
+	return true
+}
+
+// Free returns an iterator over the addresses free for dynamic allocation, in ascending order.
+// The HostPool must not be modified during the iteration.
+func (h *HostPool) Free() iter.Seq[net.IP] {
+	return func(yield func(net.IP) bool) {
+		for _, n := range h.avail.Prefixes() {
+			for ip := range ipcalc.Addresses(n) {
+				if !yield(ip) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Used returns an iterator over the leased addresses and their clients, in ascending order of address.
+func (h *HostPool) Used() iter.Seq2[net.IP, string] {
+	return func(yield func(net.IP, string) bool) {
+		clients := make([]string, 0, len(h.leases))
+		for client := range h.leases {
+			clients = append(clients, client)
+		}
+		slices.SortFunc(clients, func(a, b string) int { return ipcalc.Compare(h.leases[a], h.leases[b]) })
+		for _, client := range clients {
+			if !yield(ipcalc.CopyIP(h.leases[client]), client) {
+				return
+			}
+		}
+	}
+}
+
+// Excluded returns the minimal list of networks excluded from the HostPool, in ascending order.
+func (h *HostPool) Excluded() []net.IPNet {
+	return h.excluded.Intersect(newSet(h.subnet)).Prefixes()
+}
+
+// newSet returns a Set with the addresses of a network.
+func newSet(n net.IPNet) *ipset.Set {
+	s := &ipset.Set{}
+	s.AddPrefix(n)
+	return s
+}
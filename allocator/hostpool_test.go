@@ -0,0 +1,180 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func mustRange(t *testing.T, s string) ipcalc.Range {
+	t.Helper()
+	r, err := ipcalc.ParseRange(s)
+	if err != nil {
+		t.Fatalf("ParseRange(%v) error = %v", s, err)
+	}
+	return r
+}
+
+func newHostPool(t *testing.T, subnet string) *HostPool {
+	t.Helper()
+	h, err := NewHostPool(mustCIDR(t, subnet))
+	if err != nil {
+		t.Fatalf("NewHostPool(%v) error = %v", subnet, err)
+	}
+	return h
+}
+
+func freeStrings(h *HostPool) []string {
+	var s []string
+	for ip := range h.Free() {
+		s = append(s, ip.String())
+	}
+	return s
+}
+
+func usedStrings(h *HostPool) []string {
+	var s []string
+	for ip, client := range h.Used() {
+		s = append(s, fmt.Sprintf("%v=%v", ip, client))
+	}
+	return s
+}
+
+func TestHostPool(t *testing.T) {
+	h := newHostPool(t, "192.0.2.0/29")
+	if err := h.Exclude(mustRange(t, "192.0.2.1-192.0.2.1")); err != nil {
+		t.Fatalf("Exclude() error = %v", err)
+	}
+	if err := h.Reserve("printer", net.ParseIP("192.0.2.6")); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	want := []string{"192.0.2.2", "192.0.2.3", "192.0.2.4", "192.0.2.5"}
+	if got := freeStrings(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("Free() = %v, want %v", got, want)
+	}
+	allocate := func(client, want string) {
+		t.Helper()
+		ip, err := h.Allocate(client)
+		if err != nil || ip.String() != want {
+			t.Errorf("Allocate(%v) = %v, %v, want %v", client, ip, err, want)
+		}
+	}
+	allocate("a", "192.0.2.2")
+	allocate("b", "192.0.2.3")
+	allocate("printer", "192.0.2.6")
+	allocate("a", "192.0.2.2")
+	if !h.Release("a") || h.Release("a") {
+		t.Errorf("Release(a) twice = false, true, want true, false")
+	}
+	allocate("c", "192.0.2.2")
+	allocate("d", "192.0.2.4")
+	allocate("e", "192.0.2.5")
+	if _, err := h.Allocate("f"); !errors.Is(err, ErrExhausted) {
+		t.Errorf("Allocate(f) error = %v, want %v", err, ErrExhausted)
+	}
+	want = []string{"192.0.2.2=c", "192.0.2.3=b", "192.0.2.4=d", "192.0.2.5=e", "192.0.2.6=printer"}
+	if got := usedStrings(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("Used() = %v, want %v", got, want)
+	}
+	// Releasing a reserved address keeps it for its client.
+	h.Release("printer")
+	if ip, err := h.Allocate("f"); err == nil {
+		t.Errorf("Allocate(f) = %v, want error", ip)
+	}
+	allocate("printer", "192.0.2.6")
+	if ip, ok := h.Lease("printer"); !ok || ip.String() != "192.0.2.6" {
+		t.Errorf("Lease(printer) = %v, %v, want 192.0.2.6", ip, ok)
+	}
+	// Unreserving a leased address keeps the lease until released.
+	if !h.Unreserve("printer") || h.Unreserve("printer") {
+		t.Errorf("Unreserve(printer) twice = false, true, want true, false")
+	}
+	h.Release("printer")
+	if got := freeStrings(h); !reflect.DeepEqual(got, []string{"192.0.2.6"}) {
+		t.Errorf("Free() = %v, want [192.0.2.6]", got)
+	}
+	if got := netStrings(h.Excluded()); !reflect.DeepEqual(got, []string{"192.0.2.1/32"}) {
+		t.Errorf("Excluded() = %v, want [192.0.2.1/32]", got)
+	}
+}
+
+func TestHostPoolReservePolicy(t *testing.T) {
+	tests := []struct {
+		p      ipcalc.ReservePolicy
+		subnet string
+		want   []string
+	}{
+		{ipcalc.ReserveAWS, "10.0.0.0/29", []string{"10.0.0.4", "10.0.0.5", "10.0.0.6"}},
+		{ipcalc.ReserveAzure, "10.0.0.8/29", []string{"10.0.0.12", "10.0.0.13", "10.0.0.14"}},
+		{ipcalc.ReserveGCP, "10.0.0.0/29", []string{"10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5"}},
+		{ipcalc.ReserveGCP, "10.0.0.0/30", nil},
+		{ipcalc.ReserveNone, "192.0.2.0/30", []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3"}},
+	}
+	for _, tt := range tests {
+		h, err := NewHostPool(mustCIDR(t, tt.subnet), WithReservePolicy(tt.p))
+		if err != nil {
+			t.Fatalf("NewHostPool(%v, %v) error = %v", tt.subnet, tt.p.Name, err)
+		}
+		if got := freeStrings(h); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewHostPool(%v, %v).Free() = %v, want %v", tt.subnet, tt.p.Name, got, tt.want)
+		}
+	}
+	h, err := NewHostPool(mustCIDR(t, "10.0.0.0/24"), WithReservePolicy(ipcalc.ReserveAWS))
+	if err != nil {
+		t.Fatalf("NewHostPool() error = %v", err)
+	}
+	if err := h.Reserve("dns", net.ParseIP("10.0.0.2")); err == nil {
+		t.Errorf("Reserve(10.0.0.2) error = nil, want error")
+	}
+	if ip, err := h.Allocate("a"); err != nil || !ip.Equal(net.ParseIP("10.0.0.4")) {
+		t.Errorf("Allocate(a) = %v, %v, want 10.0.0.4", ip, err)
+	}
+}
+
+func TestHostPoolErrors(t *testing.T) {
+	if _, err := NewHostPool(net.IPNet{}); err == nil {
+		t.Errorf("NewHostPool(invalid) error = nil, want error")
+	}
+	h := newHostPool(t, "2001:db8::/126")
+	h.Reserve("a", net.ParseIP("2001:db8::1"))
+	h.Allocate("b")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"reserve taken", h.Reserve("c", net.ParseIP("2001:db8::1"))},
+		{"reserve leased", h.Reserve("c", net.ParseIP("2001:db8::"))},
+		{"reserve twice", h.Reserve("a", net.ParseIP("2001:db8::2"))},
+		{"reserve leased client", h.Reserve("b", net.ParseIP("2001:db8::2"))},
+		{"reserve outside", h.Reserve("c", net.ParseIP("2001:db8::4"))},
+		{"reserve invalid", h.Reserve("c", nil)},
+		{"exclude reserved", h.Exclude(mustRange(t, "2001:db8::1-2001:db8::2"))},
+		{"exclude leased", h.Exclude(mustRange(t, "2001:db8::-2001:db8::"))},
+		{"exclude invalid", h.Exclude(ipcalc.Range{})},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%v: error = nil, want error", tt.name)
+		}
+	}
+	if err := h.Reserve("a", net.ParseIP("2001:db8::1")); err != nil {
+		t.Errorf("Reserve(a) same address error = %v", err)
+	}
+	if err := h.Reserve("b", net.ParseIP("2001:db8::")); err != nil {
+		t.Errorf("Reserve(b) leased address error = %v", err)
+	}
+	if err := h.Exclude(mustRange(t, "2001:db8::3-2001:db8::10")); err != nil {
+		t.Errorf("Exclude() error = %v", err)
+	}
+	want := []string{"2001:db8::2"}
+	if got := freeStrings(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("Free() = %v, want %v", got, want)
+	}
+	if got := netStrings(h.Excluded()); !reflect.DeepEqual(got, []string{"2001:db8::3/128"}) {
+		t.Errorf("Excluded() = %v, want [2001:db8::3/128]", got)
+	}
+}
@@ -0,0 +1,135 @@
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// Member is a Pool in a MultiPool and the constraints on allocating from it.
+type Member struct {
+	// Name identifies the Pool in MultiPool results, it must be unique.
+	Name string
+	Pool *Pool
+	// MinPrefixLen and MaxPrefixLen bound the prefix lengths allocated from the Pool, zero means unbounded.
+	MinPrefixLen, MaxPrefixLen int
+	// MaxAllocations bounds the number of allocations in the Pool, zero means unbounded.
+	MaxAllocations int
+}
+
+// allows returns whether a subnet with the given prefix length can be allocated from the Member.
+func (m Member) allows(prefixLen int) bool {
+	return (m.MinPrefixLen == 0 || prefixLen >= m.MinPrefixLen) &&
+		(m.MaxPrefixLen == 0 || prefixLen <= m.MaxPrefixLen) &&
+		(m.MaxAllocations == 0 || m.Pool.Len() < m.MaxAllocations)
+}
+
+// MultiPool allocates subnets from an ordered list of Pools, falling back to the next Pool when one is exhausted
+// or its constraints don't allow the request, e.g., preferring 10.64.0.0/10 over 100.64.0.0/10.
+// Allocation names are unique across all Pools.
+// A MultiPool is safe for concurrent use, as long as its Pools aren't used directly meanwhile unless they're Synchronized.
+type MultiPool struct {
+	members []Member
+	// mu serializes MultiPool methods, making checks across Pools atomic.
+	mu sync.Mutex
+}
+
+// NewMultiPool returns a MultiPool allocating from the Members in order, their parent networks must not overlap.
+func NewMultiPool(members ...Member) (*MultiPool, error) {
+	names := make(map[string]bool)
+	var parents []net.IPNet
+	for _, m := range members {
+		if m.Pool == nil {
+			return nil, fmt.Errorf("allocator: pool %q is nil", m.Name)
+		}
+		if names[m.Name] {
+			return nil, fmt.Errorf("allocator: duplicate pool name %q", m.Name)
+		}
+		names[m.Name] = true
+		for _, n := range m.Pool.Parents() {
+			for _, x := range parents {
+				if ipcalc.Overlaps(n, x) {
+					return nil, fmt.Errorf("allocator: pool %q parent network %v overlaps %v", m.Name, &n, &x)
+				}
+			}
+			parents = append(parents, n)
+		}
+	}
+	return &MultiPool{members: slices.Clone(members)}, nil
+}
+
+// AllocatePrefix allocates a subnet with the given prefix length from the first Pool able to satisfy the request,
+// returning the Pool's name.
+func (mp *MultiPool) AllocatePrefix(prefixLen int) (net.IPNet, string, error) {
+	a, pool, err := mp.AllocateNamed(prefixLen, "", nil)
+	return a.Prefix, pool, err
+}
+
+// AllocateNamed is like AllocatePrefix but records a name, which must be unique across Pools unless empty, and labels.
+func (mp *MultiPool) AllocateNamed(prefixLen int, name string, labels map[string]string) (Allocation, string, error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	if a, pool, ok := mp.find(name); ok {
+		return Allocation{}, "", fmt.Errorf("allocator: name %q already allocated %v in pool %q", name, &a.Prefix, pool)
+	}
+	for _, m := range mp.members {
+		if !m.allows(prefixLen) {
+			continue
+		}
+		a, err := m.Pool.AllocateNamed(prefixLen, name, labels)
+		switch {
+		case err == nil:
+			return a, m.Name, nil
+		case !errors.Is(err, ErrExhausted):
+			return Allocation{}, "", err
+		}
+	}
+	return Allocation{}, "", fmt.Errorf("%w: no pool can allocate a /%v subnet", ErrExhausted, prefixLen)
+}
+
+// Release returns an allocated subnet to the Pool it was allocated from, returning the Pool's name.
+func (mp *MultiPool) Release(n net.IPNet) (string, error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	norm, ok := normalize(n)
+	if !ok {
+		return "", fmt.Errorf("allocator: invalid network %v", &n)
+	}
+	for _, m := range mp.members {
+		if m.Pool.parentOf(norm) != nil {
+			return m.Name, m.Pool.Release(norm)
+		}
+	}
+	return "", fmt.Errorf("allocator: %v not in any pool", &n)
+}
+
+// Find returns the Allocation with the given name and the name of its Pool.
+func (mp *MultiPool) Find(name string) (Allocation, string, bool) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	return mp.find(name)
+}
+
+func (mp *MultiPool) find(name string) (Allocation, string, bool) {
+	for _, m := range mp.members {
+		if a, ok := m.Pool.Find(name); ok {
+			return a, m.Name, true
+		}
+	}
+	return Allocation{}, "", false
+}
+
+// List returns the Allocations selected by every Filter, grouped by Pool in fallback order.
+func (mp *MultiPool) List(filters ...Filter) []Allocation {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	var out []Allocation
+	for _, m := range mp.members {
+		out = append(out, m.Pool.List(filters...)...)
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package allocator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMultiPool(t *testing.T) {
+	mp, err := NewMultiPool(
+		Member{Name: "primary", Pool: newPool(t, "10.64.0.0/22"), MaxPrefixLen: 24},
+		Member{Name: "fallback", Pool: newPool(t, "100.64.0.0/22"), MaxAllocations: 2},
+		Member{Name: "small", Pool: newPool(t, "192.0.2.0/24"), MinPrefixLen: 26},
+	)
+	if err != nil {
+		t.Fatalf("NewMultiPool() error = %v", err)
+	}
+	tests := []struct {
+		prefixLen int
+		want      string
+		pool      string
+	}{
+		{23, "10.64.0.0/23", "primary"},
+		{23, "10.64.2.0/23", "primary"},
+		{24, "100.64.0.0/24", "fallback"},
+		{26, "100.64.1.0/26", "fallback"},
+		{26, "192.0.2.0/26", "small"},
+		{24, "", ""},
+		{22, "", ""},
+	}
+	for _, tt := range tests {
+		n, pool, err := mp.AllocatePrefix(tt.prefixLen)
+		if tt.want == "" {
+			if !errors.Is(err, ErrExhausted) {
+				t.Errorf("AllocatePrefix(%v) = %v, %v, %v, want %v", tt.prefixLen, &n, pool, err, ErrExhausted)
+			}
+			continue
+		}
+		if err != nil || n.String() != tt.want || pool != tt.pool {
+			t.Errorf("AllocatePrefix(%v) = %v, %v, %v, want %v, %v", tt.prefixLen, &n, pool, err, tt.want, tt.pool)
+		}
+	}
+	if pool, err := mp.Release(mustCIDR(t, "10.64.2.0/23")); err != nil || pool != "primary" {
+		t.Errorf("Release(10.64.2.0/23) = %v, %v, want primary", pool, err)
+	}
+	if _, err := mp.Release(mustCIDR(t, "10.64.2.0/23")); err == nil {
+		t.Errorf("Release(10.64.2.0/23) twice error = nil, want error")
+	}
+	if _, err := mp.Release(mustCIDR(t, "198.51.100.0/24")); err == nil {
+		t.Errorf("Release(198.51.100.0/24) error = nil, want error")
+	}
+	a, pool, err := mp.AllocateNamed(24, "eu", map[string]string{"region": "eu"})
+	if err != nil || a.Prefix.String() != "10.64.2.0/24" || pool != "primary" {
+		t.Errorf("AllocateNamed(24, eu) = %v, %v, %v, want 10.64.2.0/24, primary", &a.Prefix, pool, err)
+	}
+	// Names are unique across pools.
+	if _, _, err := mp.AllocateNamed(27, "eu", nil); err == nil {
+		t.Errorf("AllocateNamed(27, eu) twice error = nil, want error")
+	}
+	if a, pool, ok := mp.Find("eu"); !ok || a.Prefix.String() != "10.64.2.0/24" || pool != "primary" {
+		t.Errorf("Find(eu) = %v, %v, %v, want 10.64.2.0/24, primary", &a.Prefix, pool, ok)
+	}
+	want := []string{"10.64.0.0/23=", "10.64.2.0/24=eu", "100.64.0.0/24=", "100.64.1.0/26=", "192.0.2.0/26="}
+	if got := allocStrings(mp.List()); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	if got := allocStrings(mp.List(HasLabel("region", "eu"))); !reflect.DeepEqual(got, []string{"10.64.2.0/24=eu"}) {
+		t.Errorf("List(region=eu) = %v, want [10.64.2.0/24=eu]", got)
+	}
+	if _, _, err := mp.AllocatePrefix(-1); err == nil || errors.Is(err, ErrExhausted) {
+		t.Errorf("AllocatePrefix(-1) error = %v, want invalid prefix length", err)
+	}
+}
+
+func TestNewMultiPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []Member
+	}{
+		{"nil pool", []Member{{Name: "a"}}},
+		{"duplicate name", []Member{{Name: "a", Pool: newPool(t, "10.0.0.0/8")}, {Name: "a", Pool: newPool(t, "192.0.2.0/24")}}},
+		{"overlap", []Member{{Name: "a", Pool: newPool(t, "10.0.0.0/8")}, {Name: "b", Pool: newPool(t, "10.1.0.0/16")}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewMultiPool(tt.members...); err == nil {
+			t.Errorf("NewMultiPool(%v) error = nil, want error", tt.name)
+		}
+	}
+	if _, err := NewMultiPool(Member{Name: "a", Pool: newPool(t, "10.0.0.0/8")}, Member{Name: "b", Pool: newPool(t, "2001:db8::/32")}); err != nil {
+		t.Errorf("NewMultiPool() error = %v", err)
+	}
+}
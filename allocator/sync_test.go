@@ -0,0 +1,90 @@
+package allocator
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func TestSynchronized(t *testing.T) {
+	p, err := New([]net.IPNet{mustCIDR(t, "10.0.0.0/16")}, Synchronized(), WithStrategy(BestFit))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	const workers, perWorker = 8, 64
+	results := make(chan net.IPNet, workers*perWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				n, err := p.AllocateNamed(26, fmt.Sprintf("w%v-%v", i, j), nil)
+				if err != nil {
+					t.Errorf("AllocateNamed() error = %v", err)
+					return
+				}
+				// Release every other allocation to exercise coalescing concurrently.
+				if j%2 == 1 {
+					if err := p.Release(n.Prefix); err != nil {
+						t.Errorf("Release(%v) error = %v", &n.Prefix, err)
+					}
+					continue
+				}
+				results <- n.Prefix
+				p.Free()
+				p.List()
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+	var nets []net.IPNet
+	for n := range results {
+		nets = append(nets, n)
+	}
+	if got, want := p.Len(), workers*perWorker/2; got != want || len(nets) != want {
+		t.Fatalf("Len() = %v with %v results, want %v", got, len(nets), want)
+	}
+	ipcalc.SortNets(nets)
+	for i := 1; i < len(nets); i++ {
+		if ipcalc.Overlaps(nets[i-1], nets[i]) {
+			t.Errorf("allocations %v and %v overlap", &nets[i-1], &nets[i])
+		}
+	}
+}
+
+func TestMultiPoolConcurrent(t *testing.T) {
+	mp, err := NewMultiPool(
+		Member{Name: "a", Pool: newPool(t, "10.0.0.0/24")},
+		Member{Name: "b", Pool: newPool(t, "10.1.0.0/24")},
+	)
+	if err != nil {
+		t.Fatalf("NewMultiPool() error = %v", err)
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	count := map[string]int{}
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				_, pool, err := mp.AllocateNamed(28, "", nil)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				count[pool]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if count["a"] != 16 || count["b"] != 16 {
+		t.Errorf("allocations per pool = %v, want 16 each", count)
+	}
+}
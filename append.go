@@ -0,0 +1,70 @@
+package ipcalc
+
+import (
+	"encoding/hex"
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// AppendIP appends the textual form of an IP address to dst and returns the extended buffer.
+// The output is identical to ip.String(), but no intermediate string is allocated.
+// e.g., AppendIP([]byte("ip="), 192.0.2.1) -> "ip=192.0.2.1".
+func AppendIP(dst []byte, ip net.IP) []byte {
+	if len(ip) == 0 {
+		return append(dst, "<nil>"...)
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return hex.AppendEncode(append(dst, '?'), ip)
+	}
+	return addr.Unmap().AppendTo(dst)
+}
+
+// AppendNet appends the textual form of an IPNet to dst and returns the extended buffer.
+// The output is identical to n.String(), i.e., ip/len for CIDR masks and ip/hexmask otherwise.
+// e.g., AppendNet(nil, 192.0.2.0/24) -> "192.0.2.0/24".
+func AppendNet(dst []byte, n net.IPNet) []byte {
+	// Mirror net.IPNet.String, mismatched address and mask lengths are printed as "<nil>".
+	ip := n.IP.To4()
+	if ip == nil {
+		if ip = n.IP; len(ip) != net.IPv6len {
+			return append(dst, "<nil>"...)
+		}
+	}
+	mask := n.Mask
+	switch {
+	case len(mask) == net.IPv6len && len(ip) == net.IPv4len:
+		mask = mask[net.IPv6len-net.IPv4len:]
+	case len(mask) != len(ip) && len(mask) != net.IPv6len:
+		return append(dst, "<nil>"...)
+	}
+	dst = append(AppendIP(dst, ip), '/')
+	if ones, bits := mask.Size(); bits != 0 {
+		return strconv.AppendInt(dst, int64(ones), 10)
+	}
+	return hex.AppendEncode(dst, mask)
+}
+
+// AppendBinary appends the dotted binary form of an IP address to dst and returns the extended buffer.
+// IPv4 addresses are grouped by octet and separated by '.', IPv6 addresses are grouped by hextet and separated by ':'.
+// e.g., AppendBinary(nil, 192.0.2.1) -> "11000000.00000000.00000010.00000001".
+func AppendBinary(dst []byte, ip net.IP) []byte {
+	ip = ip.To16()
+	if ip == nil {
+		return append(dst, "<nil>"...)
+	}
+	sep, group := byte(':'), 2
+	if x := ip.To4(); x != nil {
+		ip, sep, group = x, '.', 1
+	}
+	for i, b := range ip {
+		if i > 0 && i%group == 0 {
+			dst = append(dst, sep)
+		}
+		for j := 7; j >= 0; j-- {
+			dst = append(dst, '0'+b>>uint(j)&1)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,96 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAppendIP(t *testing.T) {
+	tests := []net.IP{
+		net.ParseIP("192.0.2.1"),
+		net.ParseIP("::ffff:192.0.2.1"),
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("::"),
+		net.IP{192, 0, 2, 1},
+		net.IP{1, 2, 3},
+		nil,
+	}
+	for _, ip := range tests {
+		if got, want := string(AppendIP([]byte("ip="), ip)), "ip="+ip.String(); got != want {
+			t.Errorf("AppendIP(%v) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestAppendNet(t *testing.T) {
+	tests := []net.IPNet{
+		{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(24, 32)},
+		{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(120, 128)},
+		{IP: net.IP{192, 0, 2, 0}, Mask: ParseMask("255.0.255.0")},
+		{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(64, 128)},
+		{IP: net.ParseIP("2001:db8::"), Mask: net.IPMask(net.ParseIP("ffff::ffff"))},
+		{IP: net.ParseIP("2001:db8::")},
+		{},
+		{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)},
+		{IP: net.IP{1, 2, 3, 4}, Mask: net.IPMask{1, 2, 3}},
+		{IP: net.IP{1, 2, 3}, Mask: net.CIDRMask(24, 32)},
+		{IP: net.IP{1, 2, 3, 4}, Mask: net.IPMask(net.ParseIP("ffff::ffff"))},
+		{IP: net.ParseIP("2001:db8::"), Mask: net.IPMask{1, 2, 3}},
+	}
+	for _, n := range tests {
+		if got, want := string(AppendNet(nil, n)), n.String(); got != want {
+			t.Errorf("AppendNet(%v) = %v, want %v", want, got, want)
+		}
+	}
+}
+
+func TestAppendBinary(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.1":        "11000000.00000000.00000010.00000001",
+		"::ffff:192.0.2.1": "11000000.00000000.00000010.00000001",
+		"2001:db8::ff":     "0010000000000001:0000110110111000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000011111111",
+		"invalid":          "<nil>",
+	}
+	for ip, want := range tests {
+		if got := string(AppendBinary(nil, net.ParseIP(ip))); got != want {
+			t.Errorf("AppendBinary(%v) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+var (
+	benchIP  = net.ParseIP("2001:db8::1")
+	benchNet = net.IPNet{IP: net.ParseIP("192.0.2.0").To4(), Mask: net.CIDRMask(24, 32)}
+)
+
+func BenchmarkAppendIP(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = AppendIP(buf[:0], benchIP)
+	}
+}
+
+func BenchmarkIPString(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = append(buf[:0], benchIP.String()...)
+	}
+}
+
+func BenchmarkAppendNet(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = AppendNet(buf[:0], benchNet)
+	}
+}
+
+func BenchmarkNetString(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = append(buf[:0], benchNet.String()...)
+	}
+}
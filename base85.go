@@ -0,0 +1,62 @@
+package ipcalc
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"net"
+	"strings"
+)
+
+// base85Alphabet is the RFC 1924 digit set, in ascending order.
+const base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
+// base85Len is the length of an encoded IPv6 address.
+const base85Len = 20
+
+// EncodeBase85 returns the RFC 1924 compact representation of an IPv6 address.
+// IPv4 addresses are encoded in their IPv4-mapped form, an invalid IP address returns an empty string.
+// e.g., EncodeBase85(1080::8:800:200c:417a) -> "4)+k&C#VzJ4br>0wv%Yp".
+func EncodeBase85(ip net.IP) string {
+	ip = ip.To16()
+	if ip == nil {
+		return ""
+	}
+	hi := binary.BigEndian.Uint64(ip[:8])
+	lo := binary.BigEndian.Uint64(ip[8:])
+	var b [base85Len]byte
+	for i := base85Len - 1; i >= 0; i-- {
+		var r uint64
+		hi, r = bits.Div64(0, hi, 85)
+		lo, r = bits.Div64(r, lo, 85)
+		b[i] = base85Alphabet[r]
+	}
+	return string(b[:])
+}
+
+// DecodeBase85 parses an RFC 1924 compact representation into an IPv6 address.
+// e.g., DecodeBase85("4)+k&C#VzJ4br>0wv%Yp") -> 1080::8:800:200c:417a.
+func DecodeBase85(s string) (net.IP, error) {
+	if len(s) != base85Len {
+		return nil, &net.ParseError{Type: "base85 IP address", Text: s}
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base85Alphabet, s[i])
+		if d < 0 {
+			return nil, &net.ParseError{Type: "base85 IP address", Text: s}
+		}
+		// (hi, lo) = (hi, lo) * 85 + d, rejecting anything over 128 bits.
+		overflow, hiLo := bits.Mul64(hi, 85)
+		carry, loLo := bits.Mul64(lo, 85)
+		var c uint64
+		lo, c = bits.Add64(loLo, uint64(d), 0)
+		hi, c = bits.Add64(hiLo, carry, c)
+		if overflow != 0 || c != 0 {
+			return nil, &net.ParseError{Type: "base85 IP address", Text: s}
+		}
+	}
+	ip := make(net.IP, net.IPv6len)
+	binary.BigEndian.PutUint64(ip[:8], hi)
+	binary.BigEndian.PutUint64(ip[8:], lo)
+	return ip, nil
+}
@@ -0,0 +1,52 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestEncodeBase85(t *testing.T) {
+	tests := map[string]string{
+		"1080::8:800:200c:417a": "4)+k&C#VzJ4br>0wv%Yp",
+		"::":                    "00000000000000000000",
+		"::1":                   "00000000000000000001",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff": "=r54lj&NUUO~Hi%c2ym0",
+		"192.0.2.1": EncodeBase85(net.ParseIP("::ffff:192.0.2.1")),
+		"invalid":   "",
+	}
+	for ip, want := range tests {
+		if got := EncodeBase85(net.ParseIP(ip)); got != want {
+			t.Errorf("EncodeBase85(%v) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestDecodeBase85(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+		ok   bool
+	}{
+		{"4)+k&C#VzJ4br>0wv%Yp", "1080::8:800:200c:417a", true},
+		{"00000000000000000000", "::", true},
+		{"=r54lj&NUUO~Hi%c2ym0", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", true},
+		{"=r54lj&NUUO~Hi%c2ym1", "", false},
+		{"~~~~~~~~~~~~~~~~~~~~", "", false},
+		{"4)+k&C#VzJ4br>0wv%Y", "", false},
+		{"4)+k&C#VzJ4br>0wv%Y\"", "", false},
+	}
+	for _, tt := range tests {
+		got, err := DecodeBase85(tt.s)
+		if err != nil {
+			if tt.ok {
+				t.Errorf("DecodeBase85(%v) error = %v", tt.s, err)
+			}
+			continue
+		}
+		if !tt.ok {
+			t.Errorf("DecodeBase85(%v) error = nil", tt.s)
+		} else if !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("DecodeBase85(%v) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ipcalc
+
+import "net"
+
+// AndAll returns the bitwise AND of each IP address with mask, see And.
+// Results share a single backing array to amortize allocations over the batch.
+// e.g., AndAll([192.0.2.1, 192.0.3.1], 255.255.254.0) -> [192.0.2.0 192.0.2.0].
+func AndAll(ips []net.IP, mask net.IP) []net.IP {
+	mask = canonical(mask)
+	out := make([]net.IP, len(ips))
+	buf := make([]byte, 0, batchSize(ips))
+	for i, ip := range ips {
+		ip = canonical(ip)
+		start := len(buf)
+		buf = append(buf, ip...)
+		r := buf[start:len(buf):len(buf)]
+		for j := 0; j < len(r) && j < len(mask); j++ {
+			r[j] &= mask[j]
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// MaskAll returns the result of masking each IP address with mask, like net.IP.Mask.
+// Entries are nil for addresses whose length doesn't match the mask,
+// results share a single backing array to amortize allocations over the batch.
+// e.g., MaskAll([192.0.2.1, 2001:db8::1], 255.255.255.0) -> [192.0.2.0 <nil>].
+func MaskAll(ips []net.IP, mask net.IPMask) []net.IP {
+	out := make([]net.IP, len(ips))
+	buf := make([]byte, 0, batchSize(ips))
+	for i, ip := range ips {
+		m := mask
+		switch {
+		case len(m) == net.IPv6len && len(ip) == net.IPv4len && allFF(m[:net.IPv6len-net.IPv4len]):
+			m = m[net.IPv6len-net.IPv4len:]
+		case len(m) == net.IPv4len && len(ip) == net.IPv6len:
+			ip = ip.To4()
+		}
+		if len(m) != len(ip) {
+			continue
+		}
+		start := len(buf)
+		buf = append(buf, ip...)
+		r := buf[start:len(buf):len(buf)]
+		for j := range r {
+			r[j] &= m[j]
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// ContainsAny returns whether any of the networks contains an IP address.
+// e.g., ContainsAny(192.0.2.1, [10.0.0.0/8 192.0.2.0/24]) -> true.
+func ContainsAny(ip net.IP, nets []net.IPNet) bool {
+	ip = canonical(ip)
+	for i := range nets {
+		if nets[i].Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// batchSize returns the number of bytes needed to hold every IP address in canonical form.
+func batchSize(ips []net.IP) int {
+	n := 0
+	for _, ip := range ips {
+		n += len(canonical(ip))
+	}
+	return n
+}
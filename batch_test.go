@@ -0,0 +1,109 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func parseIPs(ss ...string) []net.IP {
+	ips := make([]net.IP, len(ss))
+	for i, s := range ss {
+		ips[i] = net.ParseIP(s)
+	}
+	return ips
+}
+
+func ipStrings(ips []net.IP) []string {
+	s := make([]string, len(ips))
+	for i, ip := range ips {
+		s[i] = ip.String()
+	}
+	return s
+}
+
+func TestAndAll(t *testing.T) {
+	ips := parseIPs("192.0.2.1", "192.0.3.1", "::ffff:10.1.2.3", "2001:db8::1")
+	got := AndAll(ips, net.ParseIP("255.255.254.0"))
+	want := []string{"192.0.2.0", "192.0.2.0", "10.1.2.0", "2001:c00::1"}
+	if !reflect.DeepEqual(ipStrings(got), want) {
+		t.Errorf("AndAll(%v) = %v, want %v", ips, got, want)
+	}
+	for i, ip := range ips {
+		if want := And(ip, net.ParseIP("255.255.254.0")); !got[i].Equal(want) {
+			t.Errorf("AndAll(%v)[%v] = %v, want And() = %v", ips, i, got[i], want)
+		}
+	}
+	// Results mustn't alias each other.
+	got[0][0] = 1
+	if got[1][0] != 192 {
+		t.Errorf("AndAll() results share memory")
+	}
+}
+
+func TestMaskAll(t *testing.T) {
+	ips := append(parseIPs("192.0.2.1", "2001:db8::1"), net.IP{10, 1, 2, 3})
+	tests := []struct {
+		mask net.IPMask
+		want []string
+	}{
+		{net.CIDRMask(24, 32), []string{"192.0.2.0", "<nil>", "10.1.2.0"}},
+		{net.CIDRMask(120, 128), []string{"192.0.2.0", "2001:db8::", "10.1.2.0"}},
+		{net.CIDRMask(32, 128), []string{"::", "2001:db8::", "<nil>"}},
+		{net.CIDRMask(24, 32)[:3], []string{"<nil>", "<nil>", "<nil>"}},
+	}
+	for _, tt := range tests {
+		got := MaskAll(ips, tt.mask)
+		if !reflect.DeepEqual(ipStrings(got), tt.want) {
+			t.Errorf("MaskAll(%v, %v) = %v, want %v", ips, tt.mask, got, tt.want)
+		}
+		for i, ip := range ips {
+			if want := ip.Mask(tt.mask); !got[i].Equal(want) {
+				t.Errorf("MaskAll(%v, %v)[%v] = %v, want net.IP.Mask() = %v", ips, tt.mask, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestContainsAny(t *testing.T) {
+	nets := []net.IPNet{mustCIDR(t, "10.0.0.0/8"), mustCIDR(t, "192.0.2.0/24"), mustCIDR(t, "2001:db8::/32")}
+	tests := map[string]bool{
+		"192.0.2.1":        true,
+		"::ffff:10.1.2.3":  true,
+		"2001:db8::1":      true,
+		"198.51.100.1":     false,
+		"2001:db9::1":      false,
+		"::ffff:192.0.3.1": false,
+	}
+	for ip, want := range tests {
+		if got := ContainsAny(net.ParseIP(ip), nets); got != want {
+			t.Errorf("ContainsAny(%v) = %v, want %v", ip, got, want)
+		}
+	}
+	if ContainsAny(net.ParseIP("192.0.2.1"), nil) {
+		t.Errorf("ContainsAny(192.0.2.1, nil) = true, want false")
+	}
+}
+
+func BenchmarkAndAll(b *testing.B) {
+	ips := make([]net.IP, 1024)
+	for i := range ips {
+		ips[i] = net.IPv4(10, 0, byte(i>>8), byte(i))
+	}
+	mask := net.ParseIP("255.255.254.0")
+	b.Run("AndAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			AndAll(ips, mask)
+		}
+	})
+	b.Run("And", func(b *testing.B) {
+		b.ReportAllocs()
+		out := make([]net.IP, len(ips))
+		for i := 0; i < b.N; i++ {
+			for j, ip := range ips {
+				out[j] = And(ip, mask)
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+)
+
+// AddInt returns an IP address plus an integer offset, wrapping around the address space, or nil if it's invalid.
+// e.g., AddInt(192.0.2.250, 10) -> 192.0.3.4.
+func AddInt(ip net.IP, n int64) net.IP {
+	return AddBig(ip, big.NewInt(n))
+}
+
+// SubInt returns an IP address minus an integer offset, wrapping around the address space, or nil if it's invalid.
+// e.g., SubInt(192.0.3.4, 10) -> 192.0.2.250.
+func SubInt(ip net.IP, n int64) net.IP {
+	return SubBig(ip, big.NewInt(n))
+}
+
+// AddBig returns an IP address plus an arbitrary offset, wrapping around the address space, or nil if it's invalid.
+// e.g., AddBig(2001:db8::, 2^64) -> 2001:db8:0:1::.
+func AddBig(ip net.IP, n *big.Int) net.IP {
+	ip = canonical(ip)
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil
+	}
+	v := IPToBig(ip)
+	return intToIP(v.Add(v, n), len(ip))
+}
+
+// SubBig returns an IP address minus an arbitrary offset, wrapping around the address space, or nil if it's invalid.
+// e.g., SubBig(2001:db8:0:1::, 2^64) -> 2001:db8::.
+func SubBig(ip net.IP, n *big.Int) net.IP {
+	ip = canonical(ip)
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil
+	}
+	v := IPToBig(ip)
+	return intToIP(v.Sub(v, n), len(ip))
+}
+
+// Delta returns the signed distance from a to b, i.e., b - a,
+// or nil if either address is invalid or the addresses are from different families.
+// e.g., Delta(192.0.2.250, 192.0.3.4) -> 10.
+func Delta(a, b net.IP) *big.Int {
+	a = IP(a)
+	b = IP(b)
+	if len(a) != len(b) || len(a) != net.IPv4len && len(a) != net.IPv6len {
+		return nil
+	}
+	d := IPToBig(b)
+	return d.Sub(d, IPToBig(a))
+}
+
+// intToIP returns the IP address of the given byte length for a numeric value, modulo the address space size.
+func intToIP(v *big.Int, size int) net.IP {
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(8*size))
+	return new(big.Int).Mod(v, mod).FillBytes(make(net.IP, size))
+}
@@ -0,0 +1,114 @@
+package ipcalc
+
+import (
+	"math"
+	"math/big"
+	"net"
+	"testing"
+)
+
+func TestAddSubInt(t *testing.T) {
+	tests := []struct {
+		ip   string
+		n    int64
+		want string
+	}{
+		{"192.0.2.250", 10, "192.0.3.4"},
+		{"192.0.2.1", 0, "192.0.2.1"},
+		{"192.0.2.1", -2, "192.0.1.255"},
+		{"255.255.255.255", 1, "0.0.0.0"},
+		{"0.0.0.0", -1, "255.255.255.255"},
+		{"0.0.0.0", math.MaxInt64, "255.255.255.255"},
+		{"0.0.0.0", math.MinInt64, "0.0.0.0"},
+		{"::ffff:192.0.2.1", 256, "192.0.3.1"},
+		{"2001:db8::ffff:ffff", 1, "2001:db8::1:0:0"},
+		{"::", math.MinInt64, "ffff:ffff:ffff:ffff:8000::"},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", 2, "::1"},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		got := AddInt(ip, tt.n)
+		if !got.Equal(net.ParseIP(tt.want)) || len(got) != IPSize(ip) {
+			t.Errorf("AddInt(%v, %v) = %v, want %v", tt.ip, tt.n, got, tt.want)
+		}
+		if back := SubInt(got, tt.n); !back.Equal(ip) {
+			t.Errorf("SubInt(%v, %v) = %v, want %v", got, tt.n, back, tt.ip)
+		}
+	}
+}
+
+func TestAddSubBig(t *testing.T) {
+	two64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	two128 := new(big.Int).Lsh(big.NewInt(1), 128)
+	tests := []struct {
+		ip   string
+		n    *big.Int
+		want string
+	}{
+		{"2001:db8::", two64, "2001:db8:0:1::"},
+		{"2001:db8::", new(big.Int).Neg(two64), "2001:db7:ffff:ffff::"},
+		{"2001:db8::1", two128, "2001:db8::1"},
+		{"192.0.2.1", two64, "192.0.2.1"},
+		{"192.0.2.1", big.NewInt(1 << 32), "192.0.2.1"},
+		{"192.0.2.1", big.NewInt(1<<32 + 1), "192.0.2.2"},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		got := AddBig(ip, tt.n)
+		if !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("AddBig(%v, %v) = %v, want %v", tt.ip, tt.n, got, tt.want)
+		}
+		if back := SubBig(got, tt.n); !back.Equal(ip) {
+			t.Errorf("SubBig(%v, %v) = %v, want %v", got, tt.n, back, tt.ip)
+		}
+	}
+	for _, ip := range []net.IP{nil, {1, 2, 3}, make(net.IP, 17)} {
+		if got := AddInt(ip, 5); got != nil {
+			t.Errorf("AddInt(%v, 5) = %v, want nil", ip, got)
+		}
+		if got := SubInt(ip, 5); got != nil {
+			t.Errorf("SubInt(%v, 5) = %v, want nil", ip, got)
+		}
+		if got := AddBig(ip, big.NewInt(5)); got != nil {
+			t.Errorf("AddBig(%v, 5) = %v, want nil", ip, got)
+		}
+		if got := SubBig(ip, big.NewInt(5)); got != nil {
+			t.Errorf("SubBig(%v, 5) = %v, want nil", ip, got)
+		}
+	}
+}
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"192.0.2.250", "192.0.3.4", "10"},
+		{"192.0.3.4", "192.0.2.250", "-10"},
+		{"192.0.2.1", "::ffff:192.0.2.1", "0"},
+		{"0.0.0.0", "255.255.255.255", "4294967295"},
+		{"::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "340282366920938463463374607431768211455"},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "::", "-340282366920938463463374607431768211455"},
+		{"2001:db8::", "2001:db8:0:1::", "18446744073709551616"},
+	}
+	for _, tt := range tests {
+		got := Delta(net.ParseIP(tt.a), net.ParseIP(tt.b))
+		if got == nil || got.String() != tt.want {
+			t.Errorf("Delta(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+	invalid := []struct {
+		a, b net.IP
+	}{
+		{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")},
+		{nil, nil},
+		{net.IP{1, 2, 3}, net.IP{1, 2, 3}},
+		{nil, net.ParseIP("192.0.2.1")},
+		{net.IP{1, 2, 3, 4, 5}, net.IP{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range invalid {
+		if got := Delta(tt.a, tt.b); got != nil {
+			t.Errorf("Delta(%v, %v) = %v, want nil", tt.a, tt.b, got)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package cgn provides deterministic Carrier-Grade NAT port allocation, in the style of RFC 7422.
+//
+// Subscribers are numbered from 0 and mapped in order onto a public IPv4 pool:
+//   - The usable port range of each public address is split into PortsPerSubscriber-sized ranges
+//   - Each range is made up of BlockSize-sized port blocks
+//   - Subscriber i gets range i%N of public address i/N, where N is the number of ranges per address
+//
+// Since the mapping is a pure function of the Config, it can be reproduced from logs
+// without per-session records, and reversed to find the subscriber for a public address and port.
+package cgn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// ErrNotFound is returned by Lookup when an address and port aren't allocated to any subscriber.
+var ErrNotFound = errors.New("cgn: address and port not allocated")
+
+// Config describes a deterministic NAT pool.
+type Config struct {
+	// Pool is the public IPv4 network, all addresses are used.
+	Pool net.IPNet
+	// FirstPort and LastPort bound the inclusive port range allocated to subscribers, e.g., 1024-65535.
+	FirstPort uint16
+	LastPort  uint16
+	// PortsPerSubscriber is the number of ports allocated to each subscriber.
+	PortsPerSubscriber int
+	// BlockSize is the number of ports per block, it must divide PortsPerSubscriber.
+	BlockSize int
+}
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	First uint16
+	Last  uint16
+}
+
+// Mapping is the public address and port blocks allocated to a subscriber.
+type Mapping struct {
+	IP     net.IP
+	Blocks []PortRange
+}
+
+// Ports returns the full port range of a Mapping.
+func (m Mapping) Ports() PortRange {
+	return PortRange{m.Blocks[0].First, m.Blocks[len(m.Blocks)-1].Last}
+}
+
+// Validate returns an error if the Config can't allocate any subscriber.
+func (c Config) Validate() error {
+	if ones, bits := c.Pool.Mask.Size(); bits != 8*net.IPv4len || ones == 0 || ipcalc.IPVersion(c.Pool.IP) != 4 {
+		return fmt.Errorf("cgn: pool %v is not an IPv4 network", &c.Pool)
+	}
+	if c.FirstPort > c.LastPort {
+		return fmt.Errorf("cgn: invalid port range %v-%v", c.FirstPort, c.LastPort)
+	}
+	if c.BlockSize <= 0 || c.PortsPerSubscriber <= 0 || c.PortsPerSubscriber%c.BlockSize != 0 {
+		return fmt.Errorf("cgn: block size %v does not divide %v ports per subscriber", c.BlockSize, c.PortsPerSubscriber)
+	}
+	if c.rangesPerIP() == 0 {
+		return fmt.Errorf("cgn: port range %v-%v smaller than %v ports per subscriber", c.FirstPort, c.LastPort, c.PortsPerSubscriber)
+	}
+	return nil
+}
+
+// Subscribers returns the number of subscribers the Config can allocate.
+func (c Config) Subscribers() int {
+	if c.Validate() != nil {
+		return 0
+	}
+	return c.poolSize() * c.rangesPerIP()
+}
+
+// Map returns the public address and port blocks for a subscriber.
+// e.g., Config{192.0.2.0/30, 1024, 65535, 2048, 512}.Map(31) -> 192.0.2.1, 1024-3071.
+func (c Config) Map(subscriber int) (Mapping, error) {
+	if err := c.Validate(); err != nil {
+		return Mapping{}, err
+	}
+	if subscriber < 0 || subscriber >= c.Subscribers() {
+		return Mapping{}, fmt.Errorf("cgn: subscriber %v out of range [0, %v)", subscriber, c.Subscribers())
+	}
+	n := c.rangesPerIP()
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, c.base()+uint32(subscriber/n))
+	first := int(c.FirstPort) + subscriber%n*c.PortsPerSubscriber
+	m := Mapping{IP: ip}
+	for p := first; p < first+c.PortsPerSubscriber; p += c.BlockSize {
+		m.Blocks = append(m.Blocks, PortRange{uint16(p), uint16(p + c.BlockSize - 1)})
+	}
+	return m, nil
+}
+
+// Lookup returns the subscriber allocated a public address and port.
+func (c Config) Lookup(ip net.IP, port uint16) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+	if !c.Pool.Contains(ip) || port < c.FirstPort {
+		return 0, ErrNotFound
+	}
+	slot := int(port-c.FirstPort) / c.PortsPerSubscriber
+	if slot >= c.rangesPerIP() {
+		return 0, ErrNotFound
+	}
+	host := binary.BigEndian.Uint32(ipcalc.IP(ip)) - c.base()
+	return int(host)*c.rangesPerIP() + slot, nil
+}
+
+// rangesPerIP returns the number of subscriber port ranges on each public address.
+func (c Config) rangesPerIP() int {
+	return (int(c.LastPort) - int(c.FirstPort) + 1) / c.PortsPerSubscriber
+}
+
+// base returns the first address of the pool.
+func (c Config) base() uint32 {
+	return binary.BigEndian.Uint32(ipcalc.IP(c.Pool.IP.Mask(c.Pool.Mask)))
+}
+
+func (c Config) poolSize() int {
+	ones, bits := c.Pool.Mask.Size()
+	return 1 << uint(bits-ones)
+}
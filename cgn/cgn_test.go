@@ -0,0 +1,110 @@
+package cgn
+
+import (
+	"net"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		c  Config
+		ok bool
+	}{
+		{Config{mustCIDR(t, "192.0.2.0/30"), 1024, 65535, 2048, 512}, true},
+		{Config{mustCIDR(t, "192.0.2.1/32"), 1024, 1025, 2, 1}, true},
+		{Config{mustCIDR(t, "2001:db8::/126"), 1024, 65535, 2048, 512}, false},
+		{Config{mustCIDR(t, "0.0.0.0/0"), 1024, 65535, 2048, 512}, false},
+		{Config{mustCIDR(t, "192.0.2.0/30"), 2048, 1024, 2048, 512}, false},
+		{Config{mustCIDR(t, "192.0.2.0/30"), 1024, 65535, 2000, 512}, false},
+		{Config{mustCIDR(t, "192.0.2.0/30"), 1024, 65535, 0, 0}, false},
+		{Config{mustCIDR(t, "192.0.2.0/30"), 1024, 2047, 2048, 512}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.c.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%+v.Validate() error = %v", tt.c, err)
+		}
+	}
+}
+
+func TestSubscribers(t *testing.T) {
+	tests := []struct {
+		c    Config
+		want int
+	}{
+		{Config{mustCIDR(t, "192.0.2.0/30"), 1024, 65535, 2048, 512}, 4 * 31},
+		{Config{mustCIDR(t, "192.0.2.0/24"), 1, 65535, 1000, 100}, 256 * 65},
+		{Config{mustCIDR(t, "192.0.2.0/24"), 1, 65535, 1000, 300}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Subscribers(); got != tt.want {
+			t.Errorf("%+v.Subscribers() = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestMapLookup(t *testing.T) {
+	c := Config{mustCIDR(t, "192.0.2.0/30"), 1024, 65535, 2048, 512}
+	tests := []struct {
+		subscriber int
+		ip         string
+		ports      PortRange
+	}{
+		{0, "192.0.2.0", PortRange{1024, 3071}},
+		{1, "192.0.2.0", PortRange{3072, 5119}},
+		{30, "192.0.2.0", PortRange{62464, 64511}},
+		{31, "192.0.2.1", PortRange{1024, 3071}},
+		{123, "192.0.2.3", PortRange{62464, 64511}},
+	}
+	for _, tt := range tests {
+		m, err := c.Map(tt.subscriber)
+		if err != nil {
+			t.Errorf("Map(%v) error = %v", tt.subscriber, err)
+			continue
+		}
+		if !m.IP.Equal(net.ParseIP(tt.ip)) || m.Ports() != tt.ports || len(m.Blocks) != 4 {
+			t.Errorf("Map(%v) = %v %+v, want %v %+v", tt.subscriber, m.IP, m.Blocks, tt.ip, tt.ports)
+		}
+		for _, b := range m.Blocks {
+			if b.Last-b.First+1 != 512 {
+				t.Errorf("Map(%v) block %+v, want 512 ports", tt.subscriber, b)
+			}
+			for _, port := range []uint16{b.First, b.Last} {
+				if got, err := c.Lookup(m.IP, port); err != nil || got != tt.subscriber {
+					t.Errorf("Lookup(%v, %v) = %v, %v, want %v", m.IP, port, got, err, tt.subscriber)
+				}
+			}
+		}
+	}
+	for _, s := range []int{-1, 124} {
+		if _, err := c.Map(s); err == nil {
+			t.Errorf("Map(%v) error = nil", s)
+		}
+	}
+}
+
+func TestLookupNotFound(t *testing.T) {
+	c := Config{mustCIDR(t, "192.0.2.0/30"), 1024, 65535, 2048, 512}
+	tests := []struct {
+		ip   string
+		port uint16
+	}{
+		{"192.0.2.0", 1023},
+		{"192.0.2.0", 64512},
+		{"192.0.2.4", 2000},
+		{"2001:db8::1", 2000},
+	}
+	for _, tt := range tests {
+		if got, err := c.Lookup(net.ParseIP(tt.ip), tt.port); err != ErrNotFound {
+			t.Errorf("Lookup(%v, %v) = %v, %v, want ErrNotFound", tt.ip, tt.port, got, err)
+		}
+	}
+}
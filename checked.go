@@ -0,0 +1,131 @@
+package ipcalc
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPError is returned by checked functions when an IP address is nil or has an invalid length.
+type IPError struct {
+	Op string
+	IP net.IP
+}
+
+func (e *IPError) Error() string {
+	return fmt.Sprintf("ipcalc: %v: invalid IP address %v", e.Op, e.IP)
+}
+
+// MismatchError is returned by checked functions when operands have different sizes,
+// e.g., an IPv4 address and an IPv6 address, or a mask of the wrong length.
+type MismatchError struct {
+	Op string
+	// Sizes are the operand sizes in bytes.
+	Sizes []int
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("ipcalc: %v: mismatched operand sizes %v", e.Op, e.Sizes)
+}
+
+// NextIPE is like NextIP but returns an error for invalid input.
+func NextIPE(ip net.IP) (net.IP, error) {
+	if err := checkIPs("NextIP", ip); err != nil {
+		return nil, err
+	}
+	return NextIP(ip), nil
+}
+
+// PrevIPE is like PrevIP but returns an error for invalid input.
+func PrevIPE(ip net.IP) (net.IP, error) {
+	if err := checkIPs("PrevIP", ip); err != nil {
+		return nil, err
+	}
+	return PrevIP(ip), nil
+}
+
+// AddE is like Add but returns an error for invalid or mismatched input.
+func AddE(a, b net.IP, mask net.IPMask) (net.IP, error) {
+	if err := checkMask("Add", a, b, mask); err != nil {
+		return nil, err
+	}
+	return Add(a, b, mask), nil
+}
+
+// SubstractE is like Substract but returns an error for invalid or mismatched input.
+func SubstractE(a, b net.IP, mask net.IPMask) (net.IP, error) {
+	if err := checkMask("Substract", a, b, mask); err != nil {
+		return nil, err
+	}
+	return Substract(a, b, mask), nil
+}
+
+// AndE is like And but returns an error for invalid or mismatched input.
+func AndE(a, b net.IP) (net.IP, error) {
+	if err := checkIPs("And", a, b); err != nil {
+		return nil, err
+	}
+	return And(a, b), nil
+}
+
+// OrE is like Or but returns an error for invalid or mismatched input.
+func OrE(a, b net.IP) (net.IP, error) {
+	if err := checkIPs("Or", a, b); err != nil {
+		return nil, err
+	}
+	return Or(a, b), nil
+}
+
+// XorE is like Xor but returns an error for invalid or mismatched input.
+func XorE(a, b net.IP) (net.IP, error) {
+	if err := checkIPs("Xor", a, b); err != nil {
+		return nil, err
+	}
+	return Xor(a, b), nil
+}
+
+// BroadcastE is like Broadcast but returns an error for invalid or mismatched input.
+func BroadcastE(n net.IPNet) (net.IP, error) {
+	if err := checkNet("Broadcast", n); err != nil {
+		return nil, err
+	}
+	return Broadcast(n), nil
+}
+
+// checkIPs returns an error if any IP address is invalid or they're not all the same version.
+func checkIPs(op string, ips ...net.IP) error {
+	sizes := make([]int, len(ips))
+	for i, ip := range ips {
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return &IPError{Op: op, IP: ip}
+		}
+		sizes[i] = IPSize(ip)
+	}
+	for _, size := range sizes {
+		if size != sizes[0] {
+			return &MismatchError{Op: op, Sizes: sizes}
+		}
+	}
+	return nil
+}
+
+// checkMask is like checkIPs but also checks the mask length matches the IP addresses.
+func checkMask(op string, a, b net.IP, mask net.IPMask) error {
+	if err := checkIPs(op, a, b); err != nil {
+		return err
+	}
+	if len(mask) != IPSize(a) {
+		return &MismatchError{Op: op, Sizes: []int{IPSize(a), IPSize(b), len(mask)}}
+	}
+	return nil
+}
+
+// checkNet returns an error if a network's IP address is invalid or its mask can't be normalized to the same version.
+func checkNet(op string, n net.IPNet) error {
+	if err := checkIPs(op, n.IP); err != nil {
+		return err
+	}
+	if _, _, ok := normalizeNet(n); !ok {
+		return &MismatchError{Op: op, Sizes: []int{IPSize(n.IP), len(n.Mask)}}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package ipcalc
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestNextPrevIPE(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		next string
+		prev string
+	}{
+		{net.ParseIP("192.0.2.1"), "192.0.2.2", "192.0.2.0"},
+		{net.IP{192, 0, 2, 1}, "192.0.2.2", "192.0.2.0"},
+		{net.ParseIP("2001:db8::"), "2001:db8::1", "2001:db7:ffff:ffff:ffff:ffff:ffff:ffff"},
+		{nil, "", ""},
+		{net.IP{192, 0, 2}, "", ""},
+	}
+	for _, tt := range tests {
+		next, err := NextIPE(tt.ip)
+		if tt.next == "" {
+			var e *IPError
+			if !errors.As(err, &e) {
+				t.Errorf("NextIPE(%v) error = %v, want IPError", tt.ip, err)
+			}
+		} else if err != nil || !next.Equal(net.ParseIP(tt.next)) {
+			t.Errorf("NextIPE(%v) = %v, %v, want %v", tt.ip, next, err, tt.next)
+		}
+		prev, err := PrevIPE(tt.ip)
+		if tt.prev == "" {
+			var e *IPError
+			if !errors.As(err, &e) {
+				t.Errorf("PrevIPE(%v) error = %v, want IPError", tt.ip, err)
+			}
+		} else if err != nil || !prev.Equal(net.ParseIP(tt.prev)) {
+			t.Errorf("PrevIPE(%v) = %v, %v, want %v", tt.ip, prev, err, tt.prev)
+		}
+	}
+}
+
+func TestAddSubstractE(t *testing.T) {
+	tests := []struct {
+		a, b net.IP
+		mask net.IPMask
+		add  string
+		sub  string
+		err  error
+	}{
+		{net.ParseIP("192.0.2.1"), net.ParseIP("0.0.0.1"), ParseMask("0.0.0.255"), "192.0.2.2", "192.0.2.0", nil},
+		{net.ParseIP("2001:db8::1"), net.ParseIP("::1"), ParseMask("::ffff"), "2001:db8::2", "2001:db8::", nil},
+		{net.ParseIP("192.0.2.1"), net.ParseIP("::1"), ParseMask("0.0.0.255"), "", "", &MismatchError{}},
+		{net.ParseIP("192.0.2.1"), net.ParseIP("0.0.0.1"), ParseMask("::ffff"), "", "", &MismatchError{}},
+		{net.ParseIP("192.0.2.1"), net.ParseIP("0.0.0.1"), nil, "", "", &MismatchError{}},
+		{net.ParseIP("192.0.2.1"), nil, ParseMask("0.0.0.255"), "", "", &IPError{}},
+	}
+	for _, tt := range tests {
+		add, err := AddE(tt.a, tt.b, tt.mask)
+		if !checkErr(t, err, tt.err) && err == nil && !add.Equal(net.ParseIP(tt.add)) {
+			t.Errorf("AddE(%v, %v, %v) = %v, want %v", tt.a, tt.b, tt.mask, add, tt.add)
+		}
+		sub, err := SubstractE(tt.a, tt.b, tt.mask)
+		if !checkErr(t, err, tt.err) && err == nil && !sub.Equal(net.ParseIP(tt.sub)) {
+			t.Errorf("SubstractE(%v, %v, %v) = %v, want %v", tt.a, tt.b, tt.mask, sub, tt.sub)
+		}
+	}
+}
+
+func TestBitwiseE(t *testing.T) {
+	tests := []struct {
+		a, b net.IP
+		and  string
+		or   string
+		xor  string
+		err  error
+	}{
+		{net.ParseIP("192.0.2.255"), net.ParseIP("192.0.255.128"), "192.0.2.128", "192.0.255.255", "0.0.253.127", nil},
+		{net.ParseIP("2001:db8::ff"), net.ParseIP("::f0f"), "::f", "2001:db8::fff", "2001:db8::ff0", nil},
+		{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1"), "", "", "", &MismatchError{}},
+		{net.IP{1, 2, 3}, net.ParseIP("192.0.2.1"), "", "", "", &IPError{}},
+	}
+	for _, tt := range tests {
+		for _, f := range []struct {
+			name string
+			fn   func(a, b net.IP) (net.IP, error)
+			want string
+		}{{"AndE", AndE, tt.and}, {"OrE", OrE, tt.or}, {"XorE", XorE, tt.xor}} {
+			got, err := f.fn(tt.a, tt.b)
+			if !checkErr(t, err, tt.err) && err == nil && !got.Equal(net.ParseIP(f.want)) {
+				t.Errorf("%v(%v, %v) = %v, want %v", f.name, tt.a, tt.b, got, f.want)
+			}
+		}
+	}
+}
+
+// checkErr reports whether err doesn't have the same type as want, logging the mismatch.
+func checkErr(t *testing.T, err, want error) bool {
+	t.Helper()
+	var ipErr *IPError
+	var mismatchErr *MismatchError
+	switch want.(type) {
+	case nil:
+		if err != nil {
+			t.Errorf("error = %v, want nil", err)
+			return true
+		}
+	case *IPError:
+		if !errors.As(err, &ipErr) {
+			t.Errorf("error = %v, want IPError", err)
+			return true
+		}
+	case *MismatchError:
+		if !errors.As(err, &mismatchErr) {
+			t.Errorf("error = %v, want MismatchError", err)
+			return true
+		}
+	}
+	return false
+}
+
+func TestBroadcastE(t *testing.T) {
+	tests := []struct {
+		n    net.IPNet
+		want string
+		err  error
+	}{
+		{net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(24, 32)}, "192.0.2.255", nil},
+		{net.IPNet{IP: net.IP{192, 0, 2, 0}, Mask: net.CIDRMask(120, 128)}, "192.0.2.255", nil},
+		{net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(64, 128)}, "2001:db8::ffff:ffff:ffff:ffff", nil},
+		{net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}, "", &MismatchError{}},
+		{net.IPNet{IP: net.IP{192, 0, 2, 0}, Mask: nil}, "", &MismatchError{}},
+		{net.IPNet{IP: net.IP{192, 0, 2}, Mask: net.CIDRMask(24, 32)}, "", &IPError{}},
+	}
+	for _, tt := range tests {
+		got, err := BroadcastE(tt.n)
+		if !checkErr(t, err, tt.err) && err == nil && !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("BroadcastE(%v) = %v, want %v", &tt.n, got, tt.want)
+		}
+	}
+}
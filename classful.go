@@ -0,0 +1,92 @@
+package ipcalc
+
+import (
+	"fmt"
+	"net"
+)
+
+// classfulBlocks are the IPv4 address classes (RFC 791, RFC 1112) and their default prefix lengths,
+// classes D and E have no default mask.
+var classfulBlocks = []struct {
+	class string
+	block net.IPNet
+	ones  int
+}{
+	{"A", net.IPNet{IP: net.IP{0, 0, 0, 0}, Mask: net.CIDRMask(1, 32)}, 8},
+	{"B", net.IPNet{IP: net.IP{128, 0, 0, 0}, Mask: net.CIDRMask(2, 32)}, 16},
+	{"C", net.IPNet{IP: net.IP{192, 0, 0, 0}, Mask: net.CIDRMask(3, 32)}, 24},
+	{"D", net.IPNet{IP: net.IP{224, 0, 0, 0}, Mask: net.CIDRMask(4, 32)}, 0},
+	{"E", net.IPNet{IP: net.IP{240, 0, 0, 0}, Mask: net.CIDRMask(4, 32)}, 0},
+}
+
+// Class returns the class (A through E) of an IPv4 address, or "" if it's not an IPv4 address.
+// e.g., Class(172.16.0.1) -> B.
+func Class(ip net.IP) string {
+	for _, c := range classfulBlocks {
+		if ip.To4() != nil && c.block.Contains(ip) {
+			return c.class
+		}
+	}
+	return ""
+}
+
+// DefaultClassfulMask returns the default mask of the class of an IPv4 address,
+// or nil for class D and E addresses and for IPv6 addresses.
+// Unlike net.IP.DefaultMask, class D and E addresses have no default mask.
+// e.g., DefaultClassfulMask(172.16.0.1) -> 255.255.0.0.
+func DefaultClassfulMask(ip net.IP) net.IPMask {
+	for _, c := range classfulBlocks {
+		if ip.To4() != nil && c.block.Contains(ip) && c.ones > 0 {
+			return net.CIDRMask(c.ones, 8*net.IPv4len)
+		}
+	}
+	return nil
+}
+
+// ClassfulNets decomposes an IPv4 network into the classful networks it overlaps, in ascending order.
+// A network longer than its default mask yields its enclosing classful network,
+// class D and E space is skipped and it's an error if nothing is left.
+// As with Split, at most 2^MaxSplitBits networks are returned per class, e.g., 0.0.0.0/0 and 192.0.0.0/7 are errors,
+// use Subnets to iterate over larger classful ranges.
+// e.g., ClassfulNets(172.15.0.0/15) -> [172.14.0.0/16 172.15.0.0/16],
+// ClassfulNets(10.1.0.0/16) -> [10.0.0.0/8].
+func ClassfulNets(n net.IPNet) ([]net.IPNet, error) {
+	first, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil, err
+	}
+	if size != 8*net.IPv4len {
+		return nil, fmt.Errorf("ipcalc: %v is not an IPv4 network", &n)
+	}
+	n = net.IPNet{IP: first, Mask: net.CIDRMask(ones, size)}
+	var nets []net.IPNet
+	for _, c := range classfulBlocks {
+		switch {
+		case c.ones == 0:
+			continue
+		case Contains(c.block, n) && ones >= c.ones:
+			mask := net.CIDRMask(c.ones, size)
+			return []net.IPNet{{IP: first.Mask(mask), Mask: mask}}, nil
+		case Contains(c.block, n):
+			if c.ones-ones > MaxSplitBits {
+				return nil, classfulLimitError(n, c.class)
+			}
+			return Split(n, c.ones)
+		case Contains(n, c.block):
+			subnets, err := Split(c.block, c.ones)
+			if err != nil {
+				return nil, classfulLimitError(n, c.class)
+			}
+			nets = append(nets, subnets...)
+		}
+	}
+	if len(nets) == 0 {
+		return nil, fmt.Errorf("ipcalc: %v has no classful networks", &n)
+	}
+	return nets, nil
+}
+
+// classfulLimitError returns the error for a network spanning more than 2^MaxSplitBits networks of a class.
+func classfulLimitError(n net.IPNet, class string) error {
+	return fmt.Errorf("ipcalc: %v spans more than 2^%v class %v networks", &n, MaxSplitBits, class)
+}
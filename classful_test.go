@@ -0,0 +1,88 @@
+package ipcalc
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestClass(t *testing.T) {
+	tests := []struct {
+		ip    net.IP
+		class string
+		mask  string
+	}{
+		{net.ParseIP("10.0.0.1"), "A", "255.0.0.0"},
+		{net.ParseIP("127.255.255.255"), "A", "255.0.0.0"},
+		{net.ParseIP("128.0.0.0"), "B", "255.255.0.0"},
+		{net.IP{172, 16, 0, 1}, "B", "255.255.0.0"},
+		{net.ParseIP("192.0.2.1"), "C", "255.255.255.0"},
+		{net.ParseIP("224.0.0.1"), "D", ""},
+		{net.ParseIP("255.255.255.255"), "E", ""},
+		{net.ParseIP("2001:db8::1"), "", ""},
+		{nil, "", ""},
+	}
+	for _, tt := range tests {
+		if got := Class(tt.ip); got != tt.class {
+			t.Errorf("Class(%v) = %v, want %v", tt.ip, got, tt.class)
+		}
+		got := DefaultClassfulMask(tt.ip)
+		if want := ParseMask(tt.mask); !reflect.DeepEqual(got, want) {
+			t.Errorf("DefaultClassfulMask(%v) = %v, want %v", tt.ip, got, want)
+		}
+	}
+}
+
+func TestClassfulNets(t *testing.T) {
+	tests := []struct {
+		n    string
+		want []string
+		ok   bool
+	}{
+		{"10.1.0.0/16", []string{"10.0.0.0/8"}, true},
+		{"10.0.0.0/8", []string{"10.0.0.0/8"}, true},
+		{"172.14.0.0/15", []string{"172.14.0.0/16", "172.15.0.0/16"}, true},
+		{"192.0.2.0/23", []string{"192.0.2.0/24", "192.0.3.0/24"}, true},
+		{"::ffff:10.1.0.0/112", []string{"10.0.0.0/8"}, true},
+		{"126.0.0.0/6", []string{"124.0.0.0/8", "125.0.0.0/8", "126.0.0.0/8", "127.0.0.0/8"}, true},
+		{"224.0.0.0/3", nil, false},
+		{"0.0.0.0/0", nil, false},
+		{"2001:db8::/32", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := ClassfulNets(mustCIDR(t, tt.n))
+		if (err == nil) != tt.ok {
+			t.Errorf("ClassfulNets(%v) error = %v, want ok = %v", tt.n, err, tt.ok)
+			continue
+		}
+		if s := netStrings(got); tt.ok && !reflect.DeepEqual(s, tt.want) {
+			t.Errorf("ClassfulNets(%v) = %v, want %v", tt.n, s, tt.want)
+		}
+	}
+	got, err := ClassfulNets(mustCIDR(t, "0.0.0.0/1"))
+	if err != nil || len(got) != 128 {
+		t.Errorf("ClassfulNets(0.0.0.0/1) = %v networks, %v, want 128", len(got), err)
+	}
+	got, err = ClassfulNets(mustCIDR(t, "128.0.0.0/2"))
+	if err != nil || len(got) != 1<<14 {
+		t.Errorf("ClassfulNets(128.0.0.0/2) = %v networks, %v, want %v", len(got), err, 1<<14)
+	}
+}
+
+func TestClassfulNetsLimit(t *testing.T) {
+	tests := map[string]string{
+		"0.0.0.0/0":    "ipcalc: 0.0.0.0/0 spans more than 2^16 class C networks",
+		"192.0.0.0/7":  "ipcalc: 192.0.0.0/7 spans more than 2^16 class C networks",
+		"192.0.0.0/3":  "ipcalc: 192.0.0.0/3 spans more than 2^16 class C networks",
+		"128.0.0.0/1":  "ipcalc: 128.0.0.0/1 spans more than 2^16 class C networks",
+		"192.0.0.0/8":  "",
+		"192.0.0.0/24": "",
+	}
+	for n, want := range tests {
+		_, err := ClassfulNets(mustCIDR(t, n))
+		if got := fmt.Sprint(err); want == "" && err != nil || want != "" && got != want {
+			t.Errorf("ClassfulNets(%v) error = %v, want %q", n, err, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package classify
+
+import (
+	"net"
+	"runtime"
+	"sync"
+
+	"github.com/hazaelsan/ipcalc/trie"
+)
+
+// minClassifyChunk is the minimum number of addresses classified by each goroutine.
+const minClassifyChunk = 4096
+
+// Classification is the result of classifying a single IP address.
+type Classification struct {
+	IP net.IP
+	// Entry is the most specific Registry entry containing IP, valid only if Special is set.
+	Entry Entry
+	// Special is set if IP is within any Registry entry.
+	Special bool
+}
+
+// BatchOption configures ClassifyAll.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	workers int
+}
+
+// Parallel splits a batch across up to workers goroutines, runtime.GOMAXPROCS(0) if workers <= 0.
+// Small batches are still classified by a single goroutine.
+func Parallel(workers int) BatchOption {
+	return func(o *batchOptions) {
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		o.workers = workers
+	}
+}
+
+// registryTable is a longest-prefix match table over the Registry, built on first use.
+// Entries are inserted in reverse so the first of any duplicate networks wins, as with Lookup.
+var registryTable = sync.OnceValue(func() *trie.Table[Entry] {
+	entries := make([]trie.PrefixValue[Entry], 0, len(Registry))
+	for i := len(Registry) - 1; i >= 0; i-- {
+		entries = append(entries, trie.PrefixValue[Entry]{Prefix: Registry[i].Prefix, Value: Registry[i]})
+	}
+	t, err := trie.Build(entries)
+	if err != nil {
+		panic(err)
+	}
+	return t
+})
+
+// ClassifyAll classifies a batch of IP addresses as Lookup does, out[i] corresponds to ips[i].
+// Lookups share a table indexing the Registry, so the cost per address is a binary search
+// instead of a scan over every entry, the table is built on first use and doesn't see later changes to Registry.
+// Addresses are classified sequentially unless the Parallel option is given.
+// e.g., ClassifyAll([10.1.2.3 8.8.8.8]) -> [{10.1.2.3 Private-Use (RFC 1918) true} {8.8.8.8 false}].
+func ClassifyAll(ips []net.IP, opts ...BatchOption) []Classification {
+	o := batchOptions{workers: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	t := registryTable()
+	out := make([]Classification, len(ips))
+	classify := func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			_, e, ok := t.Lookup(ips[i])
+			out[i] = Classification{IP: ips[i], Entry: e, Special: ok}
+		}
+	}
+	chunk := max(minClassifyChunk, (len(ips)+o.workers-1)/o.workers)
+	if chunk >= len(ips) {
+		classify(0, len(ips))
+		return out
+	}
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(ips); lo += chunk {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			classify(lo, min(lo+chunk, len(ips)))
+		}()
+	}
+	wg.Wait()
+	return out
+}
@@ -0,0 +1,47 @@
+package classify
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func TestClassifyAll(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("10.1.2.3"),
+		net.ParseIP("0.0.0.0"),
+		net.ParseIP("192.0.0.9"),
+		net.ParseIP("::ffff:10.0.0.1"),
+		net.ParseIP("8.8.8.8"),
+		net.ParseIP("::"),
+		net.ParseIP("2001:1::1"),
+		net.ParseIP("2001:0:1::"),
+		net.ParseIP("fe80::1"),
+		net.ParseIP("2606:4700::1111"),
+		nil,
+		{1, 2, 3},
+	}
+	// Cover every chunk boundary with a batch larger than minClassifyChunk.
+	for ip := net.ParseIP("172.15.255.0"); len(ips) < 3*minClassifyChunk; ip = ipcalc.NextIP(ip) {
+		ips = append(ips, ip)
+	}
+	for _, opts := range [][]BatchOption{nil, {Parallel(0)}, {Parallel(1)}, {Parallel(3)}} {
+		got := ClassifyAll(ips, opts...)
+		if len(got) != len(ips) {
+			t.Fatalf("ClassifyAll(%v options) = %v results, want %v", len(opts), len(got), len(ips))
+		}
+		for i, ip := range ips {
+			e, ok := Lookup(ip)
+			if c := got[i]; !c.IP.Equal(ip) || c.Special != ok || c.Entry.String() != e.String() || c.Entry.Kind != e.Kind {
+				t.Errorf("ClassifyAll(%v options)[%v] = %v, %v, %v, want %v, %v, %v", len(opts), i, c.IP, c.Entry, c.Special, ip, e, ok)
+			}
+		}
+	}
+}
+
+func TestClassifyAllEmpty(t *testing.T) {
+	if got := ClassifyAll(nil, Parallel(4)); len(got) != 0 {
+		t.Errorf("ClassifyAll(nil) = %v, want empty", got)
+	}
+}
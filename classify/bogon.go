@@ -0,0 +1,85 @@
+package classify
+
+import (
+	"net"
+)
+
+// martians are never valid as source addresses (RFC 1812 section 5.3.7, RFC 4291).
+var martians = []net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("127.0.0.0/8"),
+	mustParseCIDR("224.0.0.0/4"),
+	mustParseCIDR("240.0.0.0/4"),
+
+	mustParseCIDR("::/96"),
+	mustParseCIDR("::1/128"),
+	mustParseCIDR("ff00::/8"),
+}
+
+// bogons aren't expected on the public Internet, they include the martians.
+var bogons = map[int][]net.IPNet{
+	4: {
+		mustParseCIDR("0.0.0.0/8"),
+		mustParseCIDR("10.0.0.0/8"),
+		mustParseCIDR("100.64.0.0/10"),
+		mustParseCIDR("127.0.0.0/8"),
+		mustParseCIDR("169.254.0.0/16"),
+		mustParseCIDR("172.16.0.0/12"),
+		mustParseCIDR("192.0.0.0/24"),
+		mustParseCIDR("192.0.2.0/24"),
+		mustParseCIDR("192.88.99.0/24"),
+		mustParseCIDR("192.168.0.0/16"),
+		mustParseCIDR("198.18.0.0/15"),
+		mustParseCIDR("198.51.100.0/24"),
+		mustParseCIDR("203.0.113.0/24"),
+		mustParseCIDR("224.0.0.0/4"),
+		mustParseCIDR("240.0.0.0/4"),
+	},
+	6: {
+		mustParseCIDR("::/8"),
+		mustParseCIDR("100::/64"),
+		mustParseCIDR("2001:2::/48"),
+		mustParseCIDR("2001:10::/28"),
+		mustParseCIDR("2001:db8::/32"),
+		mustParseCIDR("3ffe::/16"),
+		mustParseCIDR("3fff::/20"),
+		mustParseCIDR("5f00::/16"),
+		mustParseCIDR("fc00::/7"),
+		mustParseCIDR("fe80::/10"),
+		mustParseCIDR("fec0::/10"),
+		mustParseCIDR("ff00::/8"),
+	},
+}
+
+// BogonPrefixes returns the bogon prefixes for an IP version (4 or 6), or nil for any other version.
+// Bogons are special-purpose, documentation and deprecated blocks which aren't expected
+// as source or destination addresses on the public Internet, unallocated space isn't included.
+func BogonPrefixes(version int) []net.IPNet {
+	var nets []net.IPNet
+	for _, n := range bogons[version] {
+		nets = append(nets, net.IPNet{IP: append(net.IP(nil), n.IP...), Mask: append(net.IPMask(nil), n.Mask...)})
+	}
+	return nets
+}
+
+// IsBogon returns whether an IP address is within the BogonPrefixes, every martian address is also a bogon.
+// e.g., IsBogon(10.0.0.1) -> true.
+func IsBogon(ip net.IP) bool {
+	return containsAny(bogons[4], ip) || containsAny(bogons[6], ip)
+}
+
+// IsMartian returns whether an IP address is never valid as a source address,
+// e.g., "this network", loopback, multicast, reserved and IPv4-compatible IPv6 addresses.
+// e.g., IsMartian(127.0.0.1) -> true, IsMartian(10.0.0.1) -> false.
+func IsMartian(ip net.IP) bool {
+	return containsAny(martians, ip)
+}
+
+func containsAny(nets []net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package classify
+
+import (
+	"net"
+	"testing"
+)
+
+func TestBogonMartian(t *testing.T) {
+	tests := []struct {
+		ip      string
+		bogon   bool
+		martian bool
+	}{
+		{"0.1.2.3", true, true},
+		{"10.0.0.1", true, false},
+		{"100.100.0.1", true, false},
+		{"127.0.0.1", true, true},
+		{"192.0.2.1", true, false},
+		{"224.0.0.1", true, true},
+		{"255.255.255.255", true, true},
+		{"8.8.8.8", false, false},
+		{"::ffff:127.0.0.1", true, true},
+		{"::", true, true},
+		{"::1", true, true},
+		{"::2", true, true},
+		{"2001:db8::1", true, false},
+		{"fd00::1", true, false},
+		{"5f00::1", true, false},
+		{"5f01::1", false, false},
+		{"ff02::1", true, true},
+		{"2606:4700::1111", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if got := IsBogon(ip); got != tt.bogon {
+			t.Errorf("IsBogon(%v) = %v, want %v", tt.ip, got, tt.bogon)
+		}
+		if got := IsMartian(ip); got != tt.martian {
+			t.Errorf("IsMartian(%v) = %v, want %v", tt.ip, got, tt.martian)
+		}
+	}
+}
+
+func TestBogonPrefixes(t *testing.T) {
+	for _, tt := range []struct {
+		version int
+		size    int
+	}{{4, 32}, {6, 128}} {
+		nets := BogonPrefixes(tt.version)
+		if len(nets) == 0 {
+			t.Errorf("BogonPrefixes(%v) = [], want prefixes", tt.version)
+		}
+		for _, n := range nets {
+			if _, bits := n.Mask.Size(); bits != tt.size {
+				t.Errorf("BogonPrefixes(%v) includes %v", tt.version, &n)
+			}
+		}
+		// Modifying the result must not affect later calls.
+		nets[0].IP[0] ^= 0xff
+		if BogonPrefixes(tt.version)[0].IP.Equal(nets[0].IP) {
+			t.Errorf("BogonPrefixes(%v) returned shared storage", tt.version)
+		}
+	}
+	if nets := BogonPrefixes(5); nets != nil {
+		t.Errorf("BogonPrefixes(5) = %v, want nil", nets)
+	}
+	for _, m := range martians {
+		if !IsBogon(m.IP) {
+			t.Errorf("IsBogon(%v) = false for martian %v", m.IP, &m)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package classify identifies special-purpose IP addresses using the IANA registries,
+// see https://www.iana.org/assignments/iana-ipv4-special-registry and
+// https://www.iana.org/assignments/iana-ipv6-special-registry.
+//
+// Multicast ranges (RFC 5771, RFC 4291) are included even though they're kept in separate registries.
+// IPv4-mapped IPv6 addresses are classified as IPv4 addresses.
+package classify
+
+import (
+	"net"
+)
+
+// Kind is the broad category of a special-purpose address block.
+type Kind int
+
+// Address block kinds.
+const (
+	Other Kind = iota
+	Unspecified
+	Private
+	SharedCGN
+	Loopback
+	LinkLocal
+	Documentation
+	Benchmarking
+	Multicast
+	Broadcast
+	Reserved
+	ULA
+)
+
+// Entry is an entry of a special-purpose address registry.
+type Entry struct {
+	Prefix net.IPNet
+	Name   string
+	// RFC is the defining document, e.g., "RFC 1918".
+	RFC  string
+	Kind Kind
+}
+
+// String returns the name and defining document of an Entry, e.g., "Private-Use (RFC 1918)".
+func (e Entry) String() string {
+	return e.Name + " (" + e.RFC + ")"
+}
+
+// Registry is the list of known special-purpose address blocks, blocks may be nested.
+var Registry = []Entry{
+	{mustParseCIDR("0.0.0.0/8"), "This network", "RFC 791", Other},
+	{mustParseCIDR("0.0.0.0/32"), "This host on this network", "RFC 1122", Unspecified},
+	{mustParseCIDR("10.0.0.0/8"), "Private-Use", "RFC 1918", Private},
+	{mustParseCIDR("100.64.0.0/10"), "Shared Address Space", "RFC 6598", SharedCGN},
+	{mustParseCIDR("127.0.0.0/8"), "Loopback", "RFC 1122", Loopback},
+	{mustParseCIDR("169.254.0.0/16"), "Link Local", "RFC 3927", LinkLocal},
+	{mustParseCIDR("172.16.0.0/12"), "Private-Use", "RFC 1918", Private},
+	{mustParseCIDR("192.0.0.0/24"), "IETF Protocol Assignments", "RFC 6890", Other},
+	{mustParseCIDR("192.0.0.0/29"), "IPv4 Service Continuity Prefix", "RFC 7335", Other},
+	{mustParseCIDR("192.0.0.8/32"), "IPv4 dummy address", "RFC 7600", Other},
+	{mustParseCIDR("192.0.0.9/32"), "Port Control Protocol Anycast", "RFC 7723", Other},
+	{mustParseCIDR("192.0.0.10/32"), "Traversal Using Relays around NAT Anycast", "RFC 8155", Other},
+	{mustParseCIDR("192.0.0.170/32"), "NAT64/DNS64 Discovery", "RFC 8880", Other},
+	{mustParseCIDR("192.0.0.171/32"), "NAT64/DNS64 Discovery", "RFC 8880", Other},
+	{mustParseCIDR("192.0.2.0/24"), "Documentation (TEST-NET-1)", "RFC 5737", Documentation},
+	{mustParseCIDR("192.31.196.0/24"), "AS112-v4", "RFC 7535", Other},
+	{mustParseCIDR("192.52.193.0/24"), "AMT", "RFC 7450", Other},
+	{mustParseCIDR("192.88.99.0/24"), "Deprecated (6to4 Relay Anycast)", "RFC 7526", Reserved},
+	{mustParseCIDR("192.168.0.0/16"), "Private-Use", "RFC 1918", Private},
+	{mustParseCIDR("192.175.48.0/24"), "Direct Delegation AS112 Service", "RFC 7534", Other},
+	{mustParseCIDR("198.18.0.0/15"), "Benchmarking", "RFC 2544", Benchmarking},
+	{mustParseCIDR("198.51.100.0/24"), "Documentation (TEST-NET-2)", "RFC 5737", Documentation},
+	{mustParseCIDR("203.0.113.0/24"), "Documentation (TEST-NET-3)", "RFC 5737", Documentation},
+	{mustParseCIDR("224.0.0.0/4"), "Multicast", "RFC 5771", Multicast},
+	{mustParseCIDR("240.0.0.0/4"), "Reserved", "RFC 1112", Reserved},
+	{mustParseCIDR("255.255.255.255/32"), "Limited Broadcast", "RFC 919", Broadcast},
+
+	{mustParseCIDR("::/128"), "Unspecified Address", "RFC 4291", Unspecified},
+	{mustParseCIDR("::1/128"), "Loopback Address", "RFC 4291", Loopback},
+	{mustParseCIDR("64:ff9b::/96"), "IPv4-IPv6 Translat.", "RFC 6052", Other},
+	{mustParseCIDR("64:ff9b:1::/48"), "IPv4-IPv6 Translat.", "RFC 8215", Other},
+	{mustParseCIDR("100::/64"), "Discard-Only Address Block", "RFC 6666", Other},
+	{mustParseCIDR("2001::/23"), "IETF Protocol Assignments", "RFC 2928", Other},
+	{mustParseCIDR("2001::/32"), "TEREDO", "RFC 4380", Other},
+	{mustParseCIDR("2001:1::1/128"), "Port Control Protocol Anycast", "RFC 7723", Other},
+	{mustParseCIDR("2001:1::2/128"), "Traversal Using Relays around NAT Anycast", "RFC 8155", Other},
+	{mustParseCIDR("2001:2::/48"), "Benchmarking", "RFC 5180", Benchmarking},
+	{mustParseCIDR("2001:3::/32"), "AMT", "RFC 7450", Other},
+	{mustParseCIDR("2001:4:112::/48"), "AS112-v6", "RFC 7535", Other},
+	{mustParseCIDR("2001:10::/28"), "Deprecated (previously ORCHID)", "RFC 4843", Reserved},
+	{mustParseCIDR("2001:20::/28"), "ORCHIDv2", "RFC 7343", Other},
+	{mustParseCIDR("2001:db8::/32"), "Documentation", "RFC 3849", Documentation},
+	{mustParseCIDR("2002::/16"), "6to4", "RFC 3056", Other},
+	{mustParseCIDR("2620:4f:8000::/48"), "Direct Delegation AS112 Service", "RFC 7534", Other},
+	{mustParseCIDR("3fff::/20"), "Documentation", "RFC 9637", Documentation},
+	{mustParseCIDR("fc00::/7"), "Unique-Local", "RFC 4193", ULA},
+	{mustParseCIDR("fe80::/10"), "Link-Local Unicast", "RFC 4291", LinkLocal},
+	{mustParseCIDR("ff00::/8"), "Multicast", "RFC 4291", Multicast},
+}
+
+// Lookup returns the most specific Registry entry containing an IP address.
+// e.g., Lookup(192.0.0.9) -> Port Control Protocol Anycast (RFC 7723), true.
+func Lookup(ip net.IP) (Entry, bool) {
+	return lookup(ip, func(Entry) bool { return true })
+}
+
+// LookupKind returns the most specific Registry entry of the given Kind containing an IP address.
+func LookupKind(ip net.IP, k Kind) (Entry, bool) {
+	return lookup(ip, func(e Entry) bool { return e.Kind == k })
+}
+
+func lookup(ip net.IP, match func(Entry) bool) (Entry, bool) {
+	var best Entry
+	bestOnes := -1
+	for _, e := range Registry {
+		if ones, _ := e.Prefix.Mask.Size(); ones > bestOnes && match(e) && e.Prefix.Contains(ip) {
+			best, bestOnes = e, ones
+		}
+	}
+	return best, bestOnes >= 0
+}
+
+// IsUnspecified returns the entry for an unspecified address, e.g., 0.0.0.0 or ::.
+func IsUnspecified(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Unspecified)
+}
+
+// IsPrivate returns the entry for an RFC 1918 private address, e.g., 10.0.0.1.
+func IsPrivate(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Private)
+}
+
+// IsSharedCGN returns the entry for an RFC 6598 shared address, e.g., 100.64.0.1.
+func IsSharedCGN(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, SharedCGN)
+}
+
+// IsLoopback returns the entry for a loopback address, e.g., 127.0.0.1 or ::1.
+func IsLoopback(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Loopback)
+}
+
+// IsLinkLocal returns the entry for a link-local unicast address, e.g., 169.254.0.1 or fe80::1.
+func IsLinkLocal(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, LinkLocal)
+}
+
+// IsDocumentation returns the entry for a documentation address, e.g., 192.0.2.1 or 2001:db8::1.
+func IsDocumentation(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Documentation)
+}
+
+// IsBenchmarking returns the entry for a benchmarking address, e.g., 198.18.0.1 or 2001:2::1.
+func IsBenchmarking(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Benchmarking)
+}
+
+// IsMulticast returns the entry for a multicast address, e.g., 224.0.0.1 or ff02::1.
+func IsMulticast(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Multicast)
+}
+
+// IsBroadcast returns the entry for the limited broadcast address 255.255.255.255.
+func IsBroadcast(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Broadcast)
+}
+
+// IsReserved returns the entry for a reserved or deprecated address, e.g., 240.0.0.1.
+func IsReserved(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, Reserved)
+}
+
+// IsULA returns the entry for an IPv6 Unique Local Address, e.g., fd00::1.
+func IsULA(ip net.IP) (Entry, bool) {
+	return LookupKind(ip, ULA)
+}
+
+func mustParseCIDR(s string) net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return *n
+}
@@ -0,0 +1,77 @@
+package classify
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+		kind Kind
+		ok   bool
+	}{
+		{"10.1.2.3", "Private-Use (RFC 1918)", Private, true},
+		{"172.31.255.255", "Private-Use (RFC 1918)", Private, true},
+		{"172.32.0.0", "", Other, false},
+		{"100.127.0.1", "Shared Address Space (RFC 6598)", SharedCGN, true},
+		{"0.0.0.0", "This host on this network (RFC 1122)", Unspecified, true},
+		{"0.1.2.3", "This network (RFC 791)", Other, true},
+		{"192.0.0.9", "Port Control Protocol Anycast (RFC 7723)", Other, true},
+		{"192.0.0.100", "IETF Protocol Assignments (RFC 6890)", Other, true},
+		{"198.19.255.255", "Benchmarking (RFC 2544)", Benchmarking, true},
+		{"203.0.113.7", "Documentation (TEST-NET-3) (RFC 5737)", Documentation, true},
+		{"239.1.1.1", "Multicast (RFC 5771)", Multicast, true},
+		{"255.255.255.255", "Limited Broadcast (RFC 919)", Broadcast, true},
+		{"255.255.255.254", "Reserved (RFC 1112)", Reserved, true},
+		{"::ffff:10.0.0.1", "Private-Use (RFC 1918)", Private, true},
+		{"8.8.8.8", "", Other, false},
+		{"::", "Unspecified Address (RFC 4291)", Unspecified, true},
+		{"2001:db8::1", "Documentation (RFC 3849)", Documentation, true},
+		{"2001:1::1", "Port Control Protocol Anycast (RFC 7723)", Other, true},
+		{"2001:0:1::", "TEREDO (RFC 4380)", Other, true},
+		{"2001:100::", "IETF Protocol Assignments (RFC 2928)", Other, true},
+		{"fd12:3456::1", "Unique-Local (RFC 4193)", ULA, true},
+		{"fe80::1", "Link-Local Unicast (RFC 4291)", LinkLocal, true},
+		{"2606:4700::1111", "", Other, false},
+		{"", "", Other, false},
+	}
+	for _, tt := range tests {
+		e, ok := Lookup(net.ParseIP(tt.ip))
+		if ok != tt.ok || (ok && (e.String() != tt.want || e.Kind != tt.kind)) {
+			t.Errorf("Lookup(%v) = %v, %v, %v, want %v, %v, %v", tt.ip, e, e.Kind, ok, tt.want, tt.kind, tt.ok)
+		}
+	}
+}
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(net.IP) (Entry, bool)
+		ip   string
+		want bool
+	}{
+		{"IsUnspecified", IsUnspecified, "::", true},
+		{"IsPrivate", IsPrivate, "192.168.1.1", true},
+		{"IsPrivate", IsPrivate, "100.64.0.1", false},
+		{"IsSharedCGN", IsSharedCGN, "100.64.0.1", true},
+		{"IsLoopback", IsLoopback, "127.0.0.53", true},
+		{"IsLoopback", IsLoopback, "::2", false},
+		{"IsLinkLocal", IsLinkLocal, "169.254.1.1", true},
+		{"IsDocumentation", IsDocumentation, "3fff::1", true},
+		{"IsBenchmarking", IsBenchmarking, "2001:2::1", true},
+		{"IsMulticast", IsMulticast, "ff02::1", true},
+		{"IsMulticast", IsMulticast, "fe80::1", false},
+		{"IsBroadcast", IsBroadcast, "255.255.255.255", true},
+		{"IsReserved", IsReserved, "240.0.0.1", true},
+		{"IsReserved", IsReserved, "255.255.255.255", true},
+		{"IsULA", IsULA, "fc00::1", true},
+		{"IsULA", IsULA, "10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if _, got := tt.fn(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("%v(%v) = %v, want %v", tt.name, tt.ip, got, tt.want)
+		}
+	}
+}
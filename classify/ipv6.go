@@ -0,0 +1,120 @@
+package classify
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPv6Type is the type of an IPv6 address (RFC 4291).
+type IPv6Type int
+
+// IPv6 address types.
+const (
+	TypeOther IPv6Type = iota
+	TypeUnspecified
+	TypeLoopback
+	TypeV4Mapped
+	TypeLinkLocal
+	TypeULA
+	TypeGUA
+	TypeMulticast
+)
+
+var ipv6TypeNames = map[IPv6Type]string{
+	TypeOther:       "other",
+	TypeUnspecified: "unspecified",
+	TypeLoopback:    "loopback",
+	TypeV4Mapped:    "v4-mapped",
+	TypeLinkLocal:   "link-local",
+	TypeULA:         "unique-local",
+	TypeGUA:         "global-unicast",
+	TypeMulticast:   "multicast",
+}
+
+func (t IPv6Type) String() string {
+	if s, ok := ipv6TypeNames[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("IPv6Type(%d)", int(t))
+}
+
+// MulticastScope is the scope of an IPv6 multicast address (RFC 7346).
+type MulticastScope uint8
+
+// IPv6 multicast scopes, unlisted values are unassigned or reserved.
+const (
+	ScopeInterfaceLocal MulticastScope = 0x1
+	ScopeLinkLocal      MulticastScope = 0x2
+	ScopeRealmLocal     MulticastScope = 0x3
+	ScopeAdminLocal     MulticastScope = 0x4
+	ScopeSiteLocal      MulticastScope = 0x5
+	ScopeOrgLocal       MulticastScope = 0x8
+	ScopeGlobal         MulticastScope = 0xe
+)
+
+var scopeNames = map[MulticastScope]string{
+	ScopeInterfaceLocal: "interface-local",
+	ScopeLinkLocal:      "link-local",
+	ScopeRealmLocal:     "realm-local",
+	ScopeAdminLocal:     "admin-local",
+	ScopeSiteLocal:      "site-local",
+	ScopeOrgLocal:       "organization-local",
+	ScopeGlobal:         "global",
+}
+
+func (s MulticastScope) String() string {
+	if n, ok := scopeNames[s]; ok {
+		return n
+	}
+	return fmt.Sprintf("scope(%#x)", uint8(s))
+}
+
+// MulticastFlags are the flags of an IPv6 multicast address (RFC 4291, RFC 3306, RFC 3956).
+type MulticastFlags struct {
+	// Transient is set for dynamically assigned groups, it's unset for well-known groups.
+	Transient bool
+	// Prefix is set for unicast-prefix-based groups.
+	Prefix bool
+	// RendezvousPoint is set for groups embedding a rendezvous point address.
+	RendezvousPoint bool
+}
+
+// IPv6Info is the classification of an IPv6 address, Scope and Flags are only set for multicast addresses.
+type IPv6Info struct {
+	Type  IPv6Type
+	Scope MulticastScope
+	Flags MulticastFlags
+}
+
+// ClassifyIPv6 returns the classification of an IPv6 address, it returns false if ip isn't 16 bytes long.
+// IPv4 addresses in their 16-byte form are classified as TypeV4Mapped.
+// e.g., ClassifyIPv6(ff32:40:2001:db8::1) -> {multicast link-local {Transient Prefix}}, true.
+func ClassifyIPv6(ip net.IP) (IPv6Info, bool) {
+	if len(ip) != net.IPv6len {
+		return IPv6Info{}, false
+	}
+	var info IPv6Info
+	switch {
+	case ip.Equal(net.IPv6unspecified):
+		info.Type = TypeUnspecified
+	case ip.Equal(net.IPv6loopback):
+		info.Type = TypeLoopback
+	case ip.To4() != nil:
+		info.Type = TypeV4Mapped
+	case ip[0] == 0xff:
+		info.Type = TypeMulticast
+		info.Scope = MulticastScope(ip[1] & 0xf)
+		info.Flags = MulticastFlags{
+			Transient:       ip[1]&0x10 != 0,
+			Prefix:          ip[1]&0x20 != 0,
+			RendezvousPoint: ip[1]&0x40 != 0,
+		}
+	case ip[0] == 0xfe && ip[1]&0xc0 == 0x80:
+		info.Type = TypeLinkLocal
+	case ip[0]&0xfe == 0xfc:
+		info.Type = TypeULA
+	case ip[0]&0xe0 == 0x20:
+		info.Type = TypeGUA
+	}
+	return info, true
+}
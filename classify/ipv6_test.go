@@ -0,0 +1,54 @@
+package classify
+
+import (
+	"net"
+	"testing"
+)
+
+func TestClassifyIPv6(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		want IPv6Info
+		ok   bool
+	}{
+		{net.ParseIP("::"), IPv6Info{Type: TypeUnspecified}, true},
+		{net.ParseIP("::1"), IPv6Info{Type: TypeLoopback}, true},
+		{net.ParseIP("::ffff:192.0.2.1"), IPv6Info{Type: TypeV4Mapped}, true},
+		{net.ParseIP("fe80::1"), IPv6Info{Type: TypeLinkLocal}, true},
+		{net.ParseIP("febf::1"), IPv6Info{Type: TypeLinkLocal}, true},
+		{net.ParseIP("fec0::1"), IPv6Info{Type: TypeOther}, true},
+		{net.ParseIP("fd00::1"), IPv6Info{Type: TypeULA}, true},
+		{net.ParseIP("2001:db8::1"), IPv6Info{Type: TypeGUA}, true},
+		{net.ParseIP("3fff::1"), IPv6Info{Type: TypeGUA}, true},
+		{net.ParseIP("4000::1"), IPv6Info{Type: TypeOther}, true},
+		{net.ParseIP("ff02::1"), IPv6Info{Type: TypeMulticast, Scope: ScopeLinkLocal}, true},
+		{net.ParseIP("ff0e::101"), IPv6Info{Type: TypeMulticast, Scope: ScopeGlobal}, true},
+		{net.ParseIP("ff15::1"), IPv6Info{TypeMulticast, ScopeSiteLocal, MulticastFlags{Transient: true}}, true},
+		{net.ParseIP("ff38:40:2001:db8::1"), IPv6Info{TypeMulticast, ScopeOrgLocal, MulticastFlags{Transient: true, Prefix: true}}, true},
+		{net.ParseIP("ff7e:140:2001:db8::1"), IPv6Info{TypeMulticast, ScopeGlobal, MulticastFlags{true, true, true}}, true},
+		{net.IP{192, 0, 2, 1}, IPv6Info{}, false},
+		{nil, IPv6Info{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := ClassifyIPv6(tt.ip); got != tt.want || ok != tt.ok {
+			t.Errorf("ClassifyIPv6(%v) = %+v, %v, want %+v, %v", tt.ip, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIPv6Strings(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{TypeGUA.String(), "global-unicast"},
+		{IPv6Type(42).String(), "IPv6Type(42)"},
+		{ScopeOrgLocal.String(), "organization-local"},
+		{MulticastScope(0xf).String(), "scope(0xf)"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("String() = %v, want %v", tt.got, tt.want)
+		}
+	}
+}
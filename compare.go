@@ -0,0 +1,55 @@
+package ipcalc
+
+import (
+	"bytes"
+	"cmp"
+	"net"
+	"slices"
+)
+
+// Compare returns -1, 0 or 1 depending on whether a sorts before, equal to or after b.
+// IPv4 addresses (including IPv4-mapped IPv6 addresses) sort before IPv6 addresses, invalid addresses sort first,
+// ordered by length and then by value.
+// e.g., Compare(192.0.2.1, ::1) -> -1.
+func Compare(a, b net.IP) int {
+	a = canonical(a)
+	b = canonical(b)
+	if c := cmp.Compare(validIPLen(a), validIPLen(b)); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return bytes.Compare(a, b)
+}
+
+// validIPLen returns 1 for 4-byte and 16-byte IP addresses, and 0 for any other length.
+func validIPLen(ip net.IP) int {
+	if len(ip) == net.IPv4len || len(ip) == net.IPv6len {
+		return 1
+	}
+	return 0
+}
+
+// SortIPs sorts a list of IP addresses in place in the order defined by Compare.
+func SortIPs(ips []net.IP) {
+	slices.SortFunc(ips, Compare)
+}
+
+// CompareNet returns -1, 0 or 1 depending on whether a sorts before, equal to or after b.
+// Networks are ordered by network address as in Compare, then by prefix length with shorter prefixes first.
+// If either net has a non-standard mask then the result is undefined.
+// e.g., CompareNet(192.0.2.0/24, 192.0.2.0/25) -> -1.
+func CompareNet(a, b net.IPNet) int {
+	if c := Compare(a.IP.Mask(a.Mask), b.IP.Mask(b.Mask)); c != 0 {
+		return c
+	}
+	aOnes, _ := a.Mask.Size()
+	bOnes, _ := b.Mask.Size()
+	return cmp.Compare(aOnes, bOnes)
+}
+
+// SortNets sorts a list of networks in place in the order defined by CompareNet.
+func SortNets(nets []net.IPNet) {
+	slices.SortFunc(nets, CompareNet)
+}
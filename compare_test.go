@@ -0,0 +1,96 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"192.0.2.1", "192.0.2.1", 0},
+		{"192.0.2.1", "::ffff:192.0.2.1", 0},
+		{"192.0.2.1", "192.0.2.2", -1},
+		{"192.0.2.2", "192.0.2.1", 1},
+		{"255.255.255.255", "::", -1},
+		{"::", "255.255.255.255", 1},
+		{"2001:db8::1", "2001:db8::", 1},
+		{"", "0.0.0.0", -1},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(net.ParseIP(tt.a), net.ParseIP(tt.b)); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+	invalid := []struct {
+		a, b net.IP
+		want int
+	}{
+		{net.IP{1, 2, 3, 4, 5}, net.ParseIP("0.0.0.0"), -1},
+		{net.ParseIP("::"), make(net.IP, 17), 1},
+		{make(net.IP, 17), net.ParseIP("255.255.255.255"), -1},
+		{net.IP{1, 2, 3}, net.IP{1, 2, 3, 4, 5}, -1},
+		{net.IP{1, 2, 3}, net.IP{1, 2, 3}, 0},
+	}
+	for _, tt := range invalid {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortIPs(t *testing.T) {
+	var ips []net.IP
+	for _, s := range []string{"2001:db8::1", "192.0.2.9", "::", "10.0.0.1", "::ffff:10.0.0.0", "2001:db8::"} {
+		ips = append(ips, net.ParseIP(s))
+	}
+	SortIPs(ips)
+	var got []string
+	for _, ip := range ips {
+		got = append(got, ip.String())
+	}
+	want := []string{"10.0.0.0", "10.0.0.1", "192.0.2.9", "::", "2001:db8::", "2001:db8::1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortIPs() = %v, want %v", got, want)
+	}
+}
+
+func TestCompareNet(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"192.0.2.0/24", "192.0.2.0/24", 0},
+		{"192.0.2.1/24", "192.0.2.0/24", 0},
+		{"192.0.2.0/24", "192.0.2.0/25", -1},
+		{"192.0.2.128/25", "192.0.2.0/24", 1},
+		{"192.0.2.0/24", "192.0.3.0/24", -1},
+		{"255.255.255.0/24", "::/0", -1},
+		{"2001:db8::/32", "2001:db8::/48", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareNet(mustCIDR(t, tt.a), mustCIDR(t, tt.b)); got != tt.want {
+			t.Errorf("CompareNet(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortNets(t *testing.T) {
+	var nets []net.IPNet
+	for _, s := range []string{"2001:db8::/48", "192.0.2.128/25", "10.0.0.0/8", "192.0.2.0/25", "2001:db8::/32", "192.0.2.0/24"} {
+		nets = append(nets, mustCIDR(t, s))
+	}
+	SortNets(nets)
+	var got []string
+	for _, n := range nets {
+		got = append(got, n.String())
+	}
+	want := []string{"10.0.0.0/8", "192.0.2.0/24", "192.0.2.0/25", "192.0.2.128/25", "2001:db8::/32", "2001:db8::/48"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortNets() = %v, want %v", got, want)
+	}
+}
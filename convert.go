@@ -0,0 +1,41 @@
+package ipcalc
+
+import (
+	"encoding/binary"
+	"math/big"
+	"net"
+)
+
+// IPToUint32 returns the numeric value of an IPv4 address, ok is false if ip isn't IPv4.
+// e.g., IPToUint32(192.0.2.1) -> 3221225985, true.
+func IPToUint32(ip net.IP) (uint32, bool) {
+	ip = ip.To4()
+	if ip == nil {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(ip), true
+}
+
+// Uint32ToIP returns the IPv4 address for a numeric value.
+// e.g., Uint32ToIP(3221225985) -> 192.0.2.1.
+func Uint32ToIP(v uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, v)
+	return ip
+}
+
+// IPToBig returns the numeric value of an IP address, IPv4 addresses are treated as 32-bit values.
+// e.g., IPToBig(2001:db8::1) -> 42540766411282592856903984951653826561.
+func IPToBig(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(IP(ip))
+}
+
+// BigToIP returns the IP address of the given byte length (net.IPv4len or net.IPv6len) for a numeric value,
+// ok is false if the value doesn't fit in the address space.
+// e.g., BigToIP(42540766411282592856903984951653826561, 16) -> 2001:db8::1, true.
+func BigToIP(v *big.Int, size int) (net.IP, bool) {
+	if size != net.IPv4len && size != net.IPv6len || v.Sign() < 0 || v.BitLen() > 8*size {
+		return nil, false
+	}
+	return v.FillBytes(make(net.IP, size)), true
+}
@@ -0,0 +1,73 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+	"testing"
+)
+
+func TestIPToUint32(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want uint32
+		ok   bool
+	}{
+		{"0.0.0.0", 0, true},
+		{"192.0.2.1", 3221225985, true},
+		{"::ffff:192.0.2.1", 3221225985, true},
+		{"255.255.255.255", 0xffffffff, true},
+		{"2001:db8::1", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := IPToUint32(net.ParseIP(tt.ip))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("IPToUint32(%v) = %v, %v, want %v, %v", tt.ip, got, ok, tt.want, tt.ok)
+		}
+		if !ok {
+			continue
+		}
+		if ip := Uint32ToIP(got); !ip.Equal(net.ParseIP(tt.ip)) || len(ip) != net.IPv4len {
+			t.Errorf("Uint32ToIP(%v) = %v, want %v", got, ip, tt.ip)
+		}
+	}
+}
+
+func TestIPToBig(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+		size int
+	}{
+		{"192.0.2.1", "3221225985", net.IPv4len},
+		{"::ffff:192.0.2.1", "3221225985", net.IPv4len},
+		{"::", "0", net.IPv6len},
+		{"2001:db8::1", "42540766411282592856903984951653826561", net.IPv6len},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "340282366920938463463374607431768211455", net.IPv6len},
+	}
+	for _, tt := range tests {
+		got := IPToBig(net.ParseIP(tt.ip))
+		if got.String() != tt.want {
+			t.Errorf("IPToBig(%v) = %v, want %v", tt.ip, got, tt.want)
+		}
+		if ip, ok := BigToIP(got, tt.size); !ok || !ip.Equal(net.ParseIP(tt.ip)) || len(ip) != tt.size {
+			t.Errorf("BigToIP(%v, %v) = %v, %v, want %v, true", got, tt.size, ip, ok, tt.ip)
+		}
+	}
+}
+
+func TestBigToIPInvalid(t *testing.T) {
+	tests := []struct {
+		v    *big.Int
+		size int
+	}{
+		{big.NewInt(-1), net.IPv4len},
+		{big.NewInt(1 << 32), net.IPv4len},
+		{new(big.Int).Lsh(big.NewInt(1), 128), net.IPv6len},
+		{big.NewInt(1), 8},
+	}
+	for _, tt := range tests {
+		if ip, ok := BigToIP(tt.v, tt.size); ok {
+			t.Errorf("BigToIP(%v, %v) = %v, true, want false", tt.v, tt.size, ip)
+		}
+	}
+}
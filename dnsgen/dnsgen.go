@@ -0,0 +1,170 @@
+// Package dnsgen generates BIND zone file records for the usable hosts of a network.
+//
+// Hostnames are built from a template, which should be fully qualified, with these placeholders:
+//   - {ip} is the host address with its separators replaced by dashes, e.g., 192-0-2-1 or 2001-db8-0-0-0-0-0-1
+//   - {index} is the zero-based host index, see ipcalc.NthHost
+//
+// e.g., PTR(192.0.2.0/30, "host-{ip}.example.com.") ->
+//
+//	1.2.0.192.in-addr.arpa.	IN	PTR	host-192-0-2-1.example.com.
+//	2.2.0.192.in-addr.arpa.	IN	PTR	host-192-0-2-2.example.com.
+package dnsgen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// MaxRecords is the maximum number of records generated for a network.
+const MaxRecords = 1 << 16
+
+// ErrEmptyTemplate is returned when the hostname template is empty.
+var ErrEmptyTemplate = errors.New("dnsgen: empty hostname template")
+
+// Record is a resource record in the IN class.
+type Record struct {
+	Name string
+	Type string
+	Data string
+}
+
+// String returns the Record in zone file format.
+func (r Record) String() string {
+	return r.Name + "\tIN\t" + r.Type + "\t" + r.Data
+}
+
+// PTR returns the PTR records of the usable hosts of a network, in ascending address order.
+func PTR(n net.IPNet, template string) ([]Record, error) {
+	return records(n, template, func(ip net.IP, host string) Record {
+		return Record{Name: ipcalc.ReverseName(ip), Type: "PTR", Data: host}
+	})
+}
+
+// Forward returns the A or AAAA records of the usable hosts of a network, in ascending address order.
+func Forward(n net.IPNet, template string) ([]Record, error) {
+	return records(n, template, func(ip net.IP, host string) Record {
+		typ := "AAAA"
+		if ipcalc.IPVersion(ip) == 4 {
+			typ = "A"
+		}
+		return Record{Name: host, Type: typ, Data: ip.String()}
+	})
+}
+
+func records(n net.IPNet, template string, record func(ip net.IP, host string) Record) ([]Record, error) {
+	if template == "" {
+		return nil, ErrEmptyTemplate
+	}
+	count := ipcalc.UsableHosts(n)
+	if count.Sign() == 0 {
+		return nil, fmt.Errorf("dnsgen: no usable hosts in %v", &n)
+	}
+	if count.Cmp(big.NewInt(MaxRecords)) > 0 {
+		return nil, fmt.Errorf("dnsgen: %v has more than %v hosts", &n, MaxRecords)
+	}
+	var recs []Record
+	i := 0
+	for ip := range ipcalc.Hosts(n) {
+		recs = append(recs, record(ip, expand(template, dashed(ip), strconv.Itoa(i))))
+		i++
+	}
+	return recs, nil
+}
+
+// GeneratePTR returns BIND $GENERATE directives for the PTR records of the usable hosts of an IPv4 network,
+// each preceded by an $ORIGIN directive for its /24, or RFC 2317 zone for networks longer than /24.
+// e.g., GeneratePTR(192.0.2.0/24, "host-{ip}.example.com.") ->
+//
+//	$ORIGIN 2.0.192.in-addr.arpa.
+//	$GENERATE 1-254 $ PTR host-192-0-2-$.example.com.
+func GeneratePTR(n net.IPNet, template string) ([]string, error) {
+	var lines []string
+	err := generate(n, template, func(zone net.IPNet, lo, hi int, host string) {
+		lines = append(lines,
+			"$ORIGIN "+ipcalc.ReverseZones(zone)[0],
+			fmt.Sprintf("$GENERATE %v-%v $ PTR %v", lo, hi, host))
+	})
+	return lines, err
+}
+
+// GenerateForward returns BIND $GENERATE directives for the A records of the usable hosts of an IPv4 network,
+// one per /24.
+// e.g., GenerateForward(192.0.2.0/24, "host-{ip}.example.com.") ->
+//
+//	$GENERATE 1-254 host-192-0-2-$.example.com. A 192.0.2.$
+func GenerateForward(n net.IPNet, template string) ([]string, error) {
+	var lines []string
+	err := generate(n, template, func(zone net.IPNet, lo, hi int, host string) {
+		ip := zone.IP.To4()
+		lines = append(lines, fmt.Sprintf("$GENERATE %v-%v %v A %v.%v.%v.$", lo, hi, host, ip[0], ip[1], ip[2]))
+	})
+	return lines, err
+}
+
+// generate calls emit for every /24 (or smaller) zone of an IPv4 network with its range of usable host octets
+// and the hostname template expanded for $GENERATE.
+func generate(n net.IPNet, template string, emit func(zone net.IPNet, lo, hi int, host string)) error {
+	if template == "" {
+		return ErrEmptyTemplate
+	}
+	// IPv4-mapped networks are split by their IPv4 prefix length.
+	norm := ipcalc.Normalize(n)
+	first, last := ipcalc.FirstHost(norm), ipcalc.LastHost(norm)
+	if first == nil || ipcalc.IPVersion(first) != 4 {
+		return fmt.Errorf("dnsgen: %v is not an IPv4 network", &n)
+	}
+	ones, _ := norm.Mask.Size()
+	zones := []net.IPNet{norm}
+	if ones < 24 {
+		var err error
+		if zones, err = ipcalc.Split(norm, 24); err != nil {
+			return fmt.Errorf("dnsgen: %w", err)
+		}
+	}
+	index := 0
+	for _, z := range zones {
+		lo, hi := z.IP.Mask(z.Mask).To4(), ipcalc.Broadcast(z)
+		if ipcalc.Compare(lo, first) < 0 {
+			lo = first
+		}
+		if ipcalc.Compare(hi, last) > 0 {
+			hi = last
+		}
+		ip := lo.To4()
+		prefix := fmt.Sprintf("%v-%v-%v-", ip[0], ip[1], ip[2])
+		emit(z, int(lo[3]), int(hi[3]), expand(template, prefix+"$", generateOffset(index-int(lo[3]))))
+		index += int(hi[3]) - int(lo[3]) + 1
+	}
+	return nil
+}
+
+// generateOffset returns the $GENERATE iterator with an offset.
+func generateOffset(offset int) string {
+	if offset == 0 {
+		return "$"
+	}
+	return "${" + strconv.Itoa(offset) + "}"
+}
+
+func expand(template, ip, index string) string {
+	return strings.NewReplacer("{ip}", ip, "{index}", index).Replace(template)
+}
+
+// dashed returns an address with its separators replaced by dashes, IPv6 addresses aren't compressed
+// as that could yield labels starting or ending with a dash.
+func dashed(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return strings.ReplaceAll(ip4.String(), ".", "-")
+	}
+	groups := make([]string, net.IPv6len/2)
+	for i := range groups {
+		groups[i] = strconv.FormatUint(uint64(ip[2*i])<<8|uint64(ip[2*i+1]), 16)
+	}
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,137 @@
+package dnsgen
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func recordStrings(recs []Record) []string {
+	var s []string
+	for _, r := range recs {
+		s = append(s, r.String())
+	}
+	return s
+}
+
+func TestPTR(t *testing.T) {
+	tests := []struct {
+		n        string
+		template string
+		want     []string
+	}{
+		{"192.0.2.0/30", "host-{ip}.example.com.", []string{
+			"1.2.0.192.in-addr.arpa.\tIN\tPTR\thost-192-0-2-1.example.com.",
+			"2.2.0.192.in-addr.arpa.\tIN\tPTR\thost-192-0-2-2.example.com.",
+		}},
+		{"192.0.2.8/31", "h{index}.example.com.", []string{
+			"8.2.0.192.in-addr.arpa.\tIN\tPTR\th0.example.com.",
+			"9.2.0.192.in-addr.arpa.\tIN\tPTR\th1.example.com.",
+		}},
+		{"2001:db8::/127", "{ip}.example.com.", []string{
+			"0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.\tIN\tPTR\t2001-db8-0-0-0-0-0-0.example.com.",
+			"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.\tIN\tPTR\t2001-db8-0-0-0-0-0-1.example.com.",
+		}},
+	}
+	for _, tt := range tests {
+		recs, err := PTR(mustCIDR(t, tt.n), tt.template)
+		if got := recordStrings(recs); err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PTR(%v, %v) = %q, %v, want %q", tt.n, tt.template, got, err, tt.want)
+		}
+	}
+}
+
+func TestForward(t *testing.T) {
+	tests := []struct {
+		n    string
+		want []string
+	}{
+		{"192.0.2.4/30", []string{
+			"host-192-0-2-5.example.com.\tIN\tA\t192.0.2.5",
+			"host-192-0-2-6.example.com.\tIN\tA\t192.0.2.6",
+		}},
+		{"2001:db8::ff/128", []string{"host-2001-db8-0-0-0-0-0-ff.example.com.\tIN\tAAAA\t2001:db8::ff"}},
+	}
+	for _, tt := range tests {
+		recs, err := Forward(mustCIDR(t, tt.n), "host-{ip}.example.com.")
+		if got := recordStrings(recs); err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Forward(%v) = %q, %v, want %q", tt.n, got, err, tt.want)
+		}
+	}
+}
+
+func TestRecordsErrors(t *testing.T) {
+	if _, err := PTR(mustCIDR(t, "192.0.2.0/24"), ""); !errors.Is(err, ErrEmptyTemplate) {
+		t.Errorf("PTR(192.0.2.0/24, \"\") error = %v, want %v", err, ErrEmptyTemplate)
+	}
+	if _, err := Forward(mustCIDR(t, "10.0.0.0/8"), "{ip}."); err == nil {
+		t.Errorf("Forward(10.0.0.0/8) error = nil, want error")
+	}
+	if _, err := PTR(net.IPNet{}, "{ip}."); err == nil {
+		t.Errorf("PTR(<nil>) error = nil, want error")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		n        string
+		template string
+		ptr      []string
+		forward  []string
+	}{
+		{"192.0.2.0/24", "host-{ip}.example.com.",
+			[]string{"$ORIGIN 2.0.192.in-addr.arpa.", "$GENERATE 1-254 $ PTR host-192-0-2-$.example.com."},
+			[]string{"$GENERATE 1-254 host-192-0-2-$.example.com. A 192.0.2.$"}},
+		{"192.0.2.64/26", "h{index}.example.com.",
+			[]string{"$ORIGIN 64/26.2.0.192.in-addr.arpa.", "$GENERATE 65-126 $ PTR h${-65}.example.com."},
+			[]string{"$GENERATE 65-126 h${-65}.example.com. A 192.0.2.$"}},
+		{"192.0.2.0/23", "h{index}.example.com.",
+			[]string{
+				"$ORIGIN 2.0.192.in-addr.arpa.", "$GENERATE 1-255 $ PTR h${-1}.example.com.",
+				"$ORIGIN 3.0.192.in-addr.arpa.", "$GENERATE 0-254 $ PTR h${255}.example.com.",
+			},
+			[]string{
+				"$GENERATE 1-255 h${-1}.example.com. A 192.0.2.$",
+				"$GENERATE 0-254 h${255}.example.com. A 192.0.3.$",
+			}},
+	}
+	for _, tt := range tests {
+		n := mustCIDR(t, tt.n)
+		if got, err := GeneratePTR(n, tt.template); err != nil || !reflect.DeepEqual(got, tt.ptr) {
+			t.Errorf("GeneratePTR(%v, %v) = %q, %v, want %q", tt.n, tt.template, got, err, tt.ptr)
+		}
+		if got, err := GenerateForward(n, tt.template); err != nil || !reflect.DeepEqual(got, tt.forward) {
+			t.Errorf("GenerateForward(%v, %v) = %q, %v, want %q", tt.n, tt.template, got, err, tt.forward)
+		}
+	}
+	// IPv4-mapped networks generate the same directives as their IPv4 counterparts.
+	for _, tt := range []struct{ mapped, n string }{
+		{"::ffff:192.0.2.0/120", "192.0.2.0/24"},
+		{"::ffff:192.0.2.0/119", "192.0.2.0/23"},
+		{"::ffff:192.0.2.0/118", "192.0.0.0/22"},
+		{"::ffff:10.0.0.0/104", "10.0.0.0/8"},
+	} {
+		for name, fn := range map[string]func(net.IPNet, string) ([]string, error){"GeneratePTR": GeneratePTR, "GenerateForward": GenerateForward} {
+			got, err := fn(mustCIDR(t, tt.mapped), "h{index}.example.com.")
+			want, _ := fn(mustCIDR(t, tt.n), "h{index}.example.com.")
+			if err != nil || len(want) == 0 || !reflect.DeepEqual(got, want) {
+				t.Errorf("%v(%v) = %v lines, %v, want %v lines", name, tt.mapped, len(got), err, len(want))
+			}
+		}
+	}
+	for _, n := range []string{"2001:db8::/120", "10.0.0.0/7"} {
+		if _, err := GeneratePTR(mustCIDR(t, n), "{ip}."); err == nil {
+			t.Errorf("GeneratePTR(%v) error = nil, want error", n)
+		}
+	}
+}
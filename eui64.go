@@ -0,0 +1,55 @@
+package ipcalc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// universalLocal is the universal/local bit of the first byte of a MAC address, inverted in modified EUI-64.
+const universalLocal = 0x02
+
+// EUI64FromMAC returns the modified EUI-64 interface identifier of a 48-bit MAC address or 64-bit EUI-64
+// (RFC 4291, appendix A), ok is false for other lengths.
+// e.g., EUI64FromMAC(00:11:22:33:44:55) -> 0x021122fffe334455, true.
+func EUI64FromMAC(hw net.HardwareAddr) (uint64, bool) {
+	var b [8]byte
+	switch len(hw) {
+	case 6:
+		copy(b[:3], hw[:3])
+		b[3], b[4] = 0xff, 0xfe
+		copy(b[5:], hw[3:])
+	case 8:
+		copy(b[:], hw)
+	default:
+		return 0, false
+	}
+	b[0] ^= universalLocal
+	return binary.BigEndian.Uint64(b[:]), true
+}
+
+// MACFromEUI64 returns the 48-bit MAC address of an IPv6 address with a modified EUI-64 interface identifier,
+// ok is false if the identifier wasn't derived from a MAC address, i.e., it lacks the ff:fe marker.
+// e.g., MACFromEUI64(fe80::211:22ff:fe33:4455) -> 00:11:22:33:44:55, true.
+func MACFromEUI64(ip net.IP) (net.HardwareAddr, bool) {
+	if len(ip) != net.IPv6len || ip.To4() != nil || ip[11] != 0xff || ip[12] != 0xfe {
+		return nil, false
+	}
+	hw := net.HardwareAddr{ip[8] ^ universalLocal, ip[9], ip[10], ip[13], ip[14], ip[15]}
+	return hw, true
+}
+
+// SLAACAddr returns the SLAAC address for a MAC address in an IPv6 /64 prefix, see EUI64FromMAC.
+// e.g., SLAACAddr(2001:db8::/64, 00:11:22:33:44:55) -> 2001:db8::211:22ff:fe33:4455.
+func SLAACAddr(prefix net.IPNet, hw net.HardwareAddr) (net.IP, error) {
+	if ones, _ := prefix.Mask.Size(); ones != 64 || !Respects64(prefix) {
+		return nil, fmt.Errorf("ipcalc: %v is not an IPv6 /64 prefix", &prefix)
+	}
+	iid, ok := EUI64FromMAC(hw)
+	if !ok {
+		return nil, fmt.Errorf("ipcalc: invalid MAC address %v", hw)
+	}
+	ip := prefix.IP.To16().Mask(prefix.Mask)
+	binary.BigEndian.PutUint64(ip[8:], iid)
+	return ip, nil
+}
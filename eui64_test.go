@@ -0,0 +1,59 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestEUI64(t *testing.T) {
+	tests := []struct {
+		hw   string
+		iid  uint64
+		addr string
+	}{
+		{"00:11:22:33:44:55", 0x021122fffe334455, "2001:db8::211:22ff:fe33:4455"},
+		{"02:00:5e:10:00:00", 0x00005efffe100000, "2001:db8::5eff:fe10:0"},
+		{"ff:ff:ff:ff:ff:ff", 0xfdfffffffeffffff, "2001:db8::fdff:ffff:feff:ffff"},
+	}
+	prefix := mustCIDR(t, "2001:db8::/64")
+	for _, tt := range tests {
+		hw, err := net.ParseMAC(tt.hw)
+		if err != nil {
+			t.Fatalf("ParseMAC(%v) error = %v", tt.hw, err)
+		}
+		if got, ok := EUI64FromMAC(hw); !ok || got != tt.iid {
+			t.Errorf("EUI64FromMAC(%v) = %#x, %v, want %#x", hw, got, ok, tt.iid)
+		}
+		addr, err := SLAACAddr(prefix, hw)
+		if err != nil || !addr.Equal(net.ParseIP(tt.addr)) {
+			t.Errorf("SLAACAddr(%v, %v) = %v, %v, want %v", &prefix, hw, addr, err, tt.addr)
+		}
+		if got, ok := MACFromEUI64(addr); !ok || got.String() != hw.String() {
+			t.Errorf("MACFromEUI64(%v) = %v, %v, want %v", addr, got, ok, hw)
+		}
+	}
+}
+
+func TestEUI64Errors(t *testing.T) {
+	if got, ok := EUI64FromMAC(net.HardwareAddr{1, 2, 3}); ok {
+		t.Errorf("EUI64FromMAC(01:02:03) = %#x, true, want false", got)
+	}
+	eui, _ := net.ParseMAC("02:11:22:33:44:55:66:77")
+	if got, ok := EUI64FromMAC(eui); !ok || got != 0x0011223344556677 {
+		t.Errorf("EUI64FromMAC(%v) = %#x, %v, want 0x11223344556677", eui, got, ok)
+	}
+	for _, ip := range []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1"), nil} {
+		if got, ok := MACFromEUI64(ip); ok {
+			t.Errorf("MACFromEUI64(%v) = %v, true, want false", ip, got)
+		}
+	}
+	hw, _ := net.ParseMAC("00:11:22:33:44:55")
+	for _, p := range []string{"2001:db8::/48", "2001:db8::/96", "192.0.2.0/24"} {
+		if got, err := SLAACAddr(mustCIDR(t, p), hw); err == nil {
+			t.Errorf("SLAACAddr(%v, %v) = %v, want error", p, hw, got)
+		}
+	}
+	if got, err := SLAACAddr(mustCIDR(t, "2001:db8::/64"), nil); err == nil {
+		t.Errorf("SLAACAddr(2001:db8::/64, nil) = %v, want error", got)
+	}
+}
@@ -0,0 +1,174 @@
+// Package exercise generates randomized IPv4 subnetting practice problems along with their answer keys.
+//
+// Generators are seeded, the same seed always produces the same sequence of problems:
+//
+//	g := exercise.New(42)
+//	p := g.Subnet()
+//	fmt.Println(p.Question())
+//	fmt.Println(p.Answer.Network, p.Answer.Broadcast)
+package exercise
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/vlsm"
+)
+
+// Bounds for generated problems.
+const (
+	MinPrefixLen       = 8
+	MaxPrefixLen       = 30
+	MinVLSMPrefixLen   = 20
+	MaxVLSMPrefixLen   = 26
+	MaxVLSMRequirement = 6
+)
+
+// privateNets are the parent networks VLSM problems are drawn from.
+var privateNets = []net.IPNet{
+	{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+	{IP: net.IP{172, 16, 0, 0}, Mask: net.CIDRMask(12, 32)},
+	{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(16, 32)},
+}
+
+// Generator produces practice problems, it's not safe for concurrent use.
+type Generator struct {
+	rnd *rand.Rand
+}
+
+// New returns a Generator seeded with seed.
+func New(seed int64) *Generator {
+	return &Generator{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// SubnetProblem asks for the properties of the subnet containing an address.
+type SubnetProblem struct {
+	Address   net.IP
+	PrefixLen int
+	Answer    SubnetAnswer
+}
+
+// SubnetAnswer is the answer key for a SubnetProblem.
+type SubnetAnswer struct {
+	Network   net.IP
+	Broadcast net.IP
+	Mask      net.IPMask
+	FirstHost net.IP
+	LastHost  net.IP
+	Hosts     int
+}
+
+// Question returns the problem statement.
+func (p SubnetProblem) Question() string {
+	return fmt.Sprintf("Given %v/%v, what are the network address, broadcast address, subnet mask, first and last usable hosts, and number of usable hosts?", p.Address, p.PrefixLen)
+}
+
+// Subnet returns a problem for a random unicast address and prefix length.
+func (g *Generator) Subnet() SubnetProblem {
+	ip := net.IP{byte(1 + g.rnd.Intn(223)), byte(g.rnd.Intn(256)), byte(g.rnd.Intn(256)), byte(g.rnd.Intn(256))}
+	for ip[0] == 127 {
+		ip[0] = byte(1 + g.rnd.Intn(223))
+	}
+	prefixLen := MinPrefixLen + g.rnd.Intn(MaxPrefixLen-MinPrefixLen+1)
+	mask := net.CIDRMask(prefixLen, 8*net.IPv4len)
+	n := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	first, _ := ipcalc.ReserveNetworkBroadcast.FirstHost(n)
+	last, _ := ipcalc.ReserveNetworkBroadcast.LastHost(n)
+	return SubnetProblem{
+		Address:   ip,
+		PrefixLen: prefixLen,
+		Answer: SubnetAnswer{
+			Network:   n.IP,
+			Broadcast: ipcalc.Broadcast(n),
+			Mask:      mask,
+			FirstHost: first,
+			LastHost:  last,
+			Hosts:     int(ipcalc.ReserveNetworkBroadcast.UsableHosts(n).Int64()),
+		},
+	}
+}
+
+// Requirement is a named subnet needing room for a number of hosts.
+type Requirement struct {
+	Name  string
+	Hosts int
+}
+
+// Allocation is a subnet assigned to a Requirement.
+type Allocation struct {
+	Name   string
+	Subnet net.IPNet
+}
+
+// VLSMProblem asks for a variable-length subnetting plan within a parent network.
+type VLSMProblem struct {
+	Parent       net.IPNet
+	Requirements []Requirement
+	// Answer allocates the largest requirements first, each in the lowest free subnet.
+	Answer []Allocation
+}
+
+// Question returns the problem statement.
+func (p VLSMProblem) Question() string {
+	reqs := make([]string, len(p.Requirements))
+	for i, r := range p.Requirements {
+		reqs[i] = fmt.Sprintf("%v needs %v hosts", r.Name, r.Hosts)
+	}
+	return fmt.Sprintf("Subnet %v using VLSM, allocating the largest subnets first: %v.", &p.Parent, strings.Join(reqs, ", "))
+}
+
+// VLSM returns a problem with up to MaxVLSMRequirement requirements which always fit in the parent network.
+func (g *Generator) VLSM() VLSMProblem {
+	base := privateNets[g.rnd.Intn(len(privateNets))]
+	prefixLen := MinVLSMPrefixLen + g.rnd.Intn(MaxVLSMPrefixLen-MinVLSMPrefixLen+1)
+	mask := net.CIDRMask(prefixLen, 8*net.IPv4len)
+	parent := ipcalc.Merge(base.IP, ipcalc.Uint32ToIP(g.rnd.Uint32()), ipcalc.Complement(base.Mask))
+	p := VLSMProblem{Parent: net.IPNet{IP: parent.Mask(mask), Mask: mask}}
+	free := 1 << uint(8*net.IPv4len-prefixLen)
+	count := 2 + g.rnd.Intn(MaxVLSMRequirement-1)
+	for i := 0; i < count && free >= 8; i++ {
+		// A subnet between /30 and half the remaining space.
+		maxBits := 2
+		for 1<<uint(maxBits+1) <= free/2 {
+			maxBits++
+		}
+		size := 1 << uint(2+g.rnd.Intn(maxBits-1))
+		free -= size
+		p.Requirements = append(p.Requirements, Requirement{
+			Name: fmt.Sprintf("LAN%v", i+1),
+			// Any host count needing exactly this subnet size.
+			Hosts: size/2 - 1 + g.rnd.Intn(size/2),
+		})
+	}
+	answer, err := Plan(p.Parent, p.Requirements)
+	if err != nil {
+		// The requirements are power of two sized and sum to at most the parent size, so they always fit.
+		panic(err)
+	}
+	p.Answer = answer
+	return p
+}
+
+// Plan allocates requirements largest first, each in the lowest free subnet of an IPv4 parent network, see vlsm.Allocate.
+// Allocations are returned in allocation order, an error is returned if any requirement doesn't fit.
+func Plan(parent net.IPNet, reqs []Requirement) ([]Allocation, error) {
+	if _, bits := ipcalc.Normalize(parent).Mask.Size(); bits != 8*net.IPv4len {
+		return nil, fmt.Errorf("exercise: %v isn't an IPv4 network", &parent)
+	}
+	vreqs := make([]vlsm.Requirement, len(reqs))
+	for i, r := range reqs {
+		vreqs[i] = vlsm.Requirement{Name: r.Name, Hosts: r.Hosts}
+	}
+	p, err := vlsm.Allocate(parent, vreqs)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]Allocation, len(p.Allocations))
+	for i, a := range p.Allocations {
+		plan[i] = Allocation{Name: a.Name, Subnet: a.Subnet}
+	}
+	return plan, nil
+}
@@ -0,0 +1,105 @@
+package exercise
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/vlsm"
+)
+
+func TestSubnet(t *testing.T) {
+	g := New(1)
+	for i := 0; i < 1000; i++ {
+		p := g.Subnet()
+		if p.PrefixLen < MinPrefixLen || p.PrefixLen > MaxPrefixLen {
+			t.Fatalf("Subnet() prefix length = %v", p.PrefixLen)
+		}
+		n := net.IPNet{IP: p.Answer.Network, Mask: p.Answer.Mask}
+		if !n.Contains(p.Address) || !n.Contains(p.Answer.Broadcast) || !n.Contains(p.Answer.FirstHost) || !n.Contains(p.Answer.LastHost) {
+			t.Errorf("Subnet() = %+v, addresses outside %v", p, &n)
+		}
+		if want := 1<<uint(32-p.PrefixLen) - 2; p.Answer.Hosts != want {
+			t.Errorf("Subnet(%v/%v) hosts = %v, want %v", p.Address, p.PrefixLen, p.Answer.Hosts, want)
+		}
+		if !ipcalc.NextIP(p.Answer.Network).Equal(p.Answer.FirstHost) || !ipcalc.PrevIP(p.Answer.Broadcast).Equal(p.Answer.LastHost) {
+			t.Errorf("Subnet(%v/%v) = %+v, wrong host range", p.Address, p.PrefixLen, p.Answer)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	a, b := New(42), New(42)
+	for i := 0; i < 10; i++ {
+		if x, y := a.Subnet(), b.Subnet(); !reflect.DeepEqual(x, y) {
+			t.Errorf("Subnet() = %+v, %+v with the same seed", x, y)
+		}
+		if x, y := a.VLSM(), b.VLSM(); !reflect.DeepEqual(x, y) {
+			t.Errorf("VLSM() = %+v, %+v with the same seed", x, y)
+		}
+	}
+}
+
+func TestVLSM(t *testing.T) {
+	g := New(1)
+	for i := 0; i < 1000; i++ {
+		p := g.VLSM()
+		if len(p.Requirements) < 2 || len(p.Answer) != len(p.Requirements) {
+			t.Fatalf("VLSM() = %+v, want every requirement allocated", p)
+		}
+		hosts := make(map[string]int)
+		for _, r := range p.Requirements {
+			hosts[r.Name] = r.Hosts
+		}
+		for j, a := range p.Answer {
+			if !p.Parent.Contains(a.Subnet.IP) || !p.Parent.Contains(ipcalc.Broadcast(a.Subnet)) {
+				t.Errorf("VLSM() allocation %v outside %v", a.Subnet.String(), p.Parent.String())
+			}
+			ones, _ := a.Subnet.Mask.Size()
+			if size := 1<<uint(32-ones) - 2; size < hosts[a.Name] || size/2-2 >= hosts[a.Name] && ones < 30 {
+				t.Errorf("VLSM() allocation %v for %v hosts", a.Subnet.String(), hosts[a.Name])
+			}
+			if j > 0 && !ipcalc.NextIP(ipcalc.Broadcast(p.Answer[j-1].Subnet)).Equal(a.Subnet.IP) {
+				t.Errorf("VLSM() allocations %v, %v not contiguous", p.Answer[j-1].Subnet.String(), a.Subnet.String())
+			}
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	_, parent, _ := net.ParseCIDR("192.168.1.0/24")
+	reqs := []Requirement{{"A", 20}, {"B", 100}, {"D", 50}, {"E", 30}}
+	want := map[string]string{
+		"B": "192.168.1.0/25",
+		"D": "192.168.1.128/26",
+		"A": "192.168.1.192/27",
+		"E": "192.168.1.224/27",
+	}
+	plan, err := Plan(*parent, reqs)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	got := make(map[string]string)
+	for _, a := range plan {
+		got[a.Name] = a.Subnet.String()
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan() = %v, want %v", got, want)
+	}
+	if plan, err := Plan(*parent, append(reqs, Requirement{"C", 2})); !errors.Is(err, vlsm.ErrNoSpace) {
+		t.Errorf("Plan() = %v, %v, want %v", plan, err, vlsm.ErrNoSpace)
+	}
+	_, v6, _ := net.ParseCIDR("2001:db8::/64")
+	if plan, err := Plan(*v6, reqs); err == nil {
+		t.Errorf("Plan(%v) = %v, want error", v6, plan)
+	}
+}
+
+func TestQuestion(t *testing.T) {
+	g := New(7)
+	if g.Subnet().Question() == "" || g.VLSM().Question() == "" {
+		t.Errorf("Question() is empty")
+	}
+}
@@ -0,0 +1,152 @@
+package ipcalc
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// FormatOption controls the text form of IPv6 addresses in Format, options can be combined with |.
+// IPv4 addresses, including IPv4-mapped IPv6 addresses, are always formatted in dotted decimal.
+type FormatOption uint
+
+// Format options.
+const (
+	// Uppercase uses uppercase hexadecimal digits.
+	Uppercase FormatOption = 1 << iota
+	// NoCompression doesn't replace runs of zero groups with "::".
+	NoCompression
+	// ZeroPad pads every group to 4 hexadecimal digits.
+	ZeroPad
+)
+
+// Format returns the text form of an IP address with the given options, or "" if it's invalid.
+// The zero value yields the RFC 5952 canonical form.
+// e.g., Format(2001:db8::1, Uppercase|ZeroPad) -> 2001:0DB8::0001.
+func Format(ip net.IP, opts FormatOption) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.String()
+	}
+	if len(ip) != net.IPv6len {
+		return ""
+	}
+	var groups [net.IPv6len / 2]uint16
+	for i := range groups {
+		groups[i] = uint16(ip[2*i])<<8 | uint16(ip[2*i+1])
+	}
+	start, end := -1, -1
+	if opts&NoCompression == 0 {
+		start, end = longestZeroRun(groups[:])
+	}
+	var b strings.Builder
+	for i := 0; i < len(groups); i++ {
+		if i == start {
+			b.WriteString("::")
+			i = end - 1
+			continue
+		}
+		if i > 0 && i != end {
+			b.WriteByte(':')
+		}
+		s := strconv.FormatUint(uint64(groups[i]), 16)
+		if opts&ZeroPad != 0 {
+			b.WriteString(strings.Repeat("0", 4-len(s)))
+		}
+		b.WriteString(s)
+	}
+	if opts&Uppercase != 0 {
+		return strings.ToUpper(b.String())
+	}
+	return b.String()
+}
+
+// Compress returns the RFC 5952 canonical text form of an IP address, or "" if it's invalid.
+// e.g., Compress(2001:0db8:0000:0000:0001:0000:0000:0001) -> 2001:db8::1:0:0:1.
+func Compress(ip net.IP) string {
+	return Format(ip, 0)
+}
+
+// Expand returns the fully expanded text form of an IP address, or "" if it's invalid.
+// e.g., Expand(2001:db8::1) -> 2001:0db8:0000:0000:0000:0000:0000:0001.
+func Expand(ip net.IP) string {
+	return Format(ip, NoCompression|ZeroPad)
+}
+
+// longestZeroRun returns the bounds [start, end) of the first longest run of at least two zero groups,
+// or -1, -1 if there is none (RFC 5952, section 4.2).
+func longestZeroRun(groups []uint16) (int, int) {
+	start, end := -1, -1
+	for i := 0; i < len(groups); i++ {
+		j := i
+		for j < len(groups) && groups[j] == 0 {
+			j++
+		}
+		if j-i >= 2 && j-i > end-start {
+			start, end = i, j
+		}
+		i = j
+	}
+	return start, end
+}
+
+// Binary returns the binary form of an IP address or mask, in dotted octets for IPv4 and hextets for IPv6,
+// or "" if it's invalid. Masks can be passed as net.IP(mask).
+// e.g., Binary(192.0.2.1) -> 11000000.00000000.00000010.00000001.
+func Binary(ip net.IP) string {
+	return BinaryWithPrefix(ip, -1)
+}
+
+// BinaryWithPrefix is like Binary but inserts a space after the first ones bits, like ipcalc(1),
+// no space is inserted if ones is out of range.
+// e.g., BinaryWithPrefix(192.0.2.1, 26) -> 11000000.00000000.00000010.00 000001.
+func BinaryWithPrefix(ip net.IP, ones int) string {
+	ip = canonical(ip)
+	sep, groupBits := byte('.'), 8
+	switch len(ip) {
+	case net.IPv4len:
+	case net.IPv6len:
+		sep, groupBits = ':', 16
+	default:
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i < 8*len(ip); i++ {
+		if i > 0 && i%groupBits == 0 {
+			b.WriteByte(sep)
+		}
+		if i == ones {
+			b.WriteByte(' ')
+		}
+		b.WriteByte('0' + ip[i/8]>>(7-uint(i%8))&1)
+	}
+	return b.String()
+}
+
+// Hex returns the zero-padded hexadecimal form of an IP address or mask, in dotted octets for IPv4
+// and hextets for IPv6, or "" if it's invalid. Masks can be passed as net.IP(mask).
+// e.g., Hex(192.0.2.1) -> c0.00.02.01, Hex(2001:db8::1) -> 2001:0db8:0000:0000:0000:0000:0000:0001.
+func Hex(ip net.IP) string {
+	ip = canonical(ip)
+	switch len(ip) {
+	case net.IPv4len:
+		parts := make([]string, len(ip))
+		for i, v := range ip {
+			parts[i] = strconv.FormatUint(uint64(v)|0x100, 16)[1:]
+		}
+		return strings.Join(parts, ".")
+	case net.IPv6len:
+		return Format(ip, NoCompression|ZeroPad)
+	}
+	return ""
+}
+
+// Decimal returns the unsigned decimal form of an IP address or mask, or "" if it's invalid.
+// Masks can be passed as net.IP(mask).
+// e.g., Decimal(192.0.2.1) -> 3221225985.
+func Decimal(ip net.IP) string {
+	ip = canonical(ip)
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return ""
+	}
+	return IPToBig(ip).String()
+}
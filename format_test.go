@@ -0,0 +1,107 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		ip       net.IP
+		compress string
+		expand   string
+		upper    string
+	}{
+		{net.ParseIP("2001:db8::1"), "2001:db8::1", "2001:0db8:0000:0000:0000:0000:0000:0001", "2001:DB8::1"},
+		{net.ParseIP("2001:db8:0:0:1:0:0:1"), "2001:db8::1:0:0:1", "2001:0db8:0000:0000:0001:0000:0000:0001", "2001:DB8::1:0:0:1"},
+		{net.ParseIP("2001:db8:0:1:1:1:1:1"), "2001:db8:0:1:1:1:1:1", "2001:0db8:0000:0001:0001:0001:0001:0001", "2001:DB8:0:1:1:1:1:1"},
+		{net.ParseIP("2001:0:0:1:0:0:0:1"), "2001:0:0:1::1", "2001:0000:0000:0001:0000:0000:0000:0001", "2001:0:0:1::1"},
+		{net.ParseIP("fe80::abcd"), "fe80::abcd", "fe80:0000:0000:0000:0000:0000:0000:abcd", "FE80::ABCD"},
+		{net.ParseIP("::"), "::", "0000:0000:0000:0000:0000:0000:0000:0000", "::"},
+		{net.ParseIP("::1"), "::1", "0000:0000:0000:0000:0000:0000:0000:0001", "::1"},
+		{net.ParseIP("1::"), "1::", "0001:0000:0000:0000:0000:0000:0000:0000", "1::"},
+		{net.ParseIP("192.0.2.1"), "192.0.2.1", "192.0.2.1", "192.0.2.1"},
+		{net.IP{192, 0, 2, 1}, "192.0.2.1", "192.0.2.1", "192.0.2.1"},
+		{nil, "", "", ""},
+		{net.IP{1, 2, 3}, "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Compress(tt.ip); got != tt.compress {
+			t.Errorf("Compress(%v) = %v, want %v", tt.ip, got, tt.compress)
+		}
+		if tt.ip != nil && tt.compress != "" && tt.compress != tt.ip.String() {
+			t.Errorf("Compress(%v) = %v, net.IP.String() = %v", tt.ip, tt.compress, tt.ip.String())
+		}
+		if got := Expand(tt.ip); got != tt.expand {
+			t.Errorf("Expand(%v) = %v, want %v", tt.ip, got, tt.expand)
+		}
+		if got := Format(tt.ip, Uppercase); got != tt.upper {
+			t.Errorf("Format(%v, Uppercase) = %v, want %v", tt.ip, got, tt.upper)
+		}
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	ip := net.ParseIP("2001:db8::a")
+	tests := []struct {
+		opts FormatOption
+		want string
+	}{
+		{NoCompression, "2001:db8:0:0:0:0:0:a"},
+		{ZeroPad, "2001:0db8::000a"},
+		{Uppercase | NoCompression, "2001:DB8:0:0:0:0:0:A"},
+		{Uppercase | NoCompression | ZeroPad, "2001:0DB8:0000:0000:0000:0000:0000:000A"},
+	}
+	for _, tt := range tests {
+		if got := Format(ip, tt.opts); got != tt.want {
+			t.Errorf("Format(%v, %v) = %v, want %v", ip, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	tests := []struct {
+		ip      net.IP
+		binary  string
+		hex     string
+		decimal string
+	}{
+		{net.ParseIP("192.0.2.1"), "11000000.00000000.00000010.00000001", "c0.00.02.01", "3221225985"},
+		{net.IP(net.CIDRMask(26, 32)), "11111111.11111111.11111111.11000000", "ff.ff.ff.c0", "4294967232"},
+		{net.ParseIP("2001:db8::1"),
+			"0010000000000001:0000110110111000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000000000001",
+			"2001:0db8:0000:0000:0000:0000:0000:0001", "42540766411282592856903984951653826561"},
+		{nil, "", "", ""},
+		{net.IP{1, 2, 3}, "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := Binary(tt.ip); got != tt.binary {
+			t.Errorf("Binary(%v) = %v, want %v", tt.ip, got, tt.binary)
+		}
+		if got := Hex(tt.ip); got != tt.hex {
+			t.Errorf("Hex(%v) = %v, want %v", tt.ip, got, tt.hex)
+		}
+		if got := Decimal(tt.ip); got != tt.decimal {
+			t.Errorf("Decimal(%v) = %v, want %v", tt.ip, got, tt.decimal)
+		}
+	}
+}
+
+func TestBinaryWithPrefix(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		ones int
+		want string
+	}{
+		{net.ParseIP("192.0.2.1"), 26, "11000000.00000000.00000010.00 000001"},
+		{net.ParseIP("192.0.2.1"), 24, "11000000.00000000.00000010. 00000001"},
+		{net.ParseIP("192.0.2.1"), 0, " 11000000.00000000.00000010.00000001"},
+		{net.ParseIP("192.0.2.1"), 32, "11000000.00000000.00000010.00000001"},
+		{net.ParseIP("::1"), 124, "0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:0000000000000000:000000000000 0001"},
+	}
+	for _, tt := range tests {
+		if got := BinaryWithPrefix(tt.ip, tt.ones); got != tt.want {
+			t.Errorf("BinaryWithPrefix(%v, %v) = %v, want %v", tt.ip, tt.ones, got, tt.want)
+		}
+	}
+}
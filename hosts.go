@@ -0,0 +1,87 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+)
+
+// AddressCount returns the number of addresses in a network, or 0 if it's invalid.
+// e.g., AddressCount(2001:db8::/64) -> 18446744073709551616.
+func AddressCount(n net.IPNet) *big.Int {
+	if _, ones, size, err := splitBase(n); err == nil {
+		return new(big.Int).Lsh(big.NewInt(1), uint(size-ones))
+	}
+	return new(big.Int)
+}
+
+// UsableHosts returns the number of usable host addresses in a network, or 0 if it's invalid.
+// See NthHost for which addresses are usable.
+// e.g., UsableHosts(192.0.2.0/24) -> 254, UsableHosts(192.0.2.0/31) -> 2.
+func UsableHosts(n net.IPNet) *big.Int {
+	if _, ones, size, err := splitBase(n); err == nil {
+		return hostPolicy(ones, size).UsableHosts(n)
+	}
+	return new(big.Int)
+}
+
+// FirstHost returns the first usable host address in a network, or nil if it's invalid.
+// See NthHost for which addresses are usable, use Broadcast for the literal last address.
+// e.g., FirstHost(192.0.2.0/24) -> 192.0.2.1, FirstHost(192.0.2.0/31) -> 192.0.2.0.
+func FirstHost(n net.IPNet) net.IP {
+	return NthHost(n, new(big.Int))
+}
+
+// LastHost returns the last usable host address in a network, or nil if it's invalid.
+// e.g., LastHost(192.0.2.0/24) -> 192.0.2.254, LastHost(192.0.2.0/31) -> 192.0.2.1.
+func LastHost(n net.IPNet) net.IP {
+	i := UsableHosts(n)
+	return NthHost(n, i.Sub(i, big.NewInt(1)))
+}
+
+// NthHost returns the usable host address at the given zero-based index in a network, or nil if there is none.
+// IPv4 networks exclude the network and broadcast addresses, except for /31 (RFC 3021) and /32,
+// IPv6 networks have no reserved addresses.
+// e.g., NthHost(10.1.2.0/24, 4) -> 10.1.2.5.
+func NthHost(n net.IPNet, i *big.Int) net.IP {
+	_, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil
+	}
+	return hostPolicy(ones, size).NthHost(n, i)
+}
+
+// HostIndex returns the zero-based index of a usable host address in a network, the inverse of NthHost.
+// It returns nil if ip isn't a usable host address in the network.
+// e.g., HostIndex(10.1.2.0/24, 10.1.2.5) -> 4.
+func HostIndex(n net.IPNet, ip net.IP) *big.Int {
+	_, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil
+	}
+	return hostPolicy(ones, size).HostIndex(n, ip)
+}
+
+// IsNetworkAddress returns whether ip is the network address of n, i.e., its first address,
+// except for IPv4 /31 and /32 and IPv6 /127 and /128 networks, where every address can be assigned to hosts.
+// For other IPv6 networks this is the Subnet-Router anycast address (RFC 4291).
+// e.g., IsNetworkAddress(192.0.2.0, 192.0.2.0/24) -> true, IsNetworkAddress(192.0.2.0, 192.0.2.0/31) -> false.
+func IsNetworkAddress(ip net.IP, n net.IPNet) bool {
+	first, ones, size, err := splitBase(n)
+	return err == nil && ones < size-1 && first.Equal(ip)
+}
+
+// IsBroadcast returns whether ip is the broadcast address of an IPv4 network n, i.e., its last address,
+// except for /31 and /32 networks, which have no broadcast address. IPv6 has no broadcast addresses.
+// e.g., IsBroadcast(192.0.2.255, 192.0.2.0/24) -> true, IsBroadcast(192.0.2.1, 192.0.2.0/31) -> false.
+func IsBroadcast(ip net.IP, n net.IPNet) bool {
+	_, ones, size, err := splitBase(n)
+	return err == nil && size == 8*net.IPv4len && ones < size-1 && Broadcast(n).Equal(ip)
+}
+
+// hostPolicy returns the addresses reserved in a network with the given prefix length and size in bits.
+func hostPolicy(ones, size int) ReservePolicy {
+	if size == 8*net.IPv4len && ones < 31 {
+		return ReserveNetworkBroadcast
+	}
+	return ReserveNone
+}
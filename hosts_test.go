@@ -0,0 +1,169 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+	"testing"
+)
+
+func TestNthHost(t *testing.T) {
+	tests := []struct {
+		n    string
+		i    int64
+		want string
+	}{
+		{"10.1.2.0/24", 0, "10.1.2.1"},
+		{"10.1.2.0/24", 4, "10.1.2.5"},
+		{"10.1.2.0/24", 253, "10.1.2.254"},
+		{"10.1.2.0/24", 254, ""},
+		{"10.1.2.0/24", -1, ""},
+		{"10.1.2.0/30", 1, "10.1.2.2"},
+		{"10.1.2.0/30", 2, ""},
+		{"10.1.2.0/31", 0, "10.1.2.0"},
+		{"10.1.2.0/31", 1, "10.1.2.1"},
+		{"10.1.2.0/31", 2, ""},
+		{"10.1.2.3/32", 0, "10.1.2.3"},
+		{"10.1.2.3/32", 1, ""},
+		{"::ffff:10.1.2.0/120", 4, "10.1.2.5"},
+		{"::ffff:10.1.2.0/120", 254, ""},
+		{"2001:db8::/64", 0, "2001:db8::"},
+		{"2001:db8::/64", 1 << 40, "2001:db8::100:0:0"},
+		{"2001:db8::/127", 1, "2001:db8::1"},
+		{"2001:db8::/127", 2, ""},
+		{"2001:db8::1/128", 0, "2001:db8::1"},
+		{"2001:db8::1/128", 1, ""},
+	}
+	for _, tt := range tests {
+		n := mustCIDR(t, tt.n)
+		got := NthHost(n, big.NewInt(tt.i))
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("NthHost(%v, %v) = %v, want nil", tt.n, tt.i, got)
+			}
+			continue
+		}
+		if !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("NthHost(%v, %v) = %v, want %v", tt.n, tt.i, got, tt.want)
+			continue
+		}
+		if i := HostIndex(n, got); i == nil || i.Int64() != tt.i {
+			t.Errorf("HostIndex(%v, %v) = %v, want %v", tt.n, got, i, tt.i)
+		}
+	}
+}
+
+func TestHostIndexInvalid(t *testing.T) {
+	tests := []struct {
+		n, ip string
+	}{
+		{"10.1.2.0/24", "10.1.2.0"},
+		{"10.1.2.0/24", "10.1.2.255"},
+		{"10.1.2.0/24", "10.1.3.1"},
+		{"10.1.2.0/24", "2001:db8::1"},
+		{"2001:db8::/64", "2001:db8:0:1::"},
+	}
+	for _, tt := range tests {
+		if got := HostIndex(mustCIDR(t, tt.n), net.ParseIP(tt.ip)); got != nil {
+			t.Errorf("HostIndex(%v, %v) = %v, want nil", tt.n, tt.ip, got)
+		}
+	}
+}
+
+func TestAddressCount(t *testing.T) {
+	tests := []struct {
+		n            string
+		count, hosts string
+	}{
+		{"192.0.2.0/24", "256", "254"},
+		{"192.0.2.0/30", "4", "2"},
+		{"192.0.2.0/31", "2", "2"},
+		{"192.0.2.1/32", "1", "1"},
+		{"::ffff:192.0.2.0/120", "256", "254"},
+		{"0.0.0.0/0", "4294967296", "4294967294"},
+		{"2001:db8::/64", "18446744073709551616", "18446744073709551616"},
+		{"2001:db8::/127", "2", "2"},
+		{"2001:db8::1/128", "1", "1"},
+		{"::/0", "340282366920938463463374607431768211456", "340282366920938463463374607431768211456"},
+	}
+	for _, tt := range tests {
+		n := mustCIDR(t, tt.n)
+		if got := AddressCount(n); got.String() != tt.count {
+			t.Errorf("AddressCount(%v) = %v, want %v", tt.n, got, tt.count)
+		}
+		if got := UsableHosts(n); got.String() != tt.hosts {
+			t.Errorf("UsableHosts(%v) = %v, want %v", tt.n, got, tt.hosts)
+		}
+	}
+	bad := net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}
+	if got := AddressCount(bad); got.Sign() != 0 {
+		t.Errorf("AddressCount(%v) = %v, want 0", &bad, got)
+	}
+	if got := UsableHosts(bad); got.Sign() != 0 {
+		t.Errorf("UsableHosts(%v) = %v, want 0", &bad, got)
+	}
+}
+
+func TestFirstLastHost(t *testing.T) {
+	tests := []struct {
+		n           string
+		first, last string
+	}{
+		{"192.0.2.0/24", "192.0.2.1", "192.0.2.254"},
+		{"192.0.2.77/24", "192.0.2.1", "192.0.2.254"},
+		{"192.0.2.0/30", "192.0.2.1", "192.0.2.2"},
+		{"192.0.2.0/31", "192.0.2.0", "192.0.2.1"},
+		{"192.0.2.1/32", "192.0.2.1", "192.0.2.1"},
+		{"::ffff:192.0.2.0/120", "192.0.2.1", "192.0.2.254"},
+		{"2001:db8::/64", "2001:db8::", "2001:db8::ffff:ffff:ffff:ffff"},
+		{"2001:db8::/127", "2001:db8::", "2001:db8::1"},
+		{"2001:db8::1/128", "2001:db8::1", "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		n := mustCIDR(t, tt.n)
+		if got := FirstHost(n); !got.Equal(net.ParseIP(tt.first)) {
+			t.Errorf("FirstHost(%v) = %v, want %v", tt.n, got, tt.first)
+		}
+		if got := LastHost(n); !got.Equal(net.ParseIP(tt.last)) {
+			t.Errorf("LastHost(%v) = %v, want %v", tt.n, got, tt.last)
+		}
+	}
+	bad := net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}
+	if got := FirstHost(bad); got != nil {
+		t.Errorf("FirstHost(%v) = %v, want nil", &bad, got)
+	}
+	if got := LastHost(bad); got != nil {
+		t.Errorf("LastHost(%v) = %v, want nil", &bad, got)
+	}
+}
+
+func TestIsNetworkAddressBroadcast(t *testing.T) {
+	tests := []struct {
+		ip, n          string
+		network, bcast bool
+	}{
+		{"192.0.2.0", "192.0.2.0/24", true, false},
+		{"192.0.2.255", "192.0.2.0/24", false, true},
+		{"192.0.2.1", "192.0.2.0/24", false, false},
+		{"::ffff:192.0.2.0", "192.0.2.0/24", true, false},
+		{"192.0.3.0", "192.0.2.0/24", false, false},
+		{"192.0.2.0", "192.0.2.0/30", true, false},
+		{"192.0.2.3", "192.0.2.0/30", false, true},
+		{"192.0.2.0", "192.0.2.0/31", false, false},
+		{"192.0.2.1", "192.0.2.0/31", false, false},
+		{"192.0.2.1", "192.0.2.1/32", false, false},
+		{"2001:db8::", "2001:db8::/64", true, false},
+		{"2001:db8::ffff:ffff:ffff:ffff", "2001:db8::/64", false, false},
+		{"2001:db8::", "2001:db8::/127", false, false},
+		{"2001:db8::", "2001:db8::/128", false, false},
+		{"2001:db8::", "192.0.2.0/24", false, false},
+	}
+	for _, tt := range tests {
+		ip, n := net.ParseIP(tt.ip), mustCIDR(t, tt.n)
+		if got := IsNetworkAddress(ip, n); got != tt.network {
+			t.Errorf("IsNetworkAddress(%v, %v) = %v, want %v", tt.ip, tt.n, got, tt.network)
+		}
+		if got := IsBroadcast(ip, n); got != tt.bcast {
+			t.Errorf("IsBroadcast(%v, %v) = %v, want %v", tt.ip, tt.n, got, tt.bcast)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package httpipcalc provides net/http middleware allowing or denying requests by client IP address.
+//
+// Client addresses are tested against ipcalc.Matcher values, e.g., ipcalc.Nets or wildcard.Wildcard:
+//
+//	f := httpipcalc.Filter{Allow: ipcalc.Nets{*office}, Deny: blocked}
+//	http.Handle("/admin", f.Handler(admin))
+package httpipcalc
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// Filter allows or denies requests based on the client IP address.
+// The zero value allows every request with a parseable client address.
+type Filter struct {
+	// Allow, if set, restricts requests to matching client addresses.
+	Allow ipcalc.Matcher
+	// Deny, if set, rejects requests from matching client addresses, it takes precedence over Allow.
+	Deny ipcalc.Matcher
+	// DenyStatus is the HTTP status code for denied requests, http.StatusForbidden if zero.
+	DenyStatus int
+	// ClientIP returns the client address of a request, RemoteIP if nil.
+	ClientIP func(r *http.Request) (net.IP, error)
+	// OnDeny, if set, is called for every denied request, ip is nil if the client address couldn't be determined.
+	OnDeny func(r *http.Request, ip net.IP)
+}
+
+// Allowed returns whether requests from an IP address are allowed.
+func (f Filter) Allowed(ip net.IP) bool {
+	if ip == nil || f.Deny != nil && f.Deny.Matches(ip) {
+		return false
+	}
+	return f.Allow == nil || f.Allow.Matches(ip)
+}
+
+// Handler returns a handler passing allowed requests on to next, denied requests get DenyStatus.
+func (f Filter) Handler(next http.Handler) http.Handler {
+	clientIP := f.ClientIP
+	if clientIP == nil {
+		clientIP = RemoteIP
+	}
+	status := f.DenyStatus
+	if status == 0 {
+		status = http.StatusForbidden
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, err := clientIP(r)
+		if err != nil {
+			ip = nil
+		}
+		if !f.Allowed(ip) {
+			if f.OnDeny != nil {
+				f.OnDeny(r, ip)
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RemoteIP returns the IP address of the peer which sent a request, ignoring any forwarding headers.
+func RemoteIP(r *http.Request) (net.IP, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: r.RemoteAddr}
+	}
+	return ip, nil
+}
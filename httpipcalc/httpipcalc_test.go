@@ -0,0 +1,99 @@
+package httpipcalc
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/wildcard"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func TestRemoteIP(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.1:1234":     "192.0.2.1",
+		"[2001:db8::1]:1234": "2001:db8::1",
+		"192.0.2.1":          "192.0.2.1",
+		"invalid:1234":       "",
+		"":                   "",
+	}
+	for addr, want := range tests {
+		got, err := RemoteIP(&http.Request{RemoteAddr: addr})
+		if want == "" {
+			if err == nil {
+				t.Errorf("RemoteIP(%v) error = nil", addr)
+			}
+		} else if err != nil || !got.Equal(net.ParseIP(want)) {
+			t.Errorf("RemoteIP(%v) = %v, %v, want %v", addr, got, err, want)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	w := wildcard.New(net.ParseIP("192.0.2.1"), ipcalc.ParseMask("0.0.0.254"))
+	tests := []struct {
+		f    Filter
+		ip   string
+		want bool
+	}{
+		{Filter{}, "192.0.2.1", true},
+		{Filter{}, "invalid", false},
+		{Filter{Allow: ipcalc.Nets{mustCIDR(t, "192.0.2.0/24")}}, "192.0.2.1", true},
+		{Filter{Allow: ipcalc.Nets{mustCIDR(t, "192.0.2.0/24")}}, "192.0.3.1", false},
+		{Filter{Deny: w}, "192.0.2.3", false},
+		{Filter{Deny: w}, "192.0.2.4", true},
+		{Filter{Allow: ipcalc.Nets{mustCIDR(t, "192.0.2.0/24")}, Deny: w}, "192.0.2.5", false},
+		{Filter{Allow: ipcalc.Nets{mustCIDR(t, "192.0.2.0/24")}, Deny: w}, "192.0.2.6", true},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Allowed(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("%+v.Allowed(%v) = %v, want %v", tt.f, tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	var denied []net.IP
+	f := Filter{
+		Allow: ipcalc.Nets{mustCIDR(t, "2001:db8::/32")},
+		OnDeny: func(r *http.Request, ip net.IP) {
+			denied = append(denied, ip)
+		},
+	}
+	tests := []struct {
+		f          Filter
+		remoteAddr string
+		want       int
+	}{
+		{f, "[2001:db8::1]:1234", http.StatusTeapot},
+		{f, "192.0.2.1:1234", http.StatusForbidden},
+		{f, "invalid", http.StatusForbidden},
+		{Filter{Deny: ipcalc.Nets{mustCIDR(t, "192.0.2.0/24")}, DenyStatus: http.StatusNotFound}, "192.0.2.1:1234", http.StatusNotFound},
+		{Filter{ClientIP: func(*http.Request) (net.IP, error) { return net.ParseIP("2001:db8::1"), nil }, Allow: f.Allow}, "192.0.2.1:1234", http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		w := httptest.NewRecorder()
+		tt.f.Handler(ok).ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("Handler(%v) = %v, want %v", tt.remoteAddr, w.Code, tt.want)
+		}
+	}
+	if len(denied) != 2 || !denied[0].Equal(net.ParseIP("192.0.2.1")) || denied[1] != nil {
+		t.Errorf("OnDeny() calls = %v, want [192.0.2.1 <nil>]", denied)
+	}
+}
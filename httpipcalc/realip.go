@@ -0,0 +1,90 @@
+package httpipcalc
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// ForwardingHeader is the header trusted proxies record the forwarding chain in.
+type ForwardingHeader int
+
+// Forwarding headers, only the one set by the trusted proxies must be used,
+// any other header is under the client's control.
+const (
+	// XForwardedFor is the de facto standard X-Forwarded-For header.
+	XForwardedFor ForwardingHeader = iota
+	// Forwarded is the RFC 7239 Forwarded header, only its for= parameters are used.
+	Forwarded
+)
+
+// RealClientIP returns the address of the client which originated a request, as seen by the first trusted proxy.
+//
+// The forwarding chain is the given header, which must be the one set by the trusted proxies,
+// followed by the peer which sent the request.
+// The other forwarding header is ignored, it's never chosen by presence since clients can forge it.
+// The chain is walked right-to-left, skipping trusted proxies, and the first untrusted address is returned.
+// Addresses left of it were supplied by the client and can't be trusted.
+// If every address is trusted then the leftmost one is returned.
+//
+// A nil trustedProxies trusts nothing, i.e., the peer address is returned.
+// It returns an error if any address it walks over can't be parsed, e.g., obfuscated Forwarded identifiers.
+func RealClientIP(r *http.Request, header ForwardingHeader, trustedProxies ipcalc.Matcher) (net.IP, error) {
+	ip, err := RemoteIP(r)
+	if err != nil || trustedProxies == nil {
+		return ip, err
+	}
+	var chain []string
+	switch header {
+	case XForwardedFor:
+		chain = splitList(r.Header.Values("X-Forwarded-For"))
+	case Forwarded:
+		chain = forwardedFor(r.Header.Values("Forwarded"))
+	default:
+		return nil, fmt.Errorf("httpipcalc: invalid forwarding header %v", int(header))
+	}
+	for i := len(chain) - 1; i >= 0 && trustedProxies.Matches(ip); i-- {
+		if ip = parseNode(chain[i]); ip == nil {
+			return nil, &net.ParseError{Type: "forwarded IP address", Text: chain[i]}
+		}
+	}
+	return ip, nil
+}
+
+// forwardedFor returns the forwarding chain from Forwarded header values, leftmost first.
+func forwardedFor(values []string) []string {
+	var chain []string
+	for _, elem := range splitList(values) {
+		node := ""
+		for _, pair := range strings.Split(elem, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			if strings.EqualFold(k, "for") {
+				node = strings.Trim(v, `"`)
+			}
+		}
+		chain = append(chain, node)
+	}
+	return chain
+}
+
+// splitList splits comma-separated header values, in order.
+func splitList(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, elem := range strings.Split(v, ",") {
+			list = append(list, strings.TrimSpace(elem))
+		}
+	}
+	return list
+}
+
+// parseNode parses an address which may include a port, e.g., 192.0.2.1:80 or [2001:db8::1]:80.
+func parseNode(node string) net.IP {
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		node = host
+	}
+	return net.ParseIP(strings.Trim(node, "[]"))
+}
@@ -0,0 +1,60 @@
+package httpipcalc
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func TestRealClientIP(t *testing.T) {
+	trusted := ipcalc.Nets{mustCIDR(t, "10.0.0.0/8"), mustCIDR(t, "2001:db8:ffff::/48")}
+	tests := []struct {
+		remote string
+		fwd    ForwardingHeader
+		header http.Header
+		want   string
+	}{
+		{"192.0.2.1:1234", XForwardedFor, nil, "192.0.2.1"},
+		{"192.0.2.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "192.0.2.1"},
+		{"10.0.0.1:1234", XForwardedFor, nil, "10.0.0.1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "198.51.100.1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"203.0.113.66, 198.51.100.1, 10.0.0.2"}}, "198.51.100.1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"203.0.113.66", "198.51.100.1", "10.0.0.2"}}, "198.51.100.1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}}, "10.0.0.3"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"garbage, 198.51.100.1"}}, "198.51.100.1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"198.51.100.1, garbage"}}, ""},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"[2001:db8::1]:443"}}, "2001:db8::1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {"198.51.100.1:8080"}}, "198.51.100.1"},
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"X-Forwarded-For": {""}}, ""},
+		{"10.0.0.1:1234", Forwarded, http.Header{"Forwarded": {`for=198.51.100.1;proto=https, for="[2001:db8:ffff::1]:443"`}}, "198.51.100.1"},
+		{"10.0.0.1:1234", Forwarded, http.Header{"Forwarded": {"For=198.51.100.1"}, "X-Forwarded-For": {"203.0.113.1"}}, "198.51.100.1"},
+		{"10.0.0.1:1234", Forwarded, http.Header{"Forwarded": {"for=_hidden"}}, ""},
+		{"10.0.0.1:1234", Forwarded, http.Header{"Forwarded": {"proto=https"}}, ""},
+		{"invalid", XForwardedFor, nil, ""},
+		// A client-supplied header the proxies don't set is ignored, even when present.
+		{"10.0.0.1:1234", XForwardedFor, http.Header{"Forwarded": {"for=198.51.100.66"}, "X-Forwarded-For": {"203.0.113.7"}}, "203.0.113.7"},
+		{"10.0.0.1:1234", Forwarded, http.Header{"Forwarded": {"for=198.51.100.66, for=203.0.113.7"}, "X-Forwarded-For": {"192.0.2.66"}}, "203.0.113.7"},
+		{"10.0.0.1:1234", Forwarded, http.Header{"X-Forwarded-For": {"192.0.2.66"}}, "10.0.0.1"},
+		{"10.0.0.1:1234", ForwardingHeader(7), nil, ""},
+	}
+	for _, tt := range tests {
+		r := &http.Request{RemoteAddr: tt.remote, Header: tt.header}
+		got, err := RealClientIP(r, tt.fwd, trusted)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("RealClientIP(%v, %v) = %v, want error", tt.remote, tt.header, got)
+			}
+		} else if err != nil || !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("RealClientIP(%v, %v) = %v, %v, want %v", tt.remote, tt.header, got, err, tt.want)
+		}
+	}
+}
+
+func TestRealClientIPNilTrusted(t *testing.T) {
+	r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{"X-Forwarded-For": {"198.51.100.1"}}}
+	if got, err := RealClientIP(r, XForwardedFor, nil); err != nil || !got.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("RealClientIP(nil trusted) = %v, %v, want 10.0.0.1", got, err)
+	}
+}
@@ -0,0 +1,149 @@
+// Package interval provides an interval tree over arbitrary IP address ranges, which needn't be CIDR-aligned,
+// e.g., for geo-feeds and RIR delegation data.
+//
+// The tree is an implicit balanced tree over the ranges sorted by first address, each node keeping the highest
+// last address of its subtree, it's rebuilt by the first query after an insertion.
+package interval
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// Entry is a range and its associated value.
+type Entry[V any] struct {
+	Range ipcalc.Range
+	Value V
+}
+
+type item[V any] struct {
+	lo, hi uint128.Uint128
+	entry  Entry[V]
+}
+
+// family holds the ranges of a single address family.
+type family[V any] struct {
+	items []item[V]
+	// maxHi[i] is the highest last address in the subtree rooted at items[i].
+	maxHi []uint128.Uint128
+	dirty bool
+}
+
+// Tree is an interval tree mapping IP address ranges to values, ranges may overlap or be duplicated.
+// The zero value is an empty Tree ready to use.
+// A Tree is not safe for concurrent use, not even for queries after an insertion.
+type Tree[V any] struct {
+	v4, v6 family[V]
+}
+
+// Insert adds a range and its value to the Tree.
+func (t *Tree[V]) Insert(r ipcalc.Range, v V) error {
+	size, lo, hi, err := bounds(r)
+	if err != nil {
+		return err
+	}
+	f := t.family(size)
+	f.items = append(f.items, item[V]{lo, hi, Entry[V]{ipcalc.Range{First: lo.IP(size), Last: hi.IP(size)}, v}})
+	f.dirty = true
+	return nil
+}
+
+// Len returns the number of ranges in the Tree.
+func (t *Tree[V]) Len() int {
+	return len(t.v4.items) + len(t.v6.items)
+}
+
+// Stab returns every range containing an IP address, sorted by first address.
+func (t *Tree[V]) Stab(ip net.IP) []Entry[V] {
+	return t.Overlapping(ipcalc.Range{First: ip, Last: ip})
+}
+
+// Overlapping returns every range sharing at least one address with r, sorted by first address.
+func (t *Tree[V]) Overlapping(r ipcalc.Range) []Entry[V] {
+	size, lo, hi, err := bounds(r)
+	if err != nil {
+		return nil
+	}
+	f := t.family(size)
+	f.build()
+	var out []Entry[V]
+	f.query(lo, hi, 0, len(f.items), &out)
+	return out
+}
+
+func (t *Tree[V]) family(size int) *family[V] {
+	if size == net.IPv4len {
+		return &t.v4
+	}
+	return &t.v6
+}
+
+// build sorts the ranges and computes maxHi if there were insertions.
+func (f *family[V]) build() {
+	if !f.dirty {
+		return
+	}
+	sort.SliceStable(f.items, func(i, j int) bool { return f.items[i].lo.Cmp(f.items[j].lo) < 0 })
+	f.maxHi = make([]uint128.Uint128, len(f.items))
+	f.buildMax(0, len(f.items))
+	f.dirty = false
+}
+
+// buildMax computes maxHi for the subtree of items[begin:end], returning its highest last address.
+func (f *family[V]) buildMax(begin, end int) uint128.Uint128 {
+	mid := (begin + end) / 2
+	m := f.items[mid].hi
+	for _, c := range []uint128.Uint128{
+		f.subtreeMax(begin, mid),
+		f.subtreeMax(mid+1, end),
+	} {
+		if c.Cmp(m) > 0 {
+			m = c
+		}
+	}
+	f.maxHi[mid] = m
+	return m
+}
+
+func (f *family[V]) subtreeMax(begin, end int) uint128.Uint128 {
+	if begin >= end {
+		return uint128.Zero
+	}
+	return f.buildMax(begin, end)
+}
+
+// query appends the entries of items[begin:end] overlapping [lo, hi] in order.
+func (f *family[V]) query(lo, hi uint128.Uint128, begin, end int, out *[]Entry[V]) {
+	if begin >= end {
+		return
+	}
+	mid := (begin + end) / 2
+	if f.maxHi[mid].Cmp(lo) < 0 {
+		return
+	}
+	f.query(lo, hi, begin, mid, out)
+	x := f.items[mid]
+	if x.lo.Cmp(hi) > 0 {
+		// Every range to the right starts after hi too.
+		return
+	}
+	if x.hi.Cmp(lo) >= 0 {
+		*out = append(*out, x.entry)
+	}
+	f.query(lo, hi, mid+1, end, out)
+}
+
+// bounds returns the address size and the first and last values of a Range.
+func bounds(r ipcalc.Range) (int, uint128.Uint128, uint128.Uint128, error) {
+	first, last := ipcalc.IP(r.First), ipcalc.IP(r.Last)
+	lo, ok1 := uint128.FromIP(first)
+	hi, ok2 := uint128.FromIP(last)
+	if !ok1 || !ok2 || len(first) != len(last) || lo.Cmp(hi) > 0 {
+		return 0, lo, hi, fmt.Errorf("interval: invalid range %v", r)
+	}
+	return len(first), lo, hi, nil
+}
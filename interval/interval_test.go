@@ -0,0 +1,108 @@
+package interval
+
+import (
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func mustRange(t testing.TB, s string) ipcalc.Range {
+	t.Helper()
+	r, err := ipcalc.ParseRange(s)
+	if err != nil {
+		t.Fatalf("ParseRange(%v) error = %v", s, err)
+	}
+	return r
+}
+
+func values(entries []Entry[string]) []string {
+	var s []string
+	for _, e := range entries {
+		s = append(s, e.Value)
+	}
+	return s
+}
+
+func TestTree(t *testing.T) {
+	var tr Tree[string]
+	for _, s := range []string{
+		"192.0.2.0-192.0.2.255",
+		"192.0.2.10-192.0.2.20",
+		"192.0.2.15-192.0.3.5",
+		"10.0.0.0-10.255.255.255",
+		"192.0.2.10-192.0.2.20",
+		"2001:db8::-2001:db8::ffff",
+		"::ffff:198.51.100.7-::ffff:198.51.100.9",
+	} {
+		if err := tr.Insert(mustRange(t, s), s); err != nil {
+			t.Fatalf("Insert(%v) error = %v", s, err)
+		}
+	}
+	if tr.Len() != 7 {
+		t.Errorf("Len() = %v, want 7", tr.Len())
+	}
+	stabs := []struct {
+		ip   string
+		want []string
+	}{
+		{"192.0.2.15", []string{"192.0.2.0-192.0.2.255", "192.0.2.10-192.0.2.20", "192.0.2.10-192.0.2.20", "192.0.2.15-192.0.3.5"}},
+		{"192.0.2.5", []string{"192.0.2.0-192.0.2.255"}},
+		{"192.0.3.0", []string{"192.0.2.15-192.0.3.5"}},
+		{"198.51.100.8", []string{"::ffff:198.51.100.7-::ffff:198.51.100.9"}},
+		{"2001:db8::1", []string{"2001:db8::-2001:db8::ffff"}},
+		{"8.8.8.8", nil},
+		{"", nil},
+	}
+	for _, tt := range stabs {
+		if got := values(tr.Stab(net.ParseIP(tt.ip))); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Stab(%v) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+	overlaps := []struct {
+		r    string
+		want []string
+	}{
+		{"192.0.2.21-192.0.3.0", []string{"192.0.2.0-192.0.2.255", "192.0.2.15-192.0.3.5"}},
+		{"0.0.0.0-255.255.255.255", []string{"10.0.0.0-10.255.255.255", "192.0.2.0-192.0.2.255", "192.0.2.10-192.0.2.20", "192.0.2.10-192.0.2.20", "192.0.2.15-192.0.3.5", "::ffff:198.51.100.7-::ffff:198.51.100.9"}},
+		{"192.0.3.6-192.0.3.255", nil},
+	}
+	for _, tt := range overlaps {
+		if got := values(tr.Overlapping(mustRange(t, tt.r))); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Overlapping(%v) = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+	if err := tr.Insert(ipcalc.Range{First: net.ParseIP("192.0.2.2"), Last: net.ParseIP("192.0.2.1")}, "x"); err == nil {
+		t.Errorf("Insert(reversed range) error = nil, want error")
+	}
+}
+
+func TestTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var tr Tree[int]
+	var ranges [][2]uint32
+	for i := 0; i < 500; i++ {
+		lo := r.Uint32() >> 8
+		hi := lo + r.Uint32()>>(8+r.Intn(16))
+		ranges = append(ranges, [2]uint32{lo, hi})
+		tr.Insert(ipcalc.Range{First: ipcalc.Uint32ToIP(lo), Last: ipcalc.Uint32ToIP(hi)}, i)
+		if i%100 != 99 {
+			continue
+		}
+		// Query after every batch of insertions to exercise rebuilding.
+		for j := 0; j < 200; j++ {
+			ip := r.Uint32() >> 8
+			want := 0
+			for _, x := range ranges {
+				if x[0] <= ip && ip <= x[1] {
+					want++
+				}
+			}
+			if got := tr.Stab(ipcalc.Uint32ToIP(ip)); len(got) != want {
+				t.Fatalf("Stab(%v) = %v ranges, want %v", ipcalc.Uint32ToIP(ip), len(got), want)
+			}
+		}
+	}
+}
@@ -5,9 +5,12 @@
 package ipcalc
 
 import (
+	"encoding/binary"
 	"net"
 	"strconv"
 	"strings"
+
+	"github.com/hazaelsan/ipcalc/uint128"
 )
 
 // CopyIP returns a copy of a net.IP address.
@@ -23,6 +26,62 @@ func IP(ip net.IP) net.IP {
 	return CopyIP(ip)
 }
 
+// MappedPolicy controls how IPWithPolicy handles IPv4 and IPv4-mapped IPv6 addresses.
+type MappedPolicy int
+
+// IPv4-mapped IPv6 address policies.
+const (
+	// CollapseMapped returns IPv4-mapped IPv6 addresses as 4-byte IPv4 addresses, as IP does.
+	CollapseMapped MappedPolicy = iota
+	// PreserveMapped keeps the length of the address as given.
+	PreserveMapped
+	// ExpandIPv4 returns IPv4 addresses as 16-byte IPv4-mapped IPv6 addresses.
+	ExpandIPv4
+)
+
+// IPWithPolicy is like IP but lets the caller choose how IPv4 addresses are represented,
+// it returns nil if the address is invalid.
+// e.g., IPWithPolicy(::ffff:192.0.2.1, PreserveMapped) -> ::ffff:192.0.2.1.
+func IPWithPolicy(ip net.IP, p MappedPolicy) net.IP {
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil
+	}
+	switch p {
+	case PreserveMapped:
+		return CopyIP(ip)
+	case ExpandIPv4:
+		return CopyIP(ip.To16())
+	}
+	return IP(ip)
+}
+
+// To4In6 returns the 16-byte IPv4-mapped IPv6 form of an IPv4 address, or nil if it's not an IPv4 address.
+// e.g., To4In6(192.0.2.1) -> ::ffff:192.0.2.1.
+func To4In6(ip net.IP) net.IP {
+	if ip.To4() == nil {
+		return nil
+	}
+	return CopyIP(ip.To16())
+}
+
+// From4In6 returns the 4-byte IPv4 address of an IPv4-mapped IPv6 address, or nil if it's not one.
+// Unlike IP, 4-byte IPv4 addresses are also rejected.
+// e.g., From4In6(::ffff:192.0.2.1) -> 192.0.2.1.
+func From4In6(ip net.IP) net.IP {
+	if len(ip) != net.IPv6len || ip.To4() == nil {
+		return nil
+	}
+	return CopyIP(ip.To4())
+}
+
+// canonical is like IP but doesn't copy the address, it must not be modified.
+func canonical(ip net.IP) net.IP {
+	if x := ip.To4(); x != nil {
+		return x
+	}
+	return ip
+}
+
 // IPVersion returns the IP address version for the given net.IP.
 func IPVersion(ip net.IP) int {
 	if len(IP(ip)) == net.IPv4len {
@@ -77,6 +136,32 @@ func ParseIPMask(addr string) (net.IP, net.IPMask, error) {
 	return ip, mask, nil
 }
 
+// ParseIPMaskZone is like ParseIPMask but also accepts scoped IPv6 addresses, e.g., fe80::1%eth0/64,
+// the zone is returned in the net.IPAddr and is empty for unscoped addresses.
+func ParseIPMaskZone(addr string) (net.IPAddr, net.IPMask, error) {
+	i := strings.IndexByte(addr, '%')
+	if i < 0 {
+		ip, mask, err := ParseIPMask(addr)
+		return net.IPAddr{IP: ip}, mask, err
+	}
+	// The zone belongs to the address, i.e., addr%zone/len, a zone after the mask is rejected.
+	if strings.IndexByte(addr[:i], '/') >= 0 {
+		return net.IPAddr{}, nil, &net.ParseError{Type: "IP address", Text: addr}
+	}
+	zone, rest := addr[i+1:], ""
+	if j := strings.IndexByte(zone, '/'); j >= 0 {
+		zone, rest = zone[:j], zone[j:]
+	}
+	ip, mask, err := ParseIPMask(addr[:i] + rest)
+	if err != nil {
+		return net.IPAddr{}, nil, err
+	}
+	if zone == "" || IPVersion(ip) != 6 {
+		return net.IPAddr{}, nil, &net.ParseError{Type: "IP address", Text: addr}
+	}
+	return net.IPAddr{IP: ip, Zone: zone}, mask, nil
+}
+
 // Complement returns the complement of a given net.IPMask, commonly used as a Wildcard Mask.
 // e.g., Complement(255.255.254.0) -> 0.0.1.255.
 func Complement(mask net.IPMask) net.IPMask {
@@ -90,89 +175,103 @@ func Complement(mask net.IPMask) net.IPMask {
 	return w
 }
 
-// NextIP returns the next IP address.
+// NextIP returns the next IP address, or nil if it's invalid.
 // e.g., NextIP(192.168.0.0) -> 192.168.0.1.
 func NextIP(ip net.IP) net.IP {
-	ip = IP(ip)
-	for i := IPSize(ip) - 1; i >= 0; i-- {
-		ip[i]++
-		if ip[i] != 0x00 {
-			break
-		}
+	ip = canonical(ip)
+	u, ok := uint128.FromIP(ip)
+	if !ok {
+		return nil
 	}
-	return ip
+	return u.Add(uint128.One).IP(len(ip))
 }
 
-// PrevIP returns the previous IP address.
+// PrevIP returns the previous IP address, or nil if it's invalid.
 // e.g., PrevIP(192.168.0.1) -> 192.168.0.0.
 func PrevIP(ip net.IP) net.IP {
-	ip = IP(ip)
-	for i := IPSize(ip) - 1; i >= 0; i-- {
-		ip[i]--
-		if ip[i] != 0xff {
-			break
-		}
+	ip = canonical(ip)
+	u, ok := uint128.FromIP(ip)
+	if !ok {
+		return nil
 	}
-	return ip
+	return u.Sub(uint128.One).IP(len(ip))
 }
 
-// Add returns the sum of two net.IP addresses with the given mask.
+// NextIPOk is like NextIP but also reports whether the address wrapped around.
+// e.g., NextIPOk(255.255.255.255) -> 0.0.0.0, true.
+func NextIPOk(ip net.IP) (net.IP, bool) {
+	next := NextIP(ip)
+	return next, next.IsUnspecified()
+}
+
+// PrevIPOk is like PrevIP but also reports whether the address wrapped around.
+// e.g., PrevIPOk(0.0.0.0) -> 255.255.255.255, true.
+func PrevIPOk(ip net.IP) (net.IP, bool) {
+	prev := PrevIP(ip)
+	return prev, prev != nil && allFF(prev)
+}
+
+// Add returns the sum of two net.IP addresses with the given mask, or nil if a is invalid.
 // e.g., Add(192.168.0.1, 192.168.0.2, 0.0.0.255) -> 192.168.0.3.
 func Add(a, b net.IP, mask net.IPMask) net.IP {
-	a = IP(a)
-	b = IP(b)
-	for i := len(mask) - 1; i >= 0; i-- {
-		prev := a[i]
-		a[i] += b[i] & mask[i]
-		if a[i] < prev && i > 0 {
-			for j := i - 1; j >= 0; j-- {
-				a[j]++
-				if a[j] != 0x00 {
-					break
-				}
-			}
-		}
+	a = canonical(a)
+	x, ok := uint128.FromIP(a)
+	if !ok {
+		return nil
 	}
-	return a
+	y, _ := uint128.FromIP(maskIP(b, mask, len(a)))
+	return x.Add(y).IP(len(a))
 }
 
-// Substract returns the difference of two net.IP addresses with the given mask.
+// Substract returns the difference of two net.IP addresses with the given mask, or nil if a is invalid.
 // e.g., Substract(192.168.0.3, 192.168.0.1, 0.0.0.255) -> 192.168.0.2.
 func Substract(a, b net.IP, mask net.IPMask) net.IP {
-	a = IP(a)
-	b = IP(b)
-	for i := len(mask) - 1; i >= 0; i-- {
-		prev := a[i]
-		a[i] -= b[i] & mask[i]
-		if a[i] > prev && i > 0 {
-			for j := i - 1; j >= 0; j-- {
-				a[j]--
-				if a[j] != 0xff {
-					break
-				}
-			}
-		}
+	a = canonical(a)
+	x, ok := uint128.FromIP(a)
+	if !ok {
+		return nil
 	}
-	return a
+	y, _ := uint128.FromIP(maskIP(b, mask, len(a)))
+	return x.Sub(y).IP(len(a))
+}
+
+// maskIP returns b masked with mask as an IP address of the given size, missing bytes are zero.
+func maskIP(b net.IP, mask net.IPMask, size int) net.IP {
+	b = canonical(b)
+	ip := make(net.IP, size)
+	for i := 0; i < size && i < len(b) && i < len(mask); i++ {
+		ip[i] = b[i] & mask[i]
+	}
+	return ip
 }
 
 // And returns the bitwise AND of two net.IP addresses.
 // e.g., And(192.168.0.255, 192.168.255.128) -> 192.168.0.128.
 func And(a, b net.IP) net.IP {
 	a = IP(a)
-	b = IP(b)
-	for i := 0; i < IPSize(b); i++ {
+	b = canonical(b)
+	n := min(len(a), len(b))
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		binary.BigEndian.PutUint64(a[i:], binary.BigEndian.Uint64(a[i:])&binary.BigEndian.Uint64(b[i:]))
+	}
+	for ; i < n; i++ {
 		a[i] &= b[i]
 	}
 	return a
 }
 
 // Or returns the bitwise OR of two net.IP addresses.
-// e.g., Or(192.168.0.15, 192.168.10.128) -> 192.168.0.128.
+// e.g., Or(192.168.0.15, 192.168.10.128) -> 192.168.10.143.
 func Or(a, b net.IP) net.IP {
 	a = IP(a)
-	b = IP(b)
-	for i := 0; i < IPSize(b); i++ {
+	b = canonical(b)
+	n := min(len(a), len(b))
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		binary.BigEndian.PutUint64(a[i:], binary.BigEndian.Uint64(a[i:])|binary.BigEndian.Uint64(b[i:]))
+	}
+	for ; i < n; i++ {
 		a[i] |= b[i]
 	}
 	return a
@@ -182,13 +281,28 @@ func Or(a, b net.IP) net.IP {
 // e.g., Xor(192.0.2.1, 172.31.128.17) -> 108.31.130.16.
 func Xor(a, b net.IP) net.IP {
 	a = IP(a)
-	b = IP(b)
-	for i := 0; i < IPSize(b); i++ {
+	b = canonical(b)
+	n := min(len(a), len(b))
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		binary.BigEndian.PutUint64(a[i:], binary.BigEndian.Uint64(a[i:])^binary.BigEndian.Uint64(b[i:]))
+	}
+	for ; i < n; i++ {
 		a[i] ^= b[i]
 	}
 	return a
 }
 
+// Not returns the bitwise complement of a net.IP address.
+// e.g., Not(192.0.2.1) -> 63.255.253.254.
+func Not(ip net.IP) net.IP {
+	ip = IP(ip)
+	for i := range ip {
+		ip[i] = ^ip[i]
+	}
+	return ip
+}
+
 // Merge combines two net.IP addresses with the given mask.
 // For bit i, if mask[i] is set then b[i] is returned, otherwise a[i] is returned.
 // e.g., Merge(192.168.0.1, 172.16.32.100, 0.0.0.255) -> 192.168.0.100.
@@ -203,10 +317,15 @@ func Merge(a, b net.IP, mask net.IPMask) net.IP {
 }
 
 // Broadcast returns the broadcast IP address for the given IPNet.
+// The address and mask may be of different lengths as long as they can be normalized, see Normalize,
+// otherwise nil is returned.
 func Broadcast(n net.IPNet) net.IP {
-	ip := make(net.IP, IPSize(n.IP))
-	for i := 0; i < IPSize(n.IP); i++ {
-		ip[i] = n.IP[i] | ^n.Mask[i]
+	ip, mask, ok := normalizeNet(n)
+	if !ok {
+		return nil
+	}
+	for i := range ip {
+		ip[i] |= ^mask[i]
 	}
 	return ip
 }
@@ -234,3 +353,21 @@ func PrevSubnet(n net.IPNet) net.IPNet {
 func Contains(a, b net.IPNet) bool {
 	return a.Contains(b.IP) && a.Contains(Broadcast(b))
 }
+
+// Overlaps returns whether two networks share at least one address.
+// e.g., Overlaps(192.0.2.0/24, 192.0.2.128/25) -> true.
+func Overlaps(a, b net.IPNet) bool {
+	ra, rb := CIDRToRange(a), CIDRToRange(b)
+	return ra.First != nil && rb.First != nil && ra.Overlaps(rb)
+}
+
+// Adjacent returns whether the second network starts right after the first one ends.
+// e.g., Adjacent(192.0.2.0/24, 192.0.3.0/25) -> true.
+func Adjacent(a, b net.IPNet) bool {
+	ra, rb := CIDRToRange(a), CIDRToRange(b)
+	if ra.First == nil || rb.First == nil || !sameFamily(ra.Last, rb.First) {
+		return false
+	}
+	next, wrapped := NextIPOk(ra.Last)
+	return !wrapped && next.Equal(rb.First)
+}
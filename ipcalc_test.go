@@ -139,6 +139,47 @@ func TestPrevIP(t *testing.T) {
 	}
 }
 
+func TestNextIPOk(t *testing.T) {
+	tests := []struct {
+		ip      string
+		want    string
+		wrapped bool
+	}{
+		{"192.0.2.0", "192.0.2.1", false},
+		{"255.255.255.254", "255.255.255.255", false},
+		{"255.255.255.255", "0.0.0.0", true},
+		{"::ffff:255.255.255.255", "0.0.0.0", true},
+		{"::", "::1", false},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "::", true},
+	}
+	for _, tt := range tests {
+		got, wrapped := NextIPOk(net.ParseIP(tt.ip))
+		if !got.Equal(net.ParseIP(tt.want)) || wrapped != tt.wrapped {
+			t.Errorf("NextIPOk(%v) = %v, %v, want %v, %v", tt.ip, got, wrapped, tt.want, tt.wrapped)
+		}
+	}
+}
+
+func TestPrevIPOk(t *testing.T) {
+	tests := []struct {
+		ip      string
+		want    string
+		wrapped bool
+	}{
+		{"192.0.2.1", "192.0.2.0", false},
+		{"0.0.0.1", "0.0.0.0", false},
+		{"0.0.0.0", "255.255.255.255", true},
+		{"::1", "::", false},
+		{"::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", true},
+	}
+	for _, tt := range tests {
+		got, wrapped := PrevIPOk(net.ParseIP(tt.ip))
+		if !got.Equal(net.ParseIP(tt.want)) || wrapped != tt.wrapped {
+			t.Errorf("PrevIPOk(%v) = %v, %v, want %v, %v", tt.ip, got, wrapped, tt.want, tt.wrapped)
+		}
+	}
+}
+
 func TestAdd(t *testing.T) {
 	tests := []struct {
 		a    string
@@ -256,6 +297,22 @@ func TestXor(t *testing.T) {
 	}
 }
 
+func TestNot(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.1":        "63.255.253.254",
+		"0.0.0.0":          "255.255.255.255",
+		"::ffff:192.0.2.1": "63.255.253.254",
+		"2001:db8::1":      "dffe:f247:ffff:ffff:ffff:ffff:ffff:fffe",
+		"::":               "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+	}
+	for ip, want := range tests {
+		got := Not(net.ParseIP(ip))
+		if !bytes.Equal(got, IP(net.ParseIP(want))) {
+			t.Errorf("Not(%v) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
 func TestMerge(t *testing.T) {
 	tests := []struct {
 		a    string
@@ -297,6 +354,25 @@ func TestBroadcast(t *testing.T) {
 	}
 }
 
+func TestBroadcastNormalize(t *testing.T) {
+	tests := []struct {
+		n    net.IPNet
+		want net.IP
+	}{
+		{net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(24, 32)}, net.IP{192, 0, 2, 255}},
+		{net.IPNet{IP: net.IP{192, 0, 2, 0}, Mask: net.CIDRMask(120, 128)}, net.IP{192, 0, 2, 255}},
+		{net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(120, 128)}, net.IP{192, 0, 2, 255}},
+		{net.IPNet{IP: net.IP{192, 0, 2, 0}, Mask: net.CIDRMask(64, 128)}, nil},
+		{net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}, nil},
+		{net.IPNet{IP: nil, Mask: net.CIDRMask(24, 32)}, nil},
+	}
+	for _, tt := range tests {
+		if got := Broadcast(tt.n); !bytes.Equal(got, tt.want) {
+			t.Errorf("Broadcast(%v) = %v, want %v", &tt.n, got, tt.want)
+		}
+	}
+}
+
 func TestNextSubnet(t *testing.T) {
 	tests := map[string]string{
 		"192.0.2.0/23":         "192.0.4.0/23",
@@ -380,3 +456,181 @@ func TestContains(t *testing.T) {
 		}
 	}
 }
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{"192.0.2.0/24", "192.0.2.0/24", true},
+		{"192.0.2.0/24", "192.0.2.128/25", true},
+		{"192.0.2.128/25", "192.0.2.0/24", true},
+		{"192.0.2.0/24", "192.0.0.0/16", true},
+		{"192.0.2.0/24", "192.0.3.0/24", false},
+		{"0.0.0.0/0", "::/0", false},
+		{"2001:db8::/32", "2001:db8:ffff::/48", true},
+		{"2001:db8::/32", "2001:db9::/32", false},
+	}
+	for _, tt := range tests {
+		if got := Overlaps(mustCIDR(t, tt.a), mustCIDR(t, tt.b)); got != tt.want {
+			t.Errorf("Overlaps(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAdjacent(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{"192.0.2.0/24", "192.0.3.0/24", true},
+		{"192.0.2.0/24", "192.0.3.0/25", true},
+		{"192.0.2.0/24", "192.0.3.128/25", false},
+		{"192.0.3.0/24", "192.0.2.0/24", false},
+		{"192.0.2.0/24", "192.0.2.0/24", false},
+		{"255.255.255.0/24", "0.0.0.0/24", false},
+		{"255.255.255.255/32", "::/128", false},
+		{"2001:db8::/32", "2001:db9::/48", true},
+	}
+	for _, tt := range tests {
+		if got := Adjacent(mustCIDR(t, tt.a), mustCIDR(t, tt.b)); got != tt.want {
+			t.Errorf("Adjacent(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidArithmetic(t *testing.T) {
+	for _, ip := range []net.IP{nil, {1, 2, 3}} {
+		if got := NextIP(ip); got != nil {
+			t.Errorf("NextIP(%v) = %v, want nil", ip, got)
+		}
+		if got := PrevIP(ip); got != nil {
+			t.Errorf("PrevIP(%v) = %v, want nil", ip, got)
+		}
+		if got, wrapped := PrevIPOk(ip); got != nil || wrapped {
+			t.Errorf("PrevIPOk(%v) = %v, %v, want nil, false", ip, got, wrapped)
+		}
+		if got := Add(ip, net.ParseIP("0.0.0.1"), ParseMask("255.255.255.255")); got != nil {
+			t.Errorf("Add(%v) = %v, want nil", ip, got)
+		}
+		if got := Substract(ip, net.ParseIP("0.0.0.1"), ParseMask("255.255.255.255")); got != nil {
+			t.Errorf("Substract(%v) = %v, want nil", ip, got)
+		}
+	}
+}
+
+func BenchmarkNextIP(b *testing.B) {
+	ip := net.ParseIP("2001:db8::ffff:ffff")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ip = NextIP(ip)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	ip := net.ParseIP("2001:db8::ff")
+	off := net.ParseIP("::ff01")
+	mask := ParseMask("::ffff")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Add(ip, off, mask)
+	}
+}
+
+func BenchmarkBitwise(b *testing.B) {
+	for _, tt := range []struct {
+		name string
+		a, m net.IP
+	}{
+		{"IPv4", net.ParseIP("192.0.2.1"), net.ParseIP("255.255.254.0")},
+		{"IPv6", net.ParseIP("2001:db8::1"), net.ParseIP("ffff:ffff:ffff:ffff::")},
+	} {
+		for _, f := range []struct {
+			name string
+			fn   func(a, b net.IP) net.IP
+		}{{"And", And}, {"Or", Or}, {"Xor", Xor}} {
+			b.Run(f.name+"/"+tt.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					f.fn(tt.a, tt.m)
+				}
+			})
+		}
+	}
+}
+
+func TestParseIPMaskZone(t *testing.T) {
+	tests := []struct {
+		addr string
+		ip   string
+		zone string
+		mask string
+		ok   bool
+	}{
+		{"fe80::1%eth0/64", "fe80::1", "eth0", "ffff:ffff:ffff:ffff::", true},
+		{"fe80::1%eth0", "fe80::1", "eth0", "", true},
+		{"fe80::1%2/ffff:ffff:ffff:ffff::", "fe80::1", "2", "ffff:ffff:ffff:ffff::", true},
+		{"2001:db8::/64", "2001:db8::", "", "ffff:ffff:ffff:ffff::", true},
+		{"192.0.2.10/24", "192.0.2.10", "", "255.255.255.0", true},
+		{"192.0.2.10%eth0/24", "", "", "", false},
+		{"fe80::1%/64", "", "", "", false},
+		{"fe80::1%eth0/invalid", "", "", "", false},
+		{"invalid%eth0", "", "", "", false},
+		{"fe80::1/64%eth0", "", "", "", false},
+		{"fe80::1/64%eth0/64", "", "", "", false},
+	}
+	for _, tt := range tests {
+		addr, mask, err := ParseIPMaskZone(tt.addr)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseIPMaskZone(%v) error = %v, want ok = %v", tt.addr, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		if !addr.IP.Equal(net.ParseIP(tt.ip)) || addr.Zone != tt.zone || mask.String() != ParseMask(tt.mask).String() {
+			t.Errorf("ParseIPMaskZone(%v) = %v, %v, want %v%%%v, %v", tt.addr, &addr, mask, tt.ip, tt.zone, tt.mask)
+		}
+	}
+}
+
+func TestMappedPolicy(t *testing.T) {
+	v4, mapped, v6 := net.IP{192, 0, 2, 1}, net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")
+	tests := []struct {
+		ip       net.IP
+		collapse int
+		preserve int
+		expand   int
+		to4In6   int
+		from4In6 int
+	}{
+		{v4, 4, 4, 16, 16, 0},
+		{mapped, 4, 16, 16, 16, 4},
+		{v6, 16, 16, 16, 0, 0},
+		{net.IP{1, 2, 3}, 0, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		for _, f := range []struct {
+			name string
+			got  net.IP
+			want int
+		}{
+			{"IPWithPolicy(CollapseMapped)", IPWithPolicy(tt.ip, CollapseMapped), tt.collapse},
+			{"IPWithPolicy(PreserveMapped)", IPWithPolicy(tt.ip, PreserveMapped), tt.preserve},
+			{"IPWithPolicy(ExpandIPv4)", IPWithPolicy(tt.ip, ExpandIPv4), tt.expand},
+			{"To4In6", To4In6(tt.ip), tt.to4In6},
+			{"From4In6", From4In6(tt.ip), tt.from4In6},
+		} {
+			if len(f.got) != f.want || f.want != 0 && !f.got.Equal(tt.ip) {
+				t.Errorf("%v(%v) = %v (%v bytes), want %v bytes", f.name, tt.ip, f.got, len(f.got), f.want)
+			}
+		}
+	}
+	got := IPWithPolicy(mapped, PreserveMapped)
+	got[15] = 0
+	if mapped[15] == 0 {
+		t.Errorf("IPWithPolicy(%v, PreserveMapped) shares storage with its input", mapped)
+	}
+}
@@ -0,0 +1,54 @@
+package ipset
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicSet is a Set safe for concurrent use, lookups are lock-free and never wait for updates.
+// Updates are applied to a copy of the Set which then atomically replaces it (copy-on-write),
+// so they cost O(n) and are meant to be infrequent compared to lookups.
+// The zero value is an empty AtomicSet ready to use.
+type AtomicSet struct {
+	mu sync.Mutex // serializes updates
+	p  atomic.Pointer[Set]
+}
+
+// Load returns a snapshot of the Set, it must not be modified.
+func (a *AtomicSet) Load() *Set {
+	if s := a.p.Load(); s != nil {
+		return s
+	}
+	return &Set{}
+}
+
+// Store replaces the Set, s must not be modified afterwards.
+func (a *AtomicSet) Store(s *Set) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.p.Store(s)
+}
+
+// Update calls fn with a copy of the current Set and publishes it, unless fn returns an error.
+// Concurrent updates are serialized, lookups see either the old or the new Set, never a partial update.
+func (a *AtomicSet) Update(fn func(s *Set) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := a.Load().Clone()
+	if err := fn(s); err != nil {
+		return err
+	}
+	a.p.Store(s)
+	return nil
+}
+
+// Contains returns whether an IP address is in the current Set.
+func (a *AtomicSet) Contains(ip net.IP) bool {
+	return a.Load().Contains(ip)
+}
+
+// Matches is the same as Contains, it implements ipcalc.Matcher.
+func (a *AtomicSet) Matches(ip net.IP) bool {
+	return a.Contains(ip)
+}
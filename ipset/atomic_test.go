@@ -0,0 +1,73 @@
+package ipset
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// AtomicSet implements ipcalc.Matcher.
+var _ ipcalc.Matcher = &AtomicSet{}
+
+func TestAtomicSet(t *testing.T) {
+	var a AtomicSet
+	if a.Contains(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Contains(192.0.2.1) = true on empty AtomicSet")
+	}
+	if err := a.Update(func(s *Set) error { return s.AddPrefix(mustCIDR(t, "192.0.2.0/24")) }); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	old := a.Load()
+	wantErr := errors.New("rollback")
+	if err := a.Update(func(s *Set) error {
+		s.AddPrefix(mustCIDR(t, "198.51.100.0/24"))
+		return wantErr
+	}); err != wantErr {
+		t.Errorf("Update() error = %v, want %v", err, wantErr)
+	}
+	if err := a.Update(func(s *Set) error { return s.Remove(mustCIDR(t, "192.0.2.128/25")) }); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	want := []string{"192.0.2.0/25"}
+	if got := netStrings(a.Load().Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Load().Prefixes() = %v, want %v", got, want)
+	}
+	// Earlier snapshots are unaffected by updates.
+	want = []string{"192.0.2.0/24"}
+	if got := netStrings(old.Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("old snapshot Prefixes() = %v, want %v", got, want)
+	}
+}
+
+func TestAtomicSetConcurrent(t *testing.T) {
+	var a AtomicSet
+	a.Store(newSet(t, "192.0.2.0/24"))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if !a.Contains(net.ParseIP("192.0.2.1")) {
+					t.Errorf("Contains(192.0.2.1) = false, want true")
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		ip := ipcalc.Uint32ToIP(0xc6336400 + uint32(i))
+		if err := a.Update(func(s *Set) error { return s.AddIP(ip) }); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	wg.Wait()
+	want := []string{"192.0.2.0/24", "198.51.100.0/26", "198.51.100.64/27", "198.51.100.96/30"}
+	if got := netStrings(a.Load().Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Load().Prefixes() = %v, want %v", got, want)
+	}
+}
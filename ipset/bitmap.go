@@ -0,0 +1,184 @@
+package ipset
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"net"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// MaxBitmapBits is the maximum number of host bits of a Bitmap's network, i.e., a Bitmap uses at most 2^MaxBitmapBits bits.
+const MaxBitmapBits = 24
+
+// Bitmap is a set of the IPv4 addresses of a network, stored as one bit per address.
+// It's meant for dense data, e.g., the results of scanning a /16, where it uses far less memory than a Set or trie.
+// A Bitmap is not safe for concurrent use.
+type Bitmap struct {
+	prefix net.IPNet
+	base   uint32
+	words  []uint64
+	n      int
+}
+
+// NewBitmap returns an empty Bitmap for an IPv4 network with at most MaxBitmapBits host bits.
+func NewBitmap(n net.IPNet) (*Bitmap, error) {
+	n = ipcalc.Normalize(n)
+	ones, size := n.Mask.Size()
+	if size != 8*net.IPv4len || size-ones > MaxBitmapBits {
+		return nil, fmt.Errorf("ipset: can't create a bitmap for %v", &n)
+	}
+	base, _ := ipcalc.IPToUint32(n.IP)
+	return &Bitmap{
+		prefix: n,
+		base:   base,
+		words:  make([]uint64, (1<<uint(size-ones)+63)/64),
+	}, nil
+}
+
+// BitmapFromSet returns a Bitmap for an IPv4 network with the addresses of a Set within that network.
+func BitmapFromSet(n net.IPNet, s *Set) (*Bitmap, error) {
+	b, err := NewBitmap(n)
+	if err != nil {
+		return nil, err
+	}
+	last := b.base + uint32(b.size()-1)
+	for _, x := range s.v4 {
+		lo, hi := uint32(x.lo.Lo), uint32(x.hi.Lo)
+		if hi < b.base || lo > last {
+			continue
+		}
+		b.setRange(max(lo, b.base)-b.base, min(hi, last)-b.base)
+	}
+	return b, nil
+}
+
+// Prefix returns the network covered by the Bitmap.
+func (b *Bitmap) Prefix() net.IPNet {
+	return net.IPNet{IP: ipcalc.CopyIP(b.prefix.IP), Mask: append(net.IPMask(nil), b.prefix.Mask...)}
+}
+
+// Add adds an IP address to the Bitmap, it returns an error if ip is outside the Bitmap's network.
+func (b *Bitmap) Add(ip net.IP) error {
+	i, ok := b.index(ip)
+	if !ok {
+		return fmt.Errorf("ipset: %v not in %v", ip, &b.prefix)
+	}
+	if w, m := &b.words[i/64], uint64(1)<<(i%64); *w&m == 0 {
+		*w |= m
+		b.n++
+	}
+	return nil
+}
+
+// Remove removes an IP address from the Bitmap, it returns an error if ip is outside the Bitmap's network.
+func (b *Bitmap) Remove(ip net.IP) error {
+	i, ok := b.index(ip)
+	if !ok {
+		return fmt.Errorf("ipset: %v not in %v", ip, &b.prefix)
+	}
+	if w, m := &b.words[i/64], uint64(1)<<(i%64); *w&m != 0 {
+		*w &^= m
+		b.n--
+	}
+	return nil
+}
+
+// Contains returns whether an IP address is in the Bitmap.
+func (b *Bitmap) Contains(ip net.IP) bool {
+	i, ok := b.index(ip)
+	return ok && b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Matches is the same as Contains, it implements ipcalc.Matcher.
+func (b *Bitmap) Matches(ip net.IP) bool {
+	return b.Contains(ip)
+}
+
+// Len returns the number of addresses in the Bitmap.
+func (b *Bitmap) Len() int {
+	return b.n
+}
+
+// All returns an iterator over the addresses in the Bitmap, in ascending order.
+func (b *Bitmap) All() iter.Seq[net.IP] {
+	return func(yield func(net.IP) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				j := bits.TrailingZeros64(w)
+				if !yield(ipcalc.Uint32ToIP(b.base + uint32(64*i+j))) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// Set returns a Set with the addresses in the Bitmap.
+func (b *Bitmap) Set() *Set {
+	s := &Set{}
+	size := uint32(b.size())
+	for i := uint32(0); i < size; {
+		lo, ok := b.next(i, true)
+		if !ok {
+			break
+		}
+		hi, ok := b.next(lo, false)
+		if !ok {
+			hi = size
+		}
+		s.v4 = append(s.v4, span{uint128.Uint128{Lo: uint64(b.base + lo)}, uint128.Uint128{Lo: uint64(b.base + hi - 1)}})
+		i = hi
+	}
+	return s
+}
+
+// size returns the number of addresses in the Bitmap's network.
+func (b *Bitmap) size() int {
+	ones, bits := b.prefix.Mask.Size()
+	return 1 << uint(bits-ones)
+}
+
+// index returns the bit offset of an IP address, ok is false if ip is outside the Bitmap's network.
+func (b *Bitmap) index(ip net.IP) (uint32, bool) {
+	v, ok := ipcalc.IPToUint32(ip)
+	if !ok || v-b.base >= uint32(b.size()) {
+		return 0, false
+	}
+	return v - b.base, true
+}
+
+// setRange sets the bits from lo to hi inclusive.
+func (b *Bitmap) setRange(lo, hi uint32) {
+	for i := lo; ; i++ {
+		if w, m := &b.words[i/64], uint64(1)<<(i%64); *w&m == 0 {
+			*w |= m
+			b.n++
+		}
+		if i == hi {
+			return
+		}
+	}
+}
+
+// next returns the offset of the first bit at or after i that's set (or clear), ok is false if there's none.
+func (b *Bitmap) next(i uint32, set bool) (uint32, bool) {
+	size := uint32(b.size())
+	for i < size {
+		w := b.words[i/64]
+		if !set {
+			w = ^w
+		}
+		if w >>= i % 64; w != 0 {
+			if j := i + uint32(bits.TrailingZeros64(w)); j < size {
+				return j, true
+			}
+			return 0, false
+		}
+		i = (i/64 + 1) * 64
+	}
+	return 0, false
+}
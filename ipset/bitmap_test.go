@@ -0,0 +1,112 @@
+package ipset
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// Bitmap implements ipcalc.Matcher.
+var _ ipcalc.Matcher = &Bitmap{}
+
+func TestNewBitmap(t *testing.T) {
+	tests := []struct {
+		n    string
+		want string
+		ok   bool
+	}{
+		{"192.0.2.0/24", "192.0.2.0/24", true},
+		{"10.1.2.3/8", "10.0.0.0/8", true},
+		{"::ffff:192.0.2.0/120", "192.0.2.0/24", true},
+		{"192.0.2.1/32", "192.0.2.1/32", true},
+		{"10.0.0.0/7", "", false},
+		{"2001:db8::/120", "", false},
+	}
+	for _, tt := range tests {
+		b, err := NewBitmap(mustCIDR(t, tt.n))
+		if !tt.ok {
+			if err == nil {
+				t.Errorf("NewBitmap(%v) error = nil, want error", tt.n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewBitmap(%v) error = %v", tt.n, err)
+			continue
+		}
+		if got := b.Prefix(); got.String() != tt.want {
+			t.Errorf("NewBitmap(%v).Prefix() = %v, want %v", tt.n, &got, tt.want)
+		}
+	}
+}
+
+func TestBitmap(t *testing.T) {
+	b, err := NewBitmap(mustCIDR(t, "192.0.2.0/25"))
+	if err != nil {
+		t.Fatalf("NewBitmap() error = %v", err)
+	}
+	for _, ip := range []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.63", "192.0.2.64", "192.0.2.127", "192.0.2.1"} {
+		if err := b.Add(net.ParseIP(ip)); err != nil {
+			t.Errorf("Add(%v) error = %v", ip, err)
+		}
+	}
+	for _, ip := range []string{"192.0.2.128", "192.0.1.255", "2001:db8::1"} {
+		if err := b.Add(net.ParseIP(ip)); err == nil {
+			t.Errorf("Add(%v) error = nil, want error", ip)
+		}
+	}
+	if err := b.Remove(net.ParseIP("192.0.2.1")); err != nil {
+		t.Errorf("Remove(192.0.2.1) error = %v", err)
+	}
+	if b.Len() != 5 {
+		t.Errorf("Len() = %v, want 5", b.Len())
+	}
+	for ip, want := range map[string]bool{
+		"192.0.2.0":   true,
+		"192.0.2.1":   false,
+		"192.0.2.127": true,
+		"192.0.2.128": false,
+		"2001:db8::":  false,
+	} {
+		if got := b.Contains(net.ParseIP(ip)); got != want {
+			t.Errorf("Contains(%v) = %v, want %v", ip, got, want)
+		}
+	}
+	var got []string
+	for ip := range b.All() {
+		got = append(got, ip.String())
+	}
+	want := []string{"192.0.2.0", "192.0.2.2", "192.0.2.63", "192.0.2.64", "192.0.2.127"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	want = []string{"192.0.2.0/32", "192.0.2.2/32", "192.0.2.63/32", "192.0.2.64/32", "192.0.2.127/32"}
+	if got := netStrings(b.Set().Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Set().Prefixes() = %v, want %v", got, want)
+	}
+}
+
+func TestBitmapFromSet(t *testing.T) {
+	tests := []struct {
+		n    string
+		set  []string
+		want []string
+	}{
+		{"192.0.2.0/24", []string{"192.0.2.0/26", "192.0.2.128/25", "-192.0.2.200/32"}, []string{"192.0.2.0/26", "192.0.2.128/26", "192.0.2.192/29", "192.0.2.201/32", "192.0.2.202/31", "192.0.2.204/30", "192.0.2.208/28", "192.0.2.224/27"}},
+		{"192.0.2.0/24", []string{"192.0.0.0/16", "2001:db8::/32"}, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/24", []string{"192.0.1.0/24", "192.0.3.0/24"}, nil},
+		{"10.0.0.0/8", []string{"10.255.255.255/32", "11.0.0.0/8"}, []string{"10.255.255.255/32"}},
+	}
+	for _, tt := range tests {
+		b, err := BitmapFromSet(mustCIDR(t, tt.n), newSet(t, tt.set...))
+		if err != nil {
+			t.Errorf("BitmapFromSet(%v, %v) error = %v", tt.n, tt.set, err)
+			continue
+		}
+		if got := netStrings(b.Set().Prefixes()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BitmapFromSet(%v, %v).Set() = %v, want %v", tt.n, tt.set, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ipset
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// binaryVersion is the first byte of the binary encoding of a Set.
+const binaryVersion = 1
+
+// errBinary is returned when decoding a malformed binary encoding.
+var errBinary = errors.New("ipset: invalid binary encoding")
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The encoding is a version byte followed, for IPv4 and then IPv6, by a big-endian uint32 count
+// and that many pairs of first and last addresses in network byte order, i.e., 8 bytes per IPv4 range
+// and 32 bytes per IPv6 range.
+func (s *Set) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 1+4+8*len(s.v4)+4+32*len(s.v6))
+	b = append(b, binaryVersion)
+	for _, f := range []struct {
+		size  int
+		spans []span
+	}{{net.IPv4len, s.v4}, {net.IPv6len, s.v6}} {
+		b = binary.BigEndian.AppendUint32(b, uint32(len(f.spans)))
+		for _, x := range f.spans {
+			b = append(b, x.lo.IP(f.size)...)
+			b = append(b, x.hi.IP(f.size)...)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the contents of the Set.
+// The ranges must be in the canonical form produced by MarshalBinary, i.e., sorted and neither overlapping nor adjacent.
+func (s *Set) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errBinary
+	}
+	data = data[1:]
+	var n Set
+	for _, f := range []struct {
+		size  int
+		spans *[]span
+	}{{net.IPv4len, &n.v4}, {net.IPv6len, &n.v6}} {
+		if len(data) < 4 {
+			return errBinary
+		}
+		count := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(len(data)) < uint64(count)*uint64(2*f.size) {
+			return errBinary
+		}
+		*f.spans = make([]span, count)
+		for i := range *f.spans {
+			lo, _ := uint128.FromIP(net.IP(data[:f.size]))
+			hi, _ := uint128.FromIP(net.IP(data[f.size : 2*f.size]))
+			data = data[2*f.size:]
+			x := span{lo, hi}
+			if lo.Cmp(hi) > 0 || i > 0 && !before((*f.spans)[i-1], x) {
+				return errBinary
+			}
+			(*f.spans)[i] = x
+		}
+	}
+	if len(data) != 0 {
+		return errBinary
+	}
+	*s = n
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, a Set is encoded as the list of CIDR strings returned by Prefixes.
+func (s *Set) MarshalJSON() ([]byte, error) {
+	nets := s.Prefixes()
+	strs := make([]string, len(nets))
+	for i, n := range nets {
+		strs[i] = n.String()
+	}
+	return json.Marshal(strs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the contents of the Set with a list of CIDR strings,
+// which needn't be sorted or disjoint.
+func (s *Set) UnmarshalJSON(data []byte) error {
+	var strs []string
+	if err := json.Unmarshal(data, &strs); err != nil {
+		return err
+	}
+	var n Set
+	for _, str := range strs {
+		_, ipNet, err := net.ParseCIDR(str)
+		if err != nil {
+			return fmt.Errorf("ipset: %w", err)
+		}
+		if err := n.AddPrefix(*ipNet); err != nil {
+			return err
+		}
+	}
+	*s = n
+	return nil
+}
@@ -0,0 +1,88 @@
+package ipset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"192.0.2.0/24", "198.51.100.7/32"},
+		{"0.0.0.0/0", "::/0"},
+		{"192.0.2.0/24", "-192.0.2.100/32", "2001:db8::/32", "-2001:db8::/48"},
+	}
+	for _, tt := range tests {
+		s := newSet(t, tt...)
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Errorf("MarshalBinary(%v) error = %v", tt, err)
+			continue
+		}
+		var got Set
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)) error = %v", tt, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Prefixes(), s.Prefixes()) {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)) = %v, want %v", tt, netStrings(got.Prefixes()), netStrings(s.Prefixes()))
+		}
+	}
+}
+
+func TestUnmarshalBinaryError(t *testing.T) {
+	valid := []byte{binaryVersion, 0, 0, 0, 1, 192, 0, 2, 0, 192, 0, 2, 255, 0, 0, 0, 0}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"version", append([]byte{2}, valid[1:]...)},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing", append(append([]byte(nil), valid...), 0)},
+		{"reversed", []byte{binaryVersion, 0, 0, 0, 1, 192, 0, 2, 255, 192, 0, 2, 0, 0, 0, 0, 0}},
+		{"adjacent", []byte{binaryVersion, 0, 0, 0, 2, 192, 0, 2, 0, 192, 0, 2, 1, 192, 0, 2, 2, 192, 0, 2, 3, 0, 0, 0, 0}},
+		{"count", []byte{binaryVersion, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}},
+	}
+	var s Set
+	if err := s.UnmarshalBinary(valid); err != nil {
+		t.Fatalf("UnmarshalBinary(valid) error = %v", err)
+	}
+	for _, tt := range tests {
+		if err := s.UnmarshalBinary(tt.data); err == nil {
+			t.Errorf("UnmarshalBinary(%v) error = nil, want error", tt.name)
+		}
+	}
+	// A failed decoding leaves the Set untouched.
+	want := []string{"192.0.2.0/24"}
+	if got := netStrings(s.Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Prefixes() after failed UnmarshalBinary = %v, want %v", got, want)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	s := newSet(t, "192.0.2.0/25", "192.0.2.128/25", "2001:db8::/32")
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `["192.0.2.0/24","2001:db8::/32"]`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+	if b, _ := json.Marshal(&Set{}); string(b) != "[]" {
+		t.Errorf("json.Marshal(empty) = %s, want []", b)
+	}
+	var got Set
+	if err := json.Unmarshal([]byte(`["2001:db8::/32","192.0.2.0/25","192.0.2.128/25"]`), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Prefixes(), s.Prefixes()) {
+		t.Errorf("json.Unmarshal() = %v, want %v", netStrings(got.Prefixes()), netStrings(s.Prefixes()))
+	}
+	for _, in := range []string{`["192.0.2.0"]`, `"192.0.2.0/24"`, `[1]`} {
+		if err := json.Unmarshal([]byte(in), &got); err == nil {
+			t.Errorf("json.Unmarshal(%v) error = nil, want error", in)
+		}
+	}
+}
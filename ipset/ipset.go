@@ -0,0 +1,289 @@
+// Package ipset provides sets of IP addresses with set algebra, e.g., for blocklists and allow-lists.
+//
+// A Set is stored as sorted, non-overlapping address ranges per family, so adding
+// a large network costs the same as adding a single address:
+//
+//	var s ipset.Set
+//	s.AddPrefix(*bogons)
+//	s.Remove(*allowed)
+//	fmt.Println(s.Prefixes())
+//
+// IPv4-mapped IPv6 addresses are treated as IPv4 addresses.
+package ipset
+
+import (
+	"fmt"
+	"iter"
+	"net"
+	"slices"
+	"sort"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// span is an inclusive range of address values of a single family.
+type span struct {
+	lo, hi uint128.Uint128
+}
+
+// Set is a set of IP addresses, the zero value is an empty Set ready to use.
+// A Set is not safe for concurrent use.
+type Set struct {
+	v4, v6 []span
+}
+
+// AddPrefix adds every address of a network to the Set.
+func (s *Set) AddPrefix(n net.IPNet) error {
+	return s.AddRange(ipcalc.CIDRToRange(n))
+}
+
+// AddRange adds every address of a Range to the Set.
+func (s *Set) AddRange(r ipcalc.Range) error {
+	size, x, err := toSpan(r)
+	if err != nil {
+		return err
+	}
+	spans := s.spans(size)
+	*spans = add(*spans, x)
+	return nil
+}
+
+// AddIP adds an IP address to the Set.
+func (s *Set) AddIP(ip net.IP) error {
+	return s.AddRange(ipcalc.Range{First: ip, Last: ip})
+}
+
+// Remove removes every address of a network from the Set.
+func (s *Set) Remove(n net.IPNet) error {
+	return s.RemoveRange(ipcalc.CIDRToRange(n))
+}
+
+// RemoveRange removes every address of a Range from the Set.
+func (s *Set) RemoveRange(r ipcalc.Range) error {
+	size, x, err := toSpan(r)
+	if err != nil {
+		return err
+	}
+	spans := s.spans(size)
+	*spans = remove(*spans, x)
+	return nil
+}
+
+// RemoveIP removes an IP address from the Set.
+func (s *Set) RemoveIP(ip net.IP) error {
+	return s.RemoveRange(ipcalc.Range{First: ip, Last: ip})
+}
+
+// Contains returns whether an IP address is in the Set.
+func (s *Set) Contains(ip net.IP) bool {
+	ip = ipcalc.IP(ip)
+	v, ok := uint128.FromIP(ip)
+	if !ok {
+		return false
+	}
+	spans := *s.spans(len(ip))
+	i := sort.Search(len(spans), func(i int) bool { return spans[i].hi.Cmp(v) >= 0 })
+	return i < len(spans) && spans[i].lo.Cmp(v) <= 0
+}
+
+// Matches is the same as Contains, it implements ipcalc.Matcher.
+func (s *Set) Matches(ip net.IP) bool {
+	return s.Contains(ip)
+}
+
+// IsEmpty returns whether the Set has no addresses.
+func (s *Set) IsEmpty() bool {
+	return len(s.v4) == 0 && len(s.v6) == 0
+}
+
+// Union returns a new Set with the addresses in either s or o.
+func (s *Set) Union(o *Set) *Set {
+	return &Set{v4: union(s.v4, o.v4), v6: union(s.v6, o.v6)}
+}
+
+// Intersect returns a new Set with the addresses in both s and o.
+func (s *Set) Intersect(o *Set) *Set {
+	return &Set{v4: intersect(s.v4, o.v4), v6: intersect(s.v6, o.v6)}
+}
+
+// Difference returns a new Set with the addresses in s but not in o.
+func (s *Set) Difference(o *Set) *Set {
+	return &Set{v4: difference(s.v4, o.v4), v6: difference(s.v6, o.v6)}
+}
+
+// Diff returns the minimal lists of networks only in from (removed) and only in to (added),
+// i.e., the changes needed to turn from into to, in the same order as Prefixes.
+// e.g., Diff({192.0.2.0/24}, {192.0.2.0/25, 198.51.100.0/24}) -> [192.0.2.128/25], [198.51.100.0/24].
+func Diff(from, to *Set) (removed, added []net.IPNet) {
+	return from.Difference(to).Prefixes(), to.Difference(from).Prefixes()
+}
+
+// Clone returns a copy of the Set.
+func (s *Set) Clone() *Set {
+	return &Set{v4: append([]span(nil), s.v4...), v6: append([]span(nil), s.v6...)}
+}
+
+// Ranges returns the minimal list of Ranges covering exactly the Set, in ascending order with IPv4 first.
+func (s *Set) Ranges() []ipcalc.Range {
+	return slices.Collect(s.AllRanges())
+}
+
+// AllRanges returns an iterator over the Ranges returned by Ranges, without building the list.
+func (s *Set) AllRanges() iter.Seq[ipcalc.Range] {
+	return func(yield func(ipcalc.Range) bool) {
+		for _, f := range []struct {
+			size  int
+			spans []span
+		}{{net.IPv4len, s.v4}, {net.IPv6len, s.v6}} {
+			for _, x := range f.spans {
+				if !yield(ipcalc.Range{First: x.lo.IP(f.size), Last: x.hi.IP(f.size)}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Prefixes returns the minimal list of networks covering exactly the Set, in ascending order with IPv4 first.
+func (s *Set) Prefixes() []net.IPNet {
+	return slices.Collect(s.All())
+}
+
+// All returns an iterator over the networks returned by Prefixes, they're generated one range at a time.
+// e.g., All({192.0.2.0/25, 192.0.2.128/25, 2001:db8::/32}) -> 192.0.2.0/24, 2001:db8::/32.
+func (s *Set) All() iter.Seq[net.IPNet] {
+	return func(yield func(net.IPNet) bool) {
+		for r := range s.AllRanges() {
+			for _, n := range ipcalc.RangeToCIDRs(r) {
+				if !yield(n) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Set) spans(size int) *[]span {
+	if size == net.IPv4len {
+		return &s.v4
+	}
+	return &s.v6
+}
+
+// toSpan returns the address size and span of a Range.
+func toSpan(r ipcalc.Range) (int, span, error) {
+	first, last := ipcalc.IP(r.First), ipcalc.IP(r.Last)
+	lo, ok1 := uint128.FromIP(first)
+	hi, ok2 := uint128.FromIP(last)
+	if !ok1 || !ok2 || len(first) != len(last) || lo.Cmp(hi) > 0 {
+		return 0, span{}, fmt.Errorf("ipset: invalid range %v", r)
+	}
+	return len(first), span{lo, hi}, nil
+}
+
+// before returns whether a ends before b starts with a gap, i.e., they can't be merged.
+func before(a, b span) bool {
+	return a.hi.Cmp(b.lo) < 0 && a.hi.Add(uint128.One) != b.lo
+}
+
+// add returns spans with x added, merging overlapping and adjacent spans.
+func add(spans []span, x span) []span {
+	i := sort.Search(len(spans), func(i int) bool { return !before(spans[i], x) })
+	j := sort.Search(len(spans), func(j int) bool { return before(x, spans[j]) })
+	if i < j {
+		if spans[i].lo.Cmp(x.lo) < 0 {
+			x.lo = spans[i].lo
+		}
+		if spans[j-1].hi.Cmp(x.hi) > 0 {
+			x.hi = spans[j-1].hi
+		}
+	}
+	return append(spans[:i], append([]span{x}, spans[j:]...)...)
+}
+
+// remove returns spans with x removed.
+func remove(spans []span, x span) []span {
+	i := sort.Search(len(spans), func(i int) bool { return spans[i].hi.Cmp(x.lo) >= 0 })
+	j := sort.Search(len(spans), func(j int) bool { return spans[j].lo.Cmp(x.hi) > 0 })
+	if i == j {
+		return spans
+	}
+	var keep []span
+	if spans[i].lo.Cmp(x.lo) < 0 {
+		keep = append(keep, span{spans[i].lo, x.lo.Sub(uint128.One)})
+	}
+	if spans[j-1].hi.Cmp(x.hi) > 0 {
+		keep = append(keep, span{x.hi.Add(uint128.One), spans[j-1].hi})
+	}
+	return append(spans[:i], append(keep, spans[j:]...)...)
+}
+
+func union(a, b []span) []span {
+	var out []span
+	for len(a) > 0 || len(b) > 0 {
+		var x span
+		if len(b) == 0 || len(a) > 0 && a[0].lo.Cmp(b[0].lo) <= 0 {
+			x, a = a[0], a[1:]
+		} else {
+			x, b = b[0], b[1:]
+		}
+		if n := len(out); n > 0 && !before(out[n-1], x) {
+			if x.hi.Cmp(out[n-1].hi) > 0 {
+				out[n-1].hi = x.hi
+			}
+			continue
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+func intersect(a, b []span) []span {
+	var out []span
+	for len(a) > 0 && len(b) > 0 {
+		lo, hi := a[0].lo, a[0].hi
+		if b[0].lo.Cmp(lo) > 0 {
+			lo = b[0].lo
+		}
+		if b[0].hi.Cmp(hi) < 0 {
+			hi = b[0].hi
+		}
+		if lo.Cmp(hi) <= 0 {
+			out = append(out, span{lo, hi})
+		}
+		if a[0].hi.Cmp(b[0].hi) < 0 {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return out
+}
+
+func difference(a, b []span) []span {
+	var out []span
+	for _, x := range a {
+		for len(b) > 0 && b[0].hi.Cmp(x.lo) < 0 {
+			b = b[1:]
+		}
+		covered := false
+		for _, y := range b {
+			if y.lo.Cmp(x.hi) > 0 {
+				break
+			}
+			if y.lo.Cmp(x.lo) > 0 {
+				out = append(out, span{x.lo, y.lo.Sub(uint128.One)})
+			}
+			if y.hi.Cmp(x.hi) >= 0 {
+				covered = true
+				break
+			}
+			x.lo = y.hi.Add(uint128.One)
+		}
+		if !covered {
+			out = append(out, x)
+		}
+	}
+	return out
+}
@@ -0,0 +1,233 @@
+package ipset
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// Set implements ipcalc.Matcher.
+var _ ipcalc.Matcher = &Set{}
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func netStrings(nets []net.IPNet) []string {
+	var s []string
+	for _, n := range nets {
+		s = append(s, n.String())
+	}
+	return s
+}
+
+// newSet returns a Set of the given networks, removing those prefixed with "-".
+func newSet(t *testing.T, nets ...string) *Set {
+	t.Helper()
+	s := &Set{}
+	for _, n := range nets {
+		var err error
+		if n[0] == '-' {
+			err = s.Remove(mustCIDR(t, n[1:]))
+		} else {
+			err = s.AddPrefix(mustCIDR(t, n))
+		}
+		if err != nil {
+			t.Fatalf("newSet(%v) error = %v", nets, err)
+		}
+	}
+	return s
+}
+
+func TestAddRemove(t *testing.T) {
+	tests := []struct {
+		nets []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"192.0.2.0/25", "192.0.2.128/25"}, []string{"192.0.2.0/24"}},
+		{[]string{"192.0.2.0/24", "192.0.2.64/26"}, []string{"192.0.2.0/24"}},
+		{[]string{"192.0.2.0/26", "192.0.2.128/26", "192.0.2.64/26"}, []string{"192.0.2.0/25", "192.0.2.128/26"}},
+		{[]string{"192.0.2.0/24", "-192.0.2.0/25"}, []string{"192.0.2.128/25"}},
+		{[]string{"192.0.2.0/24", "-192.0.2.64/26"}, []string{"192.0.2.0/26", "192.0.2.128/25"}},
+		{[]string{"192.0.2.0/26", "192.0.2.128/26", "-192.0.2.32/27", "-192.0.2.128/27"}, []string{"192.0.2.0/27", "192.0.2.160/27"}},
+		{[]string{"192.0.2.0/24", "-198.51.100.0/24"}, []string{"192.0.2.0/24"}},
+		{[]string{"0.0.0.0/0", "-0.0.0.0/1"}, []string{"128.0.0.0/1"}},
+		{[]string{"0.0.0.0/1", "128.0.0.0/1"}, []string{"0.0.0.0/0"}},
+		{[]string{"::/0", "-::/0"}, nil},
+		{[]string{"2001:db8::/32", "192.0.2.0/24", "::ffff:198.51.100.0/120"}, []string{"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"}},
+	}
+	for _, tt := range tests {
+		if got := netStrings(newSet(t, tt.nets...).Prefixes()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Set(%v).Prefixes() = %v, want %v", tt.nets, got, tt.want)
+		}
+	}
+}
+
+func TestAddRangeIP(t *testing.T) {
+	var s Set
+	r := ipcalc.Range{First: net.ParseIP("192.0.2.5"), Last: net.ParseIP("192.0.2.12")}
+	if err := s.AddRange(r); err != nil {
+		t.Fatalf("AddRange(%v) error = %v", r, err)
+	}
+	if err := s.AddIP(net.ParseIP("192.0.2.13")); err != nil {
+		t.Fatalf("AddIP(192.0.2.13) error = %v", err)
+	}
+	if err := s.RemoveIP(net.ParseIP("192.0.2.8")); err != nil {
+		t.Fatalf("RemoveIP(192.0.2.8) error = %v", err)
+	}
+	want := []string{"192.0.2.5-192.0.2.7", "192.0.2.9-192.0.2.13"}
+	var got []string
+	for _, r := range s.Ranges() {
+		got = append(got, r.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Ranges() = %v, want %v", got, want)
+	}
+	for _, r := range []ipcalc.Range{
+		{First: net.ParseIP("192.0.2.2"), Last: net.ParseIP("192.0.2.1")},
+		{First: net.ParseIP("192.0.2.1"), Last: net.ParseIP("2001:db8::1")},
+		{},
+	} {
+		if err := s.AddRange(r); err == nil {
+			t.Errorf("AddRange(%v) error = nil, want error", r)
+		}
+		if err := s.RemoveRange(r); err == nil {
+			t.Errorf("RemoveRange(%v) error = nil, want error", r)
+		}
+	}
+	if err := s.AddPrefix(net.IPNet{}); err == nil {
+		t.Errorf("AddPrefix(<nil>) error = nil, want error")
+	}
+}
+
+func TestContains(t *testing.T) {
+	s := newSet(t, "192.0.2.0/24", "-192.0.2.128/26", "2001:db8::/64")
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"192.0.2.0", true},
+		{"192.0.2.127", true},
+		{"192.0.2.128", false},
+		{"192.0.2.192", true},
+		{"192.0.3.0", false},
+		{"::ffff:192.0.2.1", true},
+		{"2001:db8::ffff", true},
+		{"2001:db8:0:1::", false},
+		{"::", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := s.Contains(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("Contains(%v) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+	if !new(Set).IsEmpty() || s.IsEmpty() {
+		t.Errorf("IsEmpty() = %v, want false", s.IsEmpty())
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	a := newSet(t, "10.0.0.0/8", "192.0.2.0/24", "2001:db8::/32")
+	b := newSet(t, "10.128.0.0/9", "192.0.2.128/25", "198.51.100.0/24", "2001:db8:1::/48")
+	tests := []struct {
+		name string
+		got  *Set
+		want []string
+	}{
+		{"Union", a.Union(b), []string{"10.0.0.0/8", "192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"}},
+		{"Intersect", a.Intersect(b), []string{"10.128.0.0/9", "192.0.2.128/25", "2001:db8:1::/48"}},
+		{"Difference", a.Difference(b), []string{"10.0.0.0/9", "192.0.2.0/25", "2001:db8::/48", "2001:db8:2::/47", "2001:db8:4::/46", "2001:db8:8::/45", "2001:db8:10::/44", "2001:db8:20::/43", "2001:db8:40::/42", "2001:db8:80::/41", "2001:db8:100::/40", "2001:db8:200::/39", "2001:db8:400::/38", "2001:db8:800::/37", "2001:db8:1000::/36", "2001:db8:2000::/35", "2001:db8:4000::/34", "2001:db8:8000::/33"}},
+		{"Difference", b.Difference(a), []string{"198.51.100.0/24"}},
+		{"Difference", a.Difference(a), nil},
+		{"Intersect", a.Intersect(&Set{}), nil},
+		{"Union", newSet(t, "192.0.2.0/25").Union(newSet(t, "192.0.2.128/25")), []string{"192.0.2.0/24"}},
+		{"Difference", newSet(t, "192.0.2.0/24").Difference(newSet(t, "192.0.2.16/28", "192.0.2.64/27")), []string{"192.0.2.0/28", "192.0.2.32/27", "192.0.2.96/27", "192.0.2.128/25"}},
+	}
+	for _, tt := range tests {
+		if got := netStrings(tt.got.Prefixes()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	// The operands must not be modified.
+	if got := netStrings(a.Prefixes()); !reflect.DeepEqual(got, []string{"10.0.0.0/8", "192.0.2.0/24", "2001:db8::/32"}) {
+		t.Errorf("Prefixes() = %v after set operations", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := newSet(t, "192.0.2.0/24", "2001:db8::/32")
+	c := s.Clone()
+	c.Remove(mustCIDR(t, "192.0.2.0/25"))
+	c.AddPrefix(mustCIDR(t, "198.51.100.0/24"))
+	want := []string{"192.0.2.0/24", "2001:db8::/32"}
+	if got := netStrings(s.Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Prefixes() after modifying clone = %v, want %v", got, want)
+	}
+	want = []string{"192.0.2.128/25", "198.51.100.0/24", "2001:db8::/32"}
+	if got := netStrings(c.Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Clone().Prefixes() = %v, want %v", got, want)
+	}
+}
+
+func TestAll(t *testing.T) {
+	s := newSet(t, "192.0.2.0/25", "192.0.2.128/26", "198.51.100.0/24", "2001:db8::/32", "-198.51.100.0/26")
+	var got []string
+	for n := range s.All() {
+		got = append(got, n.String())
+	}
+	if want := netStrings(s.Prefixes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	got = nil
+	for r := range s.AllRanges() {
+		got = append(got, r.String())
+	}
+	want := []string{"192.0.2.0-192.0.2.191", "198.51.100.64-198.51.100.255", "2001:db8::-2001:db8:ffff:ffff:ffff:ffff:ffff:ffff"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllRanges() = %v, want %v", got, want)
+	}
+	count := 0
+	for range s.All() {
+		if count++; count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("All() yielded %v networks before break, want 2", count)
+	}
+	var empty Set
+	for n := range empty.All() {
+		t.Errorf("All() = %v on an empty Set", &n)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		from, to       []string
+		removed, added []string
+	}{
+		{[]string{"192.0.2.0/24"}, []string{"192.0.2.0/25", "198.51.100.0/24"}, []string{"192.0.2.128/25"}, []string{"198.51.100.0/24"}},
+		{[]string{"192.0.2.0/24", "2001:db8::/32"}, []string{"192.0.2.0/25", "192.0.2.128/25", "2001:db8::/32"}, nil, nil},
+		{nil, []string{"10.0.0.0/8", "-10.0.0.0/9"}, nil, []string{"10.128.0.0/9"}},
+		{[]string{"2001:db8::/32"}, []string{"2001:db8::/31"}, nil, []string{"2001:db9::/32"}},
+		{[]string{"192.0.2.0/24"}, nil, []string{"192.0.2.0/24"}, nil},
+	}
+	for _, tt := range tests {
+		removed, added := Diff(newSet(t, tt.from...), newSet(t, tt.to...))
+		if got := netStrings(removed); !reflect.DeepEqual(got, tt.removed) {
+			t.Errorf("Diff(%v, %v) removed = %v, want %v", tt.from, tt.to, got, tt.removed)
+		}
+		if got := netStrings(added); !reflect.DeepEqual(got, tt.added) {
+			t.Errorf("Diff(%v, %v) added = %v, want %v", tt.from, tt.to, got, tt.added)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ipset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// readBatch is the number of ranges buffered by Read before merging them into the Set.
+const readBatch = 1 << 16
+
+// Read returns a Set with the addresses listed in r, one per line as an IP address, CIDR network or
+// "first-last" range. Blank lines and anything after a '#' are ignored.
+// Entries are merged in batches, so memory use is bounded by the size of the resulting Set rather than the input.
+func Read(r io.Reader) (*Set, error) {
+	s := &Set{}
+	var batch4, batch6 []span
+	flush := func() {
+		for _, f := range []struct {
+			spans *[]span
+			batch *[]span
+		}{{&s.v4, &batch4}, {&s.v6, &batch6}} {
+			slices.SortFunc(*f.batch, func(a, b span) int { return a.lo.Cmp(b.lo) })
+			*f.spans = union(*f.spans, *f.batch)
+			*f.batch = (*f.batch)[:0]
+		}
+	}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		rng, err := parseLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("ipset: line %v: %w", line, err)
+		}
+		size, x, err := toSpan(rng)
+		if err != nil {
+			return nil, fmt.Errorf("ipset: line %v: %w", line, err)
+		}
+		if size == net.IPv4len {
+			batch4 = append(batch4, x)
+		} else {
+			batch6 = append(batch6, x)
+		}
+		if len(batch4)+len(batch6) >= readBatch {
+			flush()
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("ipset: %w", err)
+	}
+	flush()
+	return s, nil
+}
+
+// parseLine returns the Range of an IP address, CIDR network or "first-last" range.
+func parseLine(s string) (ipcalc.Range, error) {
+	switch {
+	case strings.Contains(s, "/"):
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return ipcalc.Range{}, err
+		}
+		return ipcalc.CIDRToRange(*n), nil
+	case strings.Contains(s, "-"):
+		return ipcalc.ParseRange(s)
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return ipcalc.Range{}, &net.ParseError{Type: "IP address", Text: s}
+	}
+	return ipcalc.Range{First: ip, Last: ip}, nil
+}
+
+// WriteTo implements io.WriterTo, it writes the networks returned by Prefixes to w, one per line.
+// Networks are generated one range at a time as they're written, see All, and writes to w are buffered.
+// The returned count is the number of bytes written to w, even if an error occurs.
+func (s *Set) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for n := range s.All() {
+		if _, err := fmt.Fprintln(bw, &n); err != nil {
+			return cw.n, err
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to an io.Writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Aggregate reads addresses from r as Read does and writes the minimal list of networks covering them to w,
+// one per line in ascending order with IPv4 first.
+// e.g., "192.0.2.0/25\n192.0.2.128/25\n192.0.2.7\n" -> "192.0.2.0/24\n".
+func Aggregate(r io.Reader, w io.Writer) error {
+	s, err := Read(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.WriteTo(w)
+	return err
+}
@@ -0,0 +1,101 @@
+package ipset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"", "", true},
+		{"# feed v1\n\n192.0.2.0/25\n192.0.2.128/25 # second half\n192.0.2.7\n", "192.0.2.0/24\n", true},
+		{"2001:db8::/33\n  2001:db8:8000::/33\n10.0.0.1-10.0.0.6\n::ffff:10.0.0.7\n", "10.0.0.1/32\n10.0.0.2/31\n10.0.0.4/30\n2001:db8::/32\n", true},
+		{"192.0.2.1\nnot-an-address\n", "", false},
+		{"192.0.2.0/33\n", "", false},
+		{"192.0.2.1\n192.0.2.5-192.0.2.2\n", "", false},
+		{"192.0.2.1x\n", "", false},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		err := Aggregate(strings.NewReader(tt.in), &b)
+		if !tt.ok {
+			if err == nil {
+				t.Errorf("Aggregate(%q) error = nil, want error", tt.in)
+			}
+			continue
+		}
+		if err != nil || b.String() != tt.want {
+			t.Errorf("Aggregate(%q) = %q, %v, want %q", tt.in, b.String(), err, tt.want)
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+	_, err := Read(strings.NewReader("192.0.2.1\n\n# comment\nbogus\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("Read() error = %v, want error on line 4", err)
+	}
+}
+
+func TestReadBatches(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	want, err := NewBitmap(mustCIDR(t, "10.0.0.0/14"))
+	if err != nil {
+		t.Fatalf("NewBitmap() error = %v", err)
+	}
+	var in strings.Builder
+	for i := 0; i < 3*readBatch; i++ {
+		ip := ipcalc.Uint32ToIP(0x0a000000 | r.Uint32()&0x3ffff)
+		fmt.Fprintln(&in, ip)
+		want.Add(ip)
+	}
+	got, err := Read(strings.NewReader(in.String()))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Ranges(), want.Set().Ranges()) {
+		t.Errorf("Read() = %v ranges, want %v", len(got.Ranges()), len(want.Set().Ranges()))
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	s := newSet(t, "192.0.2.0/24", "-192.0.2.0/32", "2001:db8::/32")
+	var b bytes.Buffer
+	n, err := s.WriteTo(&b)
+	want := "192.0.2.1/32\n192.0.2.2/31\n192.0.2.4/30\n192.0.2.8/29\n192.0.2.16/28\n192.0.2.32/27\n192.0.2.64/26\n192.0.2.128/25\n2001:db8::/32\n"
+	if err != nil || b.String() != want || n != int64(len(want)) {
+		t.Errorf("WriteTo() = %v, %q, %v, want %v, %q", n, b.String(), err, len(want), want)
+	}
+	// Only the bytes which reached the writer are counted.
+	s = newSet(t, "0.0.0.0/0", "-0.0.0.0/32", "::/0", "-::/128")
+	lw := &limitWriter{max: 1000}
+	n, err = s.WriteTo(lw)
+	if err == nil || n != int64(lw.written) || n != 1000 {
+		t.Errorf("WriteTo(limited) = %v, %v, wrote %v bytes", n, err, lw.written)
+	}
+}
+
+// limitWriter accepts up to max bytes, then fails.
+type limitWriter struct {
+	max, written int
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	n := min(len(p), w.max-w.written)
+	w.written += n
+	if n < len(p) {
+		return n, errors.New("limitWriter: full")
+	}
+	return n, nil
+}
@@ -0,0 +1,55 @@
+package ipcalc
+
+import (
+	"iter"
+	"net"
+)
+
+// Addresses returns an iterator over every address in a network, in ascending order.
+// e.g., Addresses(192.0.2.0/30) -> 192.0.2.0, 192.0.2.1, 192.0.2.2, 192.0.2.3.
+func Addresses(n net.IPNet) iter.Seq[net.IP] {
+	return rangeSeq(CIDRToRange(n))
+}
+
+// Hosts returns an iterator over every usable host address in a network, in ascending order.
+// See NthHost for which addresses are usable.
+// e.g., Hosts(192.0.2.0/30) -> 192.0.2.1, 192.0.2.2.
+func Hosts(n net.IPNet) iter.Seq[net.IP] {
+	return rangeSeq(Range{First: FirstHost(n), Last: LastHost(n)})
+}
+
+// Subnets returns an iterator over the subnets of the given prefix length in a network, in ascending order.
+// Unlike Split the number of subnets isn't limited, the iterator is empty if newPrefix is out of range.
+// e.g., Subnets(192.0.2.0/24, 25) -> 192.0.2.0/25, 192.0.2.128/25.
+func Subnets(n net.IPNet, newPrefix int) iter.Seq[net.IPNet] {
+	return func(yield func(net.IPNet) bool) {
+		first, ones, size, err := splitBase(n)
+		if err != nil || newPrefix < ones || newPrefix > size {
+			return
+		}
+		last := Broadcast(net.IPNet{IP: first, Mask: net.CIDRMask(ones, size)})
+		sub := net.IPNet{IP: first, Mask: net.CIDRMask(newPrefix, size)}
+		for {
+			if !yield(sub) || Broadcast(sub).Equal(last) {
+				return
+			}
+			sub = NextSubnet(sub)
+		}
+	}
+}
+
+// rangeSeq returns an iterator over every address in a Range, which is empty if the Range is invalid.
+func rangeSeq(r Range) iter.Seq[net.IP] {
+	return func(yield func(net.IP) bool) {
+		if !sameFamily(r.First, r.Last) || Compare(r.First, r.Last) > 0 {
+			return
+		}
+		ip := IP(r.First)
+		for {
+			if !yield(CopyIP(ip)) || ip.Equal(r.Last) {
+				return
+			}
+			ip = NextIP(ip)
+		}
+	}
+}
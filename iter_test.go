@@ -0,0 +1,101 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestAddresses(t *testing.T) {
+	tests := []struct {
+		n    string
+		want []string
+	}{
+		{"192.0.2.0/30", []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3"}},
+		{"192.0.2.1/32", []string{"192.0.2.1"}},
+		{"255.255.255.254/31", []string{"255.255.255.254", "255.255.255.255"}},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe/127", []string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for ip := range Addresses(mustCIDR(t, tt.n)) {
+			got = append(got, ip.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Addresses(%v) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHosts(t *testing.T) {
+	tests := []struct {
+		n    string
+		want []string
+	}{
+		{"192.0.2.0/30", []string{"192.0.2.1", "192.0.2.2"}},
+		{"192.0.2.0/31", []string{"192.0.2.0", "192.0.2.1"}},
+		{"192.0.2.1/32", []string{"192.0.2.1"}},
+		{"255.255.255.248/29", []string{"255.255.255.249", "255.255.255.250", "255.255.255.251", "255.255.255.252", "255.255.255.253", "255.255.255.254"}},
+		{"2001:db8::/127", []string{"2001:db8::", "2001:db8::1"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for ip := range Hosts(mustCIDR(t, tt.n)) {
+			got = append(got, ip.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Hosts(%v) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	bad := net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}
+	for ip := range Hosts(bad) {
+		t.Errorf("Hosts(%v) yielded %v, want nothing", &bad, ip)
+	}
+}
+
+func TestHostsBreak(t *testing.T) {
+	var got []net.IP
+	for ip := range Hosts(mustCIDR(t, "2001:db8::/64")) {
+		got = append(got, ip)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if len(got) != 3 || !got[2].Equal(net.ParseIP("2001:db8::2")) || got[0].Equal(got[1]) {
+		t.Errorf("Hosts(2001:db8::/64) = %v, want [2001:db8:: 2001:db8::1 2001:db8::2]", got)
+	}
+}
+
+func TestSubnets(t *testing.T) {
+	tests := []struct {
+		n         string
+		newPrefix int
+		want      []string
+	}{
+		{"192.0.2.0/24", 25, []string{"192.0.2.0/25", "192.0.2.128/25"}},
+		{"192.0.2.0/24", 24, []string{"192.0.2.0/24"}},
+		{"::ffff:192.0.2.0/120", 25, []string{"192.0.2.0/25", "192.0.2.128/25"}},
+		{"255.255.255.0/24", 26, []string{"255.255.255.0/26", "255.255.255.64/26", "255.255.255.128/26", "255.255.255.192/26"}},
+		{"::/0", 2, []string{"::/2", "4000::/2", "8000::/2", "c000::/2"}},
+		{"192.0.2.0/24", 23, nil},
+		{"192.0.2.0/24", 33, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for n := range Subnets(mustCIDR(t, tt.n), tt.newPrefix) {
+			got = append(got, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Subnets(%v, %v) = %v, want %v", tt.n, tt.newPrefix, got, tt.want)
+		}
+	}
+	count := 0
+	for range Subnets(mustCIDR(t, "2001:db8::/32"), 64) {
+		if count++; count == 1000 {
+			break
+		}
+	}
+	if count != 1000 {
+		t.Errorf("Subnets(2001:db8::/32, 64) yielded %v subnets before break, want 1000", count)
+	}
+}
@@ -0,0 +1,34 @@
+package ipcalc
+
+import "net"
+
+// NetKey is a comparable representation of a network, see KeyNet.
+type NetKey struct {
+	IP   [16]byte
+	Mask [16]byte
+}
+
+// Key returns a comparable fixed-size key for an IP address, suitable for use as a map key.
+// IPv4 addresses use their IPv4-mapped IPv6 form, so 4-byte and 16-byte IPv4 addresses have the same key.
+// Invalid addresses return the zero key, which is also the key for ::.
+func Key(ip net.IP) [16]byte {
+	var k [16]byte
+	copy(k[:], ip.To16())
+	return k
+}
+
+// KeyNet returns a comparable fixed-size key for a network, suitable for use as a map key.
+// The network is normalized first, see Normalize, IPv4 masks use their IPv4-mapped form.
+func KeyNet(n net.IPNet) NetKey {
+	var k NetKey
+	ip, mask, ok := normalizeNet(n)
+	if !ok {
+		return k
+	}
+	k.IP = Key(ip.Mask(mask))
+	if len(mask) == net.IPv4len {
+		copy(k.Mask[:], net.CIDRMask(96, 128))
+	}
+	copy(k.Mask[len(k.Mask)-len(mask):], mask)
+	return k
+}
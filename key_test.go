@@ -0,0 +1,50 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		a, b net.IP
+		want bool
+	}{
+		{net.ParseIP("192.0.2.1"), net.IP{192, 0, 2, 1}, true},
+		{net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.2"), false},
+		{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8:0::1"), true},
+		{net.ParseIP("::c000:201"), net.ParseIP("192.0.2.1"), false},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.a) == Key(tt.b); got != tt.want {
+			t.Errorf("Key(%v) == Key(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+	k := Key(net.IP{192, 0, 2, 1})
+	if got := net.IP(k[:]); !got.Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Key(192.0.2.1) = %v, want 192.0.2.1", got)
+	}
+}
+
+func TestKeyNet(t *testing.T) {
+	tests := []struct {
+		a, b net.IPNet
+		want bool
+	}{
+		{mustCIDR(t, "192.0.2.0/24"), net.IPNet{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(120, 128)}, true},
+		{mustCIDR(t, "192.0.2.0/24"), mustCIDR(t, "192.0.2.0/25"), false},
+		{mustCIDR(t, "192.0.2.0/24"), mustCIDR(t, "::ffff:192.0.2.0/120"), true},
+		{mustCIDR(t, "192.0.2.0/24"), mustCIDR(t, "::c000:200/120"), false},
+		{mustCIDR(t, "0.0.0.0/0"), mustCIDR(t, "::/0"), false},
+		{mustCIDR(t, "2001:db8::/32"), net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(32, 128)}, true},
+	}
+	for _, tt := range tests {
+		if got := KeyNet(tt.a) == KeyNet(tt.b); got != tt.want {
+			t.Errorf("KeyNet(%v) == KeyNet(%v) = %v, want %v", &tt.a, &tt.b, got, tt.want)
+		}
+	}
+	m := map[NetKey]string{KeyNet(mustCIDR(t, "192.0.2.0/24")): "test-net-1"}
+	if got := m[KeyNet(mustCIDR(t, "192.0.2.77/24"))]; got != "test-net-1" {
+		t.Errorf("map lookup = %q, want test-net-1", got)
+	}
+}
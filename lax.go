@@ -0,0 +1,93 @@
+package ipcalc
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// LaxFlags selects the legacy IPv4 forms accepted by ParseIPv4Lax, flags can be combined with |.
+// Plain dotted decimal addresses are always accepted.
+type LaxFlags uint
+
+// Lax parsing flags, following inet_aton(3).
+const (
+	// LaxInteger accepts a single number for the whole address, e.g., 3221225985.
+	LaxInteger LaxFlags = 1 << iota
+	// LaxShort accepts 2 and 3 part forms, whose last part fills the remaining bytes, e.g., 192.168.1 or 10.1.
+	LaxShort
+	// LaxHex accepts hexadecimal parts with a 0x prefix, e.g., 0xC0000201 or 0xc0.0.2.1.
+	LaxHex
+	// LaxOctal accepts octal parts with a leading 0, e.g., 0300.0.2.1, otherwise leading zeros are an error.
+	LaxOctal
+
+	// LaxAll accepts every form inet_aton(3) does.
+	LaxAll = LaxInteger | LaxShort | LaxHex | LaxOctal
+)
+
+// ParseIPv4Lax parses an IPv4 address, also accepting the legacy forms selected by flags.
+// e.g., ParseIPv4Lax(0300.0.2.1, LaxOctal) -> 192.0.2.1, ParseIPv4Lax(192.168.1, LaxShort) -> 192.168.0.1.
+func ParseIPv4Lax(s string, flags LaxFlags) (net.IP, error) {
+	parts := strings.Split(s, ".")
+	switch n := len(parts); {
+	case n > net.IPv4len,
+		n == 1 && flags&LaxInteger == 0,
+		n > 1 && n < net.IPv4len && flags&LaxShort == 0:
+		return nil, &net.ParseError{Type: "IPv4 address", Text: s}
+	}
+	var v uint32
+	for i, p := range parts {
+		x, ok := parseLaxPart(p, flags)
+		// The last part fills the remaining bytes, the others are a single byte.
+		shift, limit := 8*(net.IPv4len-1-i), uint64(0xff)
+		if i == len(parts)-1 {
+			shift, limit = 0, 1<<(8*(net.IPv4len-i))-1
+		}
+		if !ok || x > limit {
+			return nil, &net.ParseError{Type: "IPv4 address", Text: s}
+		}
+		v |= uint32(x) << shift
+	}
+	return Uint32ToIP(v), nil
+}
+
+// parseLaxPart parses a single part of a lax IPv4 address.
+func parseLaxPart(p string, flags LaxFlags) (uint64, bool) {
+	base := 10
+	switch {
+	case len(p) > 2 && (p[:2] == "0x" || p[:2] == "0X"):
+		if flags&LaxHex == 0 {
+			return 0, false
+		}
+		base, p = 16, p[2:]
+	case len(p) > 1 && p[0] == '0':
+		if flags&LaxOctal == 0 {
+			return 0, false
+		}
+		base, p = 8, p[1:]
+	}
+	x, err := strconv.ParseUint(p, base, 32)
+	return x, err == nil
+}
+
+// ParseCIDRLax is like net.ParseCIDR but also accepts abbreviated IPv4 networks whose missing trailing octets
+// are zero, as routers do.
+// e.g., ParseCIDRLax(172.16/12) -> 172.16.0.0, 172.16.0.0/12.
+func ParseCIDRLax(s string) (net.IP, *net.IPNet, error) {
+	addr, prefix, ok := strings.Cut(s, "/")
+	if !ok || strings.Contains(addr, ":") {
+		return net.ParseCIDR(s)
+	}
+	parts := strings.Split(addr, ".")
+	if len(parts) > net.IPv4len {
+		return nil, nil, &net.ParseError{Type: "CIDR address", Text: s}
+	}
+	for len(parts) < net.IPv4len {
+		parts = append(parts, "0")
+	}
+	ip, n, err := net.ParseCIDR(strings.Join(parts, ".") + "/" + prefix)
+	if err != nil {
+		return nil, nil, &net.ParseError{Type: "CIDR address", Text: s}
+	}
+	return ip, n, nil
+}
@@ -0,0 +1,81 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseIPv4Lax(t *testing.T) {
+	tests := []struct {
+		s     string
+		flags LaxFlags
+		want  string
+	}{
+		{"192.0.2.1", 0, "192.0.2.1"},
+		{"0.0.0.0", 0, "0.0.0.0"},
+		{"3221225985", LaxInteger, "192.0.2.1"},
+		{"3221225985", LaxAll &^ LaxInteger, ""},
+		{"0xC0000201", LaxInteger | LaxHex, "192.0.2.1"},
+		{"0xC0000201", LaxInteger, ""},
+		{"0xc0.0x0.0x2.0x1", LaxHex, "192.0.2.1"},
+		{"0300.0.2.1", LaxOctal, "192.0.2.1"},
+		{"0300.0.2.1", 0, ""},
+		{"0300.0.2.018", LaxOctal, ""},
+		{"192.168.1", LaxShort, "192.168.0.1"},
+		{"192.168.1", LaxAll &^ LaxShort, ""},
+		{"192.168.65535", LaxShort, "192.168.255.255"},
+		{"192.168.65536", LaxShort, ""},
+		{"10.1", LaxShort, "10.0.0.1"},
+		{"10.16777215", LaxShort, "10.255.255.255"},
+		{"127.1", LaxAll, "127.0.0.1"},
+		{"4294967295", LaxAll, "255.255.255.255"},
+		{"4294967296", LaxAll, ""},
+		{"256.0.0.1", LaxAll, ""},
+		{"1.2.3.4.5", LaxAll, ""},
+		{"1..2.3", LaxAll, ""},
+		{"0x", LaxAll, ""},
+		{"+1.2.3.4", LaxAll, ""},
+		{"1_0.0.0.1", LaxAll, ""},
+		{"", LaxAll, ""},
+		{"::1", LaxAll, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseIPv4Lax(tt.s, tt.flags)
+		if tt.want == "" {
+			if _, ok := err.(*net.ParseError); !ok {
+				t.Errorf("ParseIPv4Lax(%q, %v) = %v, %v, want ParseError", tt.s, tt.flags, got, err)
+			}
+		} else if err != nil || !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("ParseIPv4Lax(%q, %v) = %v, %v, want %v", tt.s, tt.flags, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCIDRLax(t *testing.T) {
+	tests := []struct {
+		s    string
+		ip   string
+		want string
+	}{
+		{"10/8", "10.0.0.0", "10.0.0.0/8"},
+		{"172.16/12", "172.16.0.0", "172.16.0.0/12"},
+		{"192.168.1/24", "192.168.1.0", "192.168.1.0/24"},
+		{"192.0.2.1/24", "192.0.2.1", "192.0.2.0/24"},
+		{"2001:db8::1/32", "2001:db8::1", "2001:db8::/32"},
+		{"10/33", "", ""},
+		{"10", "", ""},
+		{"1.2.3.4.5/8", "", ""},
+		{"10./8", "", ""},
+		{"/8", "", ""},
+	}
+	for _, tt := range tests {
+		ip, n, err := ParseCIDRLax(tt.s)
+		if tt.want == "" {
+			if _, ok := err.(*net.ParseError); !ok {
+				t.Errorf("ParseCIDRLax(%q) = %v, %v, %v, want ParseError", tt.s, ip, n, err)
+			}
+		} else if err != nil || !ip.Equal(net.ParseIP(tt.ip)) || n.String() != tt.want {
+			t.Errorf("ParseCIDRLax(%q) = %v, %v, %v, want %v, %v", tt.s, ip, n, err, tt.ip, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ipcalc
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Errors returned when parsing input exceeds the configured Limits.
+var (
+	ErrTooLong      = errors.New("ipcalc: input too long")
+	ErrTooManyElems = errors.New("ipcalc: too many list elements")
+	ErrTimeout      = errors.New("ipcalc: parse timeout")
+)
+
+// Limits bounds the work done when parsing untrusted input, e.g., address specs supplied by users.
+// A zero value for any field means that dimension is unlimited.
+type Limits struct {
+	// MaxLen is the maximum input length in bytes.
+	MaxLen int
+	// MaxElems is the maximum number of elements in a list.
+	MaxElems int
+	// Timeout is the maximum time spent parsing a list.
+	Timeout time.Duration
+}
+
+// ParseIPMask is like the package-level ParseIPMask, but enforces MaxLen and
+// rejects masks whose length doesn't match the address family.
+// It never panics, regardless of input.
+func (l Limits) ParseIPMask(addr string) (net.IP, net.IPMask, error) {
+	if l.MaxLen > 0 && len(addr) > l.MaxLen {
+		return nil, nil, ErrTooLong
+	}
+	ip, mask, err := ParseIPMask(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	ip = IP(ip)
+	if mask != nil && len(mask) != len(ip) {
+		return nil, nil, &net.ParseError{Type: "IP/Mask", Text: addr}
+	}
+	return ip, mask, nil
+}
+
+// ParseList parses a list of ip[/mask] elements separated by commas and/or whitespace.
+// Elements without a mask are returned with a host mask, e.g., /32 for IPv4.
+// MaxLen applies to the whole list, MaxElems and Timeout are checked as elements are parsed.
+// It never panics, regardless of input.
+// e.g., ParseList("192.0.2.0/24, 2001:db8::1") -> [192.0.2.0/24 2001:db8::1/128].
+func (l Limits) ParseList(list string) ([]net.IPNet, error) {
+	if l.MaxLen > 0 && len(list) > l.MaxLen {
+		return nil, ErrTooLong
+	}
+	var deadline time.Time
+	if l.Timeout > 0 {
+		deadline = time.Now().Add(l.Timeout)
+	}
+	var nets []net.IPNet
+	for list != "" {
+		var elem string
+		elem, list = nextElem(list)
+		if elem == "" {
+			continue
+		}
+		if l.MaxElems > 0 && len(nets) == l.MaxElems {
+			return nil, ErrTooManyElems
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, ErrTimeout
+		}
+		ip, mask, err := l.ParseIPMask(elem)
+		if err != nil {
+			return nil, err
+		}
+		if mask == nil {
+			mask = net.CIDRMask(len(ip)*8, len(ip)*8)
+		}
+		nets = append(nets, net.IPNet{IP: ip, Mask: mask})
+	}
+	return nets, nil
+}
+
+// nextElem splits the first list element from the rest of a list.
+func nextElem(list string) (string, string) {
+	i := strings.IndexFunc(list, isListSep)
+	if i < 0 {
+		return list, ""
+	}
+	return list[:i], list[i+1:]
+}
+
+func isListSep(r rune) bool {
+	switch r {
+	case ',', ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
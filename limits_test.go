@@ -0,0 +1,101 @@
+package ipcalc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimitsParseIPMask(t *testing.T) {
+	tests := []struct {
+		addr string
+		l    Limits
+		ok   bool
+	}{
+		{"192.0.2.0/24", Limits{}, true},
+		{"192.0.2.0/24", Limits{MaxLen: 12}, true},
+		{"192.0.2.0/24", Limits{MaxLen: 11}, false},
+		{"2001:db8::/255.255.0.0", Limits{}, false},
+		{"192.0.2.0/ffff::", Limits{}, false},
+		{"192.0.2.0/~0.0.0.255", Limits{}, true},
+		{"invalid", Limits{}, false},
+	}
+	for _, tt := range tests {
+		_, _, err := tt.l.ParseIPMask(tt.addr)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%+v.ParseIPMask(%v) error = %v", tt.l, tt.addr, err)
+		}
+	}
+}
+
+func TestLimitsParseList(t *testing.T) {
+	tests := []struct {
+		list string
+		l    Limits
+		want []string
+		err  error
+	}{
+		{"192.0.2.0/24, 2001:db8::1", Limits{}, []string{"192.0.2.0/24", "2001:db8::1/128"}, nil},
+		{" 192.0.2.1\n192.0.2.2\t,, ", Limits{}, []string{"192.0.2.1/32", "192.0.2.2/32"}, nil},
+		{"", Limits{}, nil, nil},
+		{"192.0.2.1,192.0.2.2", Limits{MaxElems: 2}, []string{"192.0.2.1/32", "192.0.2.2/32"}, nil},
+		{"192.0.2.1,192.0.2.2,192.0.2.3", Limits{MaxElems: 2}, nil, ErrTooManyElems},
+		{"192.0.2.1,192.0.2.2", Limits{MaxLen: 10}, nil, ErrTooLong},
+		{"192.0.2.1,192.0.2.2", Limits{Timeout: -time.Second}, []string{"192.0.2.1/32", "192.0.2.2/32"}, nil},
+	}
+	for _, tt := range tests {
+		nets, err := tt.l.ParseList(tt.list)
+		if err != tt.err {
+			t.Errorf("%+v.ParseList(%q) error = %v, want %v", tt.l, tt.list, err, tt.err)
+			continue
+		}
+		var got []string
+		for _, n := range nets {
+			got = append(got, n.String())
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%+v.ParseList(%q) = %v, want %v", tt.l, tt.list, got, tt.want)
+		}
+	}
+	if _, err := (Limits{}).ParseList("192.0.2.0/33"); err == nil {
+		t.Errorf("ParseList(192.0.2.0/33) error = nil")
+	}
+}
+
+func TestLimitsParseListTimeout(t *testing.T) {
+	list := strings.Repeat("2001:db8::1/64, ", 100000)
+	if _, err := (Limits{Timeout: time.Nanosecond}).ParseList(list); !errors.Is(err, ErrTimeout) {
+		t.Errorf("ParseList() with 1ns timeout error = %v, want %v", err, ErrTimeout)
+	}
+	if _, err := (Limits{Timeout: time.Minute}).ParseList(list); err != nil {
+		t.Errorf("ParseList() with 1m timeout error = %v, want nil", err)
+	}
+}
+
+func FuzzParseIPMask(f *testing.F) {
+	for _, s := range []string{"192.0.2.0/24", "2001:db8::/~ffff::", "192.0.2.0/255.255.255.0", "::ffff:192.0.2.1/120", "/", "~/~"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, addr string) {
+		ip, mask, err := (Limits{MaxLen: 128}).ParseIPMask(addr)
+		if err != nil {
+			return
+		}
+		if mask != nil && len(mask) != len(ip) {
+			t.Errorf("ParseIPMask(%q) = %v/%v, mismatched lengths", addr, ip, mask)
+		}
+	})
+}
+
+func FuzzParseList(f *testing.F) {
+	for _, s := range []string{"192.0.2.0/24, 2001:db8::1", "a,b,,c", " \t\n", "192.0.2.1/~0.0.0.255 ::/0"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, list string) {
+		nets, err := (Limits{MaxLen: 4096, MaxElems: 16, Timeout: time.Second}).ParseList(list)
+		if err == nil && len(nets) > 16 {
+			t.Errorf("ParseList(%q) returned %v elements, want <= 16", list, len(nets))
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Package listener provides a net.Listener dropping connections by remote IP address.
+//
+// Remote addresses are tested against ipcalc.Matcher values, e.g., ipcalc.Nets or wildcard.Wildcard:
+//
+//	l := listener.FilterListener(ln, ipcalc.Nets{*office}, blocked)
+//	http.Serve(l, handler)
+package listener
+
+import (
+	"net"
+	"sync/atomic"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// FilteredListener is a net.Listener which closes connections from disallowed remote addresses
+// before they're returned by Accept.
+type FilteredListener struct {
+	net.Listener
+	allow    ipcalc.Matcher
+	deny     ipcalc.Matcher
+	rejected atomic.Uint64
+}
+
+// FilterListener returns a FilteredListener accepting connections from l.
+// If allow is non-nil only matching remote addresses are accepted,
+// if deny is non-nil matching remote addresses are rejected, deny takes precedence over allow.
+// Connections whose remote address isn't an IP address are always rejected.
+func FilterListener(l net.Listener, allow, deny ipcalc.Matcher) *FilteredListener {
+	return &FilteredListener{
+		Listener: l,
+		allow:    allow,
+		deny:     deny,
+	}
+}
+
+// Accept waits for and returns the next connection from an allowed remote address.
+func (l *FilteredListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.Allowed(addrIP(c.RemoteAddr())) {
+			return c, nil
+		}
+		l.rejected.Add(1)
+		c.Close()
+	}
+}
+
+// Allowed returns whether connections from an IP address are accepted.
+func (l *FilteredListener) Allowed(ip net.IP) bool {
+	if ip == nil || l.deny != nil && l.deny.Matches(ip) {
+		return false
+	}
+	return l.allow == nil || l.allow.Matches(ip)
+}
+
+// Rejected returns the number of connections rejected so far.
+func (l *FilteredListener) Rejected() uint64 {
+	return l.rejected.Load()
+}
+
+// addrIP returns the IP address of a net.Addr, or nil if it doesn't have one.
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	case *net.IPAddr:
+		return a.IP
+	case nil:
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,93 @@
+package listener
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+type fakeAddr string
+
+func (a fakeAddr) Network() string { return "fake" }
+func (a fakeAddr) String() string  { return string(a) }
+
+type fakeConn struct {
+	net.Conn
+	remote net.Addr
+	closed bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr { return c.remote }
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type fakeListener struct {
+	net.Listener
+	conns []*fakeConn
+}
+
+func (l *fakeListener) Accept() (net.Conn, error) {
+	if len(l.conns) == 0 {
+		return nil, errors.New("closed")
+	}
+	c := l.conns[0]
+	l.conns = l.conns[1:]
+	return c, nil
+}
+
+func TestFilterListener(t *testing.T) {
+	conns := []*fakeConn{
+		{remote: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1}},
+		{remote: &net.TCPAddr{IP: net.ParseIP("198.51.100.1"), Port: 1}},
+		{remote: &net.TCPAddr{IP: net.ParseIP("192.0.2.66"), Port: 1}},
+		{remote: fakeAddr("192.0.2.2:1")},
+		{remote: fakeAddr("/tmp/sock")},
+		{remote: &net.UDPAddr{IP: net.ParseIP("192.0.2.3"), Port: 1}},
+	}
+	l := FilterListener(&fakeListener{conns: append([]*fakeConn(nil), conns...)}, ipcalc.Nets{mustCIDR(t, "192.0.2.0/24")}, ipcalc.Nets{mustCIDR(t, "192.0.2.64/26")})
+	var got []net.Conn
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			break
+		}
+		got = append(got, c)
+	}
+	want := []net.Conn{conns[0], conns[3], conns[5]}
+	if len(got) != len(want) {
+		t.Fatalf("Accept() returned %v connections, want %v", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Accept()[%v] = %v, want %v", i, got[i].RemoteAddr(), want[i].RemoteAddr())
+		}
+	}
+	for i, c := range conns {
+		if closed := i == 1 || i == 2 || i == 4; c.closed != closed {
+			t.Errorf("conn %v closed = %v, want %v", c.remote, c.closed, closed)
+		}
+	}
+	if got := l.Rejected(); got != 3 {
+		t.Errorf("Rejected() = %v, want 3", got)
+	}
+}
+
+func TestFilterListenerNoFilters(t *testing.T) {
+	l := FilterListener(nil, nil, nil)
+	if !l.Allowed(net.ParseIP("2001:db8::1")) || l.Allowed(nil) {
+		t.Errorf("Allowed() with no filters rejected an IP address or accepted nil")
+	}
+}
@@ -0,0 +1,226 @@
+// Package mac provides arithmetic utilities for 48-bit MAC and 64-bit EUI-64 hardware addresses,
+// mirroring the ipcalc utilities for IP addresses.
+//
+// Note that next/prev functions are subject to wrapping,
+// e.g., NextMAC(ff:ff:ff:ff:ff:ff) -> 00:00:00:00:00:00.
+package mac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"iter"
+	"math/big"
+	"net"
+	"strings"
+)
+
+// ParseMAC parses a hardware address in any form accepted by net.ParseMAC, e.g., Cisco dotted 0011.2233.4455,
+// or as bare hexadecimal digits, e.g., 001122334455.
+// Only 48-bit and 64-bit addresses are accepted.
+func ParseMAC(s string) (net.HardwareAddr, error) {
+	var hw net.HardwareAddr
+	var err error
+	if len(s) == 12 || len(s) == 16 {
+		hw, err = hex.DecodeString(s)
+	} else {
+		hw, err = net.ParseMAC(s)
+	}
+	if err != nil || !valid(hw) {
+		return nil, &net.ParseError{Type: "MAC address", Text: s}
+	}
+	return hw, nil
+}
+
+// FormatCisco returns the Cisco dotted form of a hardware address, or "" if it's invalid.
+// e.g., FormatCisco(00:11:22:33:44:55) -> 0011.2233.4455.
+func FormatCisco(hw net.HardwareAddr) string {
+	if !valid(hw) {
+		return ""
+	}
+	groups := make([]string, len(hw)/2)
+	for i := range groups {
+		groups[i] = hex.EncodeToString(hw[2*i : 2*i+2])
+	}
+	return strings.Join(groups, ".")
+}
+
+// FormatBare returns the bare hexadecimal form of a hardware address, or "" if it's invalid.
+// e.g., FormatBare(00:11:22:33:44:55) -> 001122334455.
+func FormatBare(hw net.HardwareAddr) string {
+	if !valid(hw) {
+		return ""
+	}
+	return hex.EncodeToString(hw)
+}
+
+// NextMAC returns the next hardware address, or nil if it's invalid.
+// e.g., NextMAC(00:11:22:33:44:ff) -> 00:11:22:33:45:00.
+func NextMAC(hw net.HardwareAddr) net.HardwareAddr {
+	return AddMAC(hw, 1)
+}
+
+// PrevMAC returns the previous hardware address, or nil if it's invalid.
+// e.g., PrevMAC(00:11:22:33:45:00) -> 00:11:22:33:44:ff.
+func PrevMAC(hw net.HardwareAddr) net.HardwareAddr {
+	return AddMAC(hw, -1)
+}
+
+// AddMAC returns a hardware address offset by n, which may be negative, or nil if it's invalid.
+// e.g., AddMAC(00:11:22:33:44:55, 16) -> 00:11:22:33:44:65.
+func AddMAC(hw net.HardwareAddr, n int64) net.HardwareAddr {
+	v, ok := toUint64(hw)
+	if !ok {
+		return nil
+	}
+	return fromUint64(v+uint64(n), len(hw))
+}
+
+// OUI returns the Organizationally Unique Identifier of a hardware address, i.e., its first 3 bytes,
+// or nil if it's invalid.
+// e.g., OUI(00:11:22:33:44:55) -> 00:11:22.
+func OUI(hw net.HardwareAddr) net.HardwareAddr {
+	if !valid(hw) {
+		return nil
+	}
+	return append(net.HardwareAddr(nil), hw[:3]...)
+}
+
+// IsMulticast returns whether a hardware address is a group address, i.e., its I/G bit is set.
+func IsMulticast(hw net.HardwareAddr) bool {
+	return valid(hw) && hw[0]&0x01 != 0
+}
+
+// IsLocal returns whether a hardware address is locally administered, i.e., its U/L bit is set.
+func IsLocal(hw net.HardwareAddr) bool {
+	return valid(hw) && hw[0]&0x02 != 0
+}
+
+// Mask returns a hardware address with all but its first ones bits cleared, or nil if it's invalid.
+// e.g., Mask(00:11:22:33:44:55, 24) -> 00:11:22:00:00:00.
+func Mask(hw net.HardwareAddr, ones int) net.HardwareAddr {
+	if !valid(hw) || ones < 0 || ones > 8*len(hw) {
+		return nil
+	}
+	m := prefixMask(ones, len(hw))
+	masked := make(net.HardwareAddr, len(hw))
+	for i := range hw {
+		masked[i] = hw[i] & m[i]
+	}
+	return masked
+}
+
+// Compare returns an integer comparing two hardware addresses,
+// shorter addresses sort before longer ones and invalid addresses first.
+func Compare(a, b net.HardwareAddr) int {
+	if valid(a) != valid(b) {
+		if valid(a) {
+			return 1
+		}
+		return -1
+	}
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a, b)
+}
+
+// Range is an inclusive interval of hardware addresses of the same length.
+type Range struct {
+	First net.HardwareAddr
+	Last  net.HardwareAddr
+}
+
+// PrefixRange returns the Range of hardware addresses sharing the first ones bits of hw,
+// or a zero Range if it's invalid.
+// e.g., PrefixRange(00:11:22:33:44:55, 24) -> 00:11:22:00:00:00-00:11:22:ff:ff:ff.
+func PrefixRange(hw net.HardwareAddr, ones int) Range {
+	first := Mask(hw, ones)
+	if first == nil {
+		return Range{}
+	}
+	last := make(net.HardwareAddr, len(hw))
+	m := prefixMask(ones, len(hw))
+	for i := range first {
+		last[i] = first[i] | ^m[i]
+	}
+	return Range{First: first, Last: last}
+}
+
+// String returns the "first-last" form of a Range.
+func (r Range) String() string {
+	return r.First.String() + "-" + r.Last.String()
+}
+
+// Len returns the number of addresses in a Range, or 0 if it's empty or invalid.
+func (r Range) Len() *big.Int {
+	if !r.valid() {
+		return new(big.Int)
+	}
+	n := new(big.Int).Sub(new(big.Int).SetBytes(r.Last), new(big.Int).SetBytes(r.First))
+	return n.Add(n, big.NewInt(1))
+}
+
+// Contains returns whether a hardware address is within a Range.
+func (r Range) Contains(hw net.HardwareAddr) bool {
+	return r.valid() && len(hw) == len(r.First) && Compare(r.First, hw) <= 0 && Compare(hw, r.Last) <= 0
+}
+
+// All returns an iterator over every address in a Range, in ascending order.
+func (r Range) All() iter.Seq[net.HardwareAddr] {
+	return func(yield func(net.HardwareAddr) bool) {
+		if !r.valid() {
+			return
+		}
+		hw := append(net.HardwareAddr(nil), r.First...)
+		for {
+			if !yield(hw) || bytes.Equal(hw, r.Last) {
+				return
+			}
+			hw = NextMAC(hw)
+		}
+	}
+}
+
+func (r Range) valid() bool {
+	return valid(r.First) && len(r.First) == len(r.Last) && bytes.Compare(r.First, r.Last) <= 0
+}
+
+// valid returns whether a hardware address is 48 or 64 bits long.
+func valid(hw net.HardwareAddr) bool {
+	return len(hw) == 6 || len(hw) == 8
+}
+
+// prefixMask returns a mask of the given length in bytes with its first ones bits set,
+// net.CIDRMask only supports IP address lengths.
+func prefixMask(ones, size int) []byte {
+	m := make([]byte, size)
+	for i := range m {
+		switch n := ones - 8*i; {
+		case n >= 8:
+			m[i] = 0xff
+		case n > 0:
+			m[i] = ^byte(0xff >> uint(n))
+		}
+	}
+	return m
+}
+
+func toUint64(hw net.HardwareAddr) (uint64, bool) {
+	if !valid(hw) {
+		return 0, false
+	}
+	var b [8]byte
+	copy(b[8-len(hw):], hw)
+	return binary.BigEndian.Uint64(b[:]), true
+}
+
+// fromUint64 returns the hardware address of the given length for the low bytes of v.
+func fromUint64(v uint64, size int) net.HardwareAddr {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(net.HardwareAddr(nil), b[8-size:]...)
+}
@@ -0,0 +1,203 @@
+package mac
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func mustMAC(t *testing.T, s string) net.HardwareAddr {
+	t.Helper()
+	hw, err := net.ParseMAC(s)
+	if err != nil {
+		t.Fatalf("ParseMAC(%v) error = %v", s, err)
+	}
+	return hw
+}
+
+func TestParseMAC(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"00:11:22:33:44:55", "00:11:22:33:44:55"},
+		{"00-11-22-33-44-55", "00:11:22:33:44:55"},
+		{"0011.2233.4455", "00:11:22:33:44:55"},
+		{"001122334455", "00:11:22:33:44:55"},
+		{"00AABBCCDDEE", "00:aa:bb:cc:dd:ee"},
+		{"0011223344556677", "00:11:22:33:44:55:66:77"},
+		{"0011.2233.4455.6677", "00:11:22:33:44:55:66:77"},
+		{"00112233445", ""},
+		{"00112233445g", ""},
+		{"00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseMAC(tt.s)
+		if tt.want == "" {
+			if _, ok := err.(*net.ParseError); !ok {
+				t.Errorf("ParseMAC(%q) = %v, %v, want ParseError", tt.s, got, err)
+			}
+		} else if err != nil || got.String() != tt.want {
+			t.Errorf("ParseMAC(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		hw    net.HardwareAddr
+		cisco string
+		bare  string
+	}{
+		{mustMAC(t, "00:11:22:33:44:55"), "0011.2233.4455", "001122334455"},
+		{mustMAC(t, "00:11:22:33:44:55:66:77"), "0011.2233.4455.6677", "0011223344556677"},
+		{nil, "", ""},
+	}
+	for _, tt := range tests {
+		if got := FormatCisco(tt.hw); got != tt.cisco {
+			t.Errorf("FormatCisco(%v) = %v, want %v", tt.hw, got, tt.cisco)
+		}
+		if got := FormatBare(tt.hw); got != tt.bare {
+			t.Errorf("FormatBare(%v) = %v, want %v", tt.hw, got, tt.bare)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		hw   string
+		n    int64
+		next string
+		prev string
+		add  string
+	}{
+		{"00:11:22:33:44:55", 16, "00:11:22:33:44:56", "00:11:22:33:44:54", "00:11:22:33:44:65"},
+		{"00:11:22:33:44:ff", -256, "00:11:22:33:45:00", "00:11:22:33:44:fe", "00:11:22:33:43:ff"},
+		{"ff:ff:ff:ff:ff:ff", 2, "00:00:00:00:00:00", "ff:ff:ff:ff:ff:fe", "00:00:00:00:00:01"},
+		{"00:00:00:00:00:00:00:00", -1, "00:00:00:00:00:00:00:01", "ff:ff:ff:ff:ff:ff:ff:ff", "ff:ff:ff:ff:ff:ff:ff:ff"},
+	}
+	for _, tt := range tests {
+		hw := mustMAC(t, tt.hw)
+		if got := NextMAC(hw).String(); got != tt.next {
+			t.Errorf("NextMAC(%v) = %v, want %v", hw, got, tt.next)
+		}
+		if got := PrevMAC(hw).String(); got != tt.prev {
+			t.Errorf("PrevMAC(%v) = %v, want %v", hw, got, tt.prev)
+		}
+		if got := AddMAC(hw, tt.n).String(); got != tt.add {
+			t.Errorf("AddMAC(%v, %v) = %v, want %v", hw, tt.n, got, tt.add)
+		}
+	}
+	if got := NextMAC(net.HardwareAddr{1, 2, 3}); got != nil {
+		t.Errorf("NextMAC(01:02:03) = %v, want nil", got)
+	}
+}
+
+func TestBits(t *testing.T) {
+	tests := []struct {
+		hw        string
+		oui       string
+		multicast bool
+		local     bool
+	}{
+		{"00:11:22:33:44:55", "00:11:22", false, false},
+		{"01:00:5e:00:00:01", "01:00:5e", true, false},
+		{"02:42:ac:11:00:02", "02:42:ac", false, true},
+		{"33:33:00:00:00:01", "33:33:00", true, true},
+	}
+	for _, tt := range tests {
+		hw := mustMAC(t, tt.hw)
+		if got := OUI(hw).String(); got != tt.oui {
+			t.Errorf("OUI(%v) = %v, want %v", hw, got, tt.oui)
+		}
+		if got := IsMulticast(hw); got != tt.multicast {
+			t.Errorf("IsMulticast(%v) = %v, want %v", hw, got, tt.multicast)
+		}
+		if got := IsLocal(hw); got != tt.local {
+			t.Errorf("IsLocal(%v) = %v, want %v", hw, got, tt.local)
+		}
+	}
+	if got := OUI(nil); got != nil {
+		t.Errorf("OUI(nil) = %v, want nil", got)
+	}
+}
+
+func TestMask(t *testing.T) {
+	hw := mustMAC(t, "00:11:22:33:44:55")
+	tests := []struct {
+		ones int
+		want string
+	}{
+		{0, "00:00:00:00:00:00"},
+		{24, "00:11:22:00:00:00"},
+		{36, "00:11:22:33:40:00"},
+		{48, "00:11:22:33:44:55"},
+		{49, ""},
+		{-1, ""},
+	}
+	for _, tt := range tests {
+		if got := Mask(hw, tt.ones); got.String() != tt.want {
+			t.Errorf("Mask(%v, %v) = %v, want %v", hw, tt.ones, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a, b := mustMAC(t, "00:11:22:33:44:55"), mustMAC(t, "00:11:22:33:44:56")
+	eui := mustMAC(t, "00:00:00:00:00:00:00:00")
+	tests := []struct {
+		a, b net.HardwareAddr
+		want int
+	}{
+		{a, b, -1},
+		{b, a, 1},
+		{a, a, 0},
+		{b, eui, -1},
+		{nil, a, -1},
+		{a, nil, 1},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := PrefixRange(mustMAC(t, "00:11:22:33:44:55"), 44)
+	if got := r.String(); got != "00:11:22:33:44:50-00:11:22:33:44:5f" {
+		t.Errorf("PrefixRange(00:11:22:33:44:55, 44) = %v, want 00:11:22:33:44:50-00:11:22:33:44:5f", got)
+	}
+	if got := r.Len().Int64(); got != 16 {
+		t.Errorf("Len(%v) = %v, want 16", r, got)
+	}
+	var all []string
+	for hw := range r.All() {
+		all = append(all, hw.String())
+	}
+	if len(all) != 16 || all[0] != "00:11:22:33:44:50" || all[15] != "00:11:22:33:44:5f" {
+		t.Errorf("All(%v) = %v", r, all)
+	}
+	for _, tt := range []struct {
+		hw   string
+		want bool
+	}{
+		{"00:11:22:33:44:50", true},
+		{"00:11:22:33:44:5f", true},
+		{"00:11:22:33:44:60", false},
+		{"00:11:22:33:44:50:00:00", false},
+	} {
+		if got := r.Contains(mustMAC(t, tt.hw)); got != tt.want {
+			t.Errorf("Contains(%v, %v) = %v, want %v", r, tt.hw, got, tt.want)
+		}
+	}
+	full := PrefixRange(mustMAC(t, "00:00:00:00:00:00:00:00"), 0)
+	if got := full.Len().String(); got != "18446744073709551616" {
+		t.Errorf("Len(%v) = %v, want 18446744073709551616", full, got)
+	}
+	if got := PrefixRange(nil, 0); !reflect.DeepEqual(got, Range{}) || got.Len().Sign() != 0 {
+		t.Errorf("PrefixRange(nil, 0) = %v, want zero Range", got)
+	}
+}
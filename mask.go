@@ -0,0 +1,39 @@
+package ipcalc
+
+import (
+	"math/bits"
+	"net"
+)
+
+// PrefixLen returns the number of leading one bits of a mask and whether the mask is contiguous,
+// i.e., whether those are its only one bits.
+// Unlike net.IPMask.Size the length is returned for non-contiguous masks as well,
+// use PrefixLen(Complement(mask)) for wildcard masks.
+// e.g., PrefixLen(255.255.254.0) -> 23, true, PrefixLen(255.0.255.0) -> 8, false.
+func PrefixLen(mask net.IPMask) (int, bool) {
+	n := 0
+	for _, b := range mask {
+		n += bits.LeadingZeros8(^b)
+		if b != 0xff {
+			break
+		}
+	}
+	return n, n == MaskBits(mask)
+}
+
+// IsCIDRMask returns whether a mask is a valid contiguous IPv4 or IPv6 mask.
+// e.g., IsCIDRMask(255.255.254.0) -> true.
+func IsCIDRMask(mask net.IPMask) bool {
+	_, ok := PrefixLen(mask)
+	return ok && (len(mask) == net.IPv4len || len(mask) == net.IPv6len)
+}
+
+// MaskBits returns the number of one bits in a mask, whether it's contiguous or not.
+// e.g., MaskBits(255.0.255.0) -> 16.
+func MaskBits(mask net.IPMask) int {
+	n := 0
+	for _, b := range mask {
+		n += bits.OnesCount8(b)
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPrefixLen(t *testing.T) {
+	tests := []struct {
+		mask   net.IPMask
+		want   int
+		ok     bool
+		isCIDR bool
+		bits   int
+	}{
+		{ParseMask("255.255.254.0"), 23, true, true, 23},
+		{ParseMask("255.255.255.255"), 32, true, true, 32},
+		{ParseMask("0.0.0.0"), 0, true, true, 0},
+		{ParseMask("255.0.255.0"), 8, false, false, 16},
+		{ParseMask("0.0.0.255"), 0, false, false, 8},
+		{ParseMask("255.255.255.254"), 31, true, true, 31},
+		{net.CIDRMask(64, 128), 64, true, true, 64},
+		{net.CIDRMask(128, 128), 128, true, true, 128},
+		{net.IPMask{0xff, 0xff, 0xff}, 24, true, false, 24},
+		{nil, 0, true, false, 0},
+	}
+	for _, tt := range tests {
+		got, ok := PrefixLen(tt.mask)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("PrefixLen(%v) = %v, %v, want %v, %v", tt.mask, got, ok, tt.want, tt.ok)
+		}
+		if got := IsCIDRMask(tt.mask); got != tt.isCIDR {
+			t.Errorf("IsCIDRMask(%v) = %v, want %v", tt.mask, got, tt.isCIDR)
+		}
+		if got := MaskBits(tt.mask); got != tt.bits {
+			t.Errorf("MaskBits(%v) = %v, want %v", tt.mask, got, tt.bits)
+		}
+	}
+}
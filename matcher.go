@@ -0,0 +1,41 @@
+package ipcalc
+
+import "net"
+
+// Matcher is implemented by types testing IP address membership, e.g., wildcard.Wildcard.
+type Matcher interface {
+	Matches(ip net.IP) bool
+}
+
+// MatcherFunc adapts an ordinary function to a Matcher.
+type MatcherFunc func(ip net.IP) bool
+
+// Matches returns f(ip).
+func (f MatcherFunc) Matches(ip net.IP) bool {
+	return f(ip)
+}
+
+// Nets is a Matcher for a list of networks, an IP address matches if any network contains it.
+type Nets []net.IPNet
+
+// Matches returns whether any network contains an IP address.
+func (n Nets) Matches(ip net.IP) bool {
+	for _, x := range n {
+		if x.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Any returns a Matcher matching IP addresses matched by any of the given Matchers.
+func Any(m ...Matcher) Matcher {
+	return MatcherFunc(func(ip net.IP) bool {
+		for _, x := range m {
+			if x.Matches(ip) {
+				return true
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,45 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetsMatches(t *testing.T) {
+	n := Nets{mustCIDR(t, "192.0.2.0/24"), mustCIDR(t, "2001:db8::/32")}
+	tests := map[string]bool{
+		"192.0.2.1":        true,
+		"::ffff:192.0.2.1": true,
+		"192.0.3.1":        false,
+		"2001:db8::1":      true,
+		"2001:db9::1":      false,
+	}
+	for ip, want := range tests {
+		if got := n.Matches(net.ParseIP(ip)); got != want {
+			t.Errorf("Matches(%v) = %v, want %v", ip, got, want)
+		}
+	}
+	if (Nets{}).Matches(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Nets{}.Matches() = true")
+	}
+}
+
+func TestAny(t *testing.T) {
+	loopback := MatcherFunc(net.IP.IsLoopback)
+	m := Any(Nets{mustCIDR(t, "192.0.2.0/24")}, loopback)
+	tests := map[string]bool{
+		"192.0.2.1":   true,
+		"127.0.0.1":   true,
+		"::1":         true,
+		"198.51.0.1":  false,
+		"2001:db8::1": false,
+	}
+	for ip, want := range tests {
+		if got := m.Matches(net.ParseIP(ip)); got != want {
+			t.Errorf("Matches(%v) = %v, want %v", ip, got, want)
+		}
+	}
+	if Any().Matches(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Any().Matches() = true")
+	}
+}
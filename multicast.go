@@ -0,0 +1,91 @@
+package ipcalc
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+)
+
+// MulticastCollision is a set of IPv4 multicast groups sharing the same Ethernet MAC address.
+type MulticastCollision struct {
+	MAC    net.HardwareAddr
+	Groups []net.IP
+}
+
+// MulticastCollisions reports IPv4 multicast groups mapping onto the same 01:00:5e MAC address.
+// Only the low 23 bits of a group are mapped (RFC 1112), so 32 groups share each MAC address
+// and switches can't tell them apart.
+// Addresses which aren't IPv4 multicast and duplicate groups are ignored,
+// collisions are sorted by MAC address and groups are kept in input order.
+// e.g., MulticastCollisions(224.1.1.1, 225.1.1.1, 239.1.1.2) -> [01:00:5e:01:01:01 [224.1.1.1 225.1.1.1]].
+func MulticastCollisions(groups []net.IP) []MulticastCollision {
+	byMAC := make(map[string]*MulticastCollision)
+	seen := make(map[string]bool)
+	for _, ip := range groups {
+		ip = ip.To4()
+		if ip == nil || !ip.IsMulticast() || seen[string(ip)] {
+			continue
+		}
+		seen[string(ip)] = true
+		mac := ipv4MulticastMAC(ip)
+		c, ok := byMAC[string(mac)]
+		if !ok {
+			c = &MulticastCollision{MAC: mac}
+			byMAC[string(mac)] = c
+		}
+		c.Groups = append(c.Groups, CopyIP(ip))
+	}
+	var collisions []MulticastCollision
+	for _, c := range byMAC {
+		if len(c.Groups) > 1 {
+			collisions = append(collisions, *c)
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return bytes.Compare(collisions[i].MAC, collisions[j].MAC) < 0
+	})
+	return collisions
+}
+
+// MulticastMAC returns the Ethernet MAC address of an IPv4 (RFC 1112) or IPv6 (RFC 2464) multicast group.
+// e.g., MulticastMAC(239.1.2.3) -> 01:00:5e:01:02:03, MulticastMAC(ff02::1:ff33:4455) -> 33:33:ff:33:44:55.
+func MulticastMAC(ip net.IP) (net.HardwareAddr, error) {
+	if !ip.IsMulticast() {
+		return nil, fmt.Errorf("ipcalc: %v is not a multicast address", ip)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ipv4MulticastMAC(ip4), nil
+	}
+	return net.HardwareAddr{0x33, 0x33, ip[12], ip[13], ip[14], ip[15]}, nil
+}
+
+// MulticastGroups returns the 32 IPv4 multicast groups mapping onto an 01:00:5e MAC address, in ascending order.
+// e.g., MulticastGroups(01:00:5e:01:02:03) -> [224.1.2.3 224.129.2.3 225.1.2.3 ... 239.129.2.3].
+func MulticastGroups(hw net.HardwareAddr) ([]net.IP, error) {
+	if len(hw) != 6 || hw[0] != 0x01 || hw[1] != 0x00 || hw[2] != 0x5e || hw[3]&0x80 != 0 {
+		return nil, fmt.Errorf("ipcalc: %v is not an IPv4 multicast MAC address", hw)
+	}
+	groups := make([]net.IP, 0, 32)
+	for first := byte(224); first <= 239; first++ {
+		for _, high := range []byte{0, 0x80} {
+			groups = append(groups, net.IP{first, hw[3] | high, hw[4], hw[5]})
+		}
+	}
+	return groups, nil
+}
+
+// ipv4MulticastMAC returns the Ethernet MAC address for a 4-byte IPv4 multicast group (RFC 1112).
+func ipv4MulticastMAC(ip net.IP) net.HardwareAddr {
+	return net.HardwareAddr{0x01, 0x00, 0x5e, ip[1] & 0x7f, ip[2], ip[3]}
+}
+
+// SolicitedNode returns the solicited-node multicast address of an IPv6 unicast address (RFC 4291),
+// or nil if it's not an IPv6 unicast address.
+// e.g., SolicitedNode(2001:db8::211:22ff:fe33:4455) -> ff02::1:ff33:4455.
+func SolicitedNode(ip net.IP) net.IP {
+	if len(ip) != net.IPv6len || ip.To4() != nil || ip.IsMulticast() || ip.IsUnspecified() {
+		return nil
+	}
+	return net.IP{0xff, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0xff, ip[13], ip[14], ip[15]}
+}
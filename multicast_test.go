@@ -0,0 +1,126 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMulticastCollisions(t *testing.T) {
+	tests := []struct {
+		groups []string
+		want   map[string][]string
+	}{
+		{[]string{"224.1.1.1", "239.1.1.2"}, nil},
+		{
+			[]string{"239.1.1.1", "224.1.1.1", "225.129.1.1", "239.129.1.1", "239.1.1.2", "224.1.1.1", "192.0.2.1", "ff02::1"},
+			map[string][]string{
+				"01:00:5e:01:01:01": {"239.1.1.1", "224.1.1.1", "225.129.1.1", "239.129.1.1"},
+			},
+		},
+		{
+			[]string{"232.0.0.1", "224.0.0.1", "::ffff:233.0.0.2", "238.128.0.2"},
+			map[string][]string{
+				"01:00:5e:00:00:01": {"232.0.0.1", "224.0.0.1"},
+				"01:00:5e:00:00:02": {"233.0.0.2", "238.128.0.2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var groups []net.IP
+		for _, ip := range tt.groups {
+			groups = append(groups, net.ParseIP(ip))
+		}
+		got := make(map[string][]string)
+		var macs []string
+		for _, c := range MulticastCollisions(groups) {
+			macs = append(macs, c.MAC.String())
+			for _, ip := range c.Groups {
+				got[c.MAC.String()] = append(got[c.MAC.String()], ip.String())
+			}
+		}
+		if len(tt.want) == 0 && len(got) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MulticastCollisions(%v) = %v, want %v", tt.groups, got, tt.want)
+		}
+		for i := 1; i < len(macs); i++ {
+			if macs[i-1] >= macs[i] {
+				t.Errorf("MulticastCollisions(%v) MACs not sorted: %v", tt.groups, macs)
+			}
+		}
+	}
+}
+
+func TestSolicitedNode(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		want string
+	}{
+		{net.ParseIP("2001:db8::211:22ff:fe33:4455"), "ff02::1:ff33:4455"},
+		{net.ParseIP("fe80::1"), "ff02::1:ff00:1"},
+		{net.ParseIP("::1"), "ff02::1:ff00:1"},
+		{net.ParseIP("ff02::1"), ""},
+		{net.ParseIP("::"), ""},
+		{net.ParseIP("192.0.2.1"), ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		got := SolicitedNode(tt.ip)
+		if tt.want == "" && got != nil || tt.want != "" && !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("SolicitedNode(%v) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestMulticastMAC(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		want string
+	}{
+		{net.ParseIP("224.0.0.1"), "01:00:5e:00:00:01"},
+		{net.ParseIP("239.129.2.3"), "01:00:5e:01:02:03"},
+		{net.IP{239, 255, 255, 250}, "01:00:5e:7f:ff:fa"},
+		{net.ParseIP("ff02::1"), "33:33:00:00:00:01"},
+		{net.ParseIP("ff02::1:ff33:4455"), "33:33:ff:33:44:55"},
+		{net.ParseIP("192.0.2.1"), ""},
+		{net.ParseIP("2001:db8::1"), ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		got, err := MulticastMAC(tt.ip)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("MulticastMAC(%v) = %v, want error", tt.ip, got)
+			}
+		} else if err != nil || got.String() != tt.want {
+			t.Errorf("MulticastMAC(%v) = %v, %v, want %v", tt.ip, got, err, tt.want)
+		}
+	}
+}
+
+func TestMulticastGroups(t *testing.T) {
+	hw, _ := net.ParseMAC("01:00:5e:01:02:03")
+	groups, err := MulticastGroups(hw)
+	if err != nil || len(groups) != 32 {
+		t.Fatalf("MulticastGroups(%v) = %v, %v, want 32 groups", hw, groups, err)
+	}
+	if first, last := groups[0].String(), groups[31].String(); first != "224.1.2.3" || last != "239.129.2.3" {
+		t.Errorf("MulticastGroups(%v) = [%v ... %v], want [224.1.2.3 ... 239.129.2.3]", hw, first, last)
+	}
+	for i, g := range groups {
+		if mac, err := MulticastMAC(g); err != nil || mac.String() != hw.String() {
+			t.Errorf("MulticastMAC(%v) = %v, %v, want %v", g, mac, err, hw)
+		}
+		if i > 0 && Compare(groups[i-1], g) >= 0 {
+			t.Errorf("MulticastGroups(%v) not sorted at %v", hw, g)
+		}
+	}
+	for _, s := range []string{"01:00:5e:81:02:03", "33:33:00:00:00:01", "00:11:22:33:44:55"} {
+		hw, _ := net.ParseMAC(s)
+		if groups, err := MulticastGroups(hw); err == nil {
+			t.Errorf("MulticastGroups(%v) = %v, want error", hw, groups)
+		}
+	}
+}
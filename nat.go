@@ -0,0 +1,63 @@
+package ipcalc
+
+import (
+	"fmt"
+	"net"
+)
+
+// MaxStaticTableBits is the maximum number of host bits for networks passed to StaticTable.
+const MaxStaticTableBits = 24
+
+// StaticMapping is a single 1:1 NAT translation.
+type StaticMapping struct {
+	Inside  net.IP
+	Outside net.IP
+}
+
+// MapStatic translates an address between two equal-sized networks, preserving its host bits.
+// e.g., MapStatic(10.0.0.0/24, 192.0.2.0/24, 10.0.0.5) -> 192.0.2.5.
+func MapStatic(insideNet, outsideNet net.IPNet, ip net.IP) (net.IP, error) {
+	if err := checkStatic(insideNet, outsideNet); err != nil {
+		return nil, err
+	}
+	if !insideNet.Contains(ip) {
+		return nil, fmt.Errorf("ipcalc: %v not in %v", ip, &insideNet)
+	}
+	return mapStatic(outsideNet, ip), nil
+}
+
+// StaticTable returns the 1:1 NAT translations for every address in insideNet, in ascending order.
+// Networks with more than MaxStaticTableBits host bits are rejected.
+// e.g., StaticTable(10.0.0.0/31, 192.0.2.0/31) -> [10.0.0.0 -> 192.0.2.0, 10.0.0.1 -> 192.0.2.1].
+func StaticTable(insideNet, outsideNet net.IPNet) ([]StaticMapping, error) {
+	if err := checkStatic(insideNet, outsideNet); err != nil {
+		return nil, err
+	}
+	ones, bits := insideNet.Mask.Size()
+	if bits-ones > MaxStaticTableBits {
+		return nil, fmt.Errorf("ipcalc: %v has more than %v host bits", &insideNet, MaxStaticTableBits)
+	}
+	table := make([]StaticMapping, 0, 1<<uint(bits-ones))
+	ip := IP(insideNet.IP.Mask(insideNet.Mask))
+	for i := 0; i < cap(table); i++ {
+		table = append(table, StaticMapping{
+			Inside:  ip,
+			Outside: mapStatic(outsideNet, ip),
+		})
+		ip = NextIP(ip)
+	}
+	return table, nil
+}
+
+func checkStatic(insideNet, outsideNet net.IPNet) error {
+	inOnes, inBits := insideNet.Mask.Size()
+	outOnes, outBits := outsideNet.Mask.Size()
+	if inBits == 0 || inOnes != outOnes || inBits != outBits || inBits != 8*IPSize(insideNet.IP) || outBits != 8*IPSize(outsideNet.IP) {
+		return fmt.Errorf("ipcalc: %v and %v are not equal-sized networks", &insideNet, &outsideNet)
+	}
+	return nil
+}
+
+func mapStatic(outsideNet net.IPNet, ip net.IP) net.IP {
+	return Merge(outsideNet.IP, ip, Complement(outsideNet.Mask))
+}
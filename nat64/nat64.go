@@ -0,0 +1,94 @@
+// Package nat64 embeds IPv4 addresses in IPv6 addresses and extracts them back, following RFC 6052.
+//
+// The IPv4 address follows the prefix, skipping bits 64 to 71 which must be zero, e.g., for a /40 prefix:
+//
+//	| prefix (40) | v4 (24) | u (8) | v4 (8) | suffix (48) |
+package nat64
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hazaelsan/ipcalc/classify"
+)
+
+// ErrNonGlobal is returned when embedding a non-global IPv4 address in the WellKnownPrefix (RFC 6052, section 3.1).
+var ErrNonGlobal = errors.New("nat64: non-global IPv4 address with the well-known prefix")
+
+// WellKnownPrefix is the RFC 6052 Well-Known Prefix, 64:ff9b::/96.
+var WellKnownPrefix = net.IPNet{
+	IP:   net.ParseIP("64:ff9b::"),
+	Mask: net.CIDRMask(96, 8*net.IPv6len),
+}
+
+// PrefixLengths are the prefix lengths allowed by RFC 6052.
+var PrefixLengths = []int{32, 40, 48, 56, 64, 96}
+
+// uOctet is the index of the byte holding bits 64 to 71, which must be zero.
+const uOctet = 8
+
+// Embed returns the IPv4-embedded IPv6 address for an IPv4 address with a NAT64 prefix.
+// e.g., Embed(2001:db8:100::/40, 192.0.2.33) -> 2001:db8:1c0:2:21::.
+func Embed(prefix net.IPNet, v4 net.IP) (net.IP, error) {
+	ones, err := checkPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	ip4 := v4.To4()
+	if ip4 == nil {
+		return nil, fmt.Errorf("nat64: %v is not an IPv4 address", v4)
+	}
+	if ones == 96 && prefix.IP.Equal(WellKnownPrefix.IP) && classify.IsBogon(ip4) {
+		return nil, fmt.Errorf("%w: %v", ErrNonGlobal, ip4)
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, prefix.IP.To16().Mask(prefix.Mask))
+	pos := ones / 8
+	for _, b := range ip4 {
+		if pos == uOctet {
+			pos++
+		}
+		ip[pos] = b
+		pos++
+	}
+	return ip, nil
+}
+
+// Extract returns the IPv4 address embedded in an IPv6 address with a NAT64 prefix.
+// e.g., Extract(2001:db8:100::/40, 2001:db8:1c0:2:21::) -> 192.0.2.33.
+func Extract(prefix net.IPNet, v6 net.IP) (net.IP, error) {
+	ones, err := checkPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(v6) != net.IPv6len || !prefix.Contains(v6) {
+		return nil, fmt.Errorf("nat64: %v not in %v", v6, &prefix)
+	}
+	if ones < 96 && v6[uOctet] != 0 {
+		return nil, fmt.Errorf("nat64: %v has non-zero bits 64 to 71", v6)
+	}
+	ip4 := make(net.IP, net.IPv4len)
+	pos := ones / 8
+	for i := range ip4 {
+		if pos == uOctet {
+			pos++
+		}
+		ip4[i] = v6[pos]
+		pos++
+	}
+	return ip4, nil
+}
+
+// checkPrefix returns the length of a NAT64 prefix, or an error if it's not allowed by RFC 6052.
+func checkPrefix(prefix net.IPNet) (int, error) {
+	ones, bits := prefix.Mask.Size()
+	if bits == 8*net.IPv6len && len(prefix.IP) == net.IPv6len && prefix.IP.To4() == nil {
+		for _, l := range PrefixLengths {
+			if ones == l {
+				return ones, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("nat64: invalid prefix %v", &prefix)
+}
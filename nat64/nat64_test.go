@@ -0,0 +1,76 @@
+package nat64
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+// The examples from RFC 6052, section 2.4.
+func TestEmbedExtract(t *testing.T) {
+	tests := []struct {
+		prefix string
+		v6     string
+	}{
+		{"2001:db8::/32", "2001:db8:c000:221::"},
+		{"2001:db8:100::/40", "2001:db8:1c0:2:21::"},
+		{"2001:db8:122::/48", "2001:db8:122:c000:2:2100::"},
+		{"2001:db8:122:300::/56", "2001:db8:122:3c0:0:221::"},
+		{"2001:db8:122:344::/64", "2001:db8:122:344:c0:2:2100:0"},
+		{"2001:db8:122:344::/96", "2001:db8:122:344::192.0.2.33"},
+	}
+	v4 := net.ParseIP("192.0.2.33")
+	for _, tt := range tests {
+		prefix := mustCIDR(t, tt.prefix)
+		got, err := Embed(prefix, v4)
+		if err != nil || !got.Equal(net.ParseIP(tt.v6)) {
+			t.Errorf("Embed(%v, %v) = %v, %v, want %v", tt.prefix, v4, got, err, tt.v6)
+		}
+		back, err := Extract(prefix, net.ParseIP(tt.v6))
+		if err != nil || !back.Equal(v4) {
+			t.Errorf("Extract(%v, %v) = %v, %v, want %v", tt.prefix, tt.v6, back, err, v4)
+		}
+	}
+}
+
+func TestWellKnownPrefix(t *testing.T) {
+	v4 := net.ParseIP("8.8.8.8")
+	got, err := Embed(WellKnownPrefix, v4)
+	if want := net.ParseIP("64:ff9b::808:808"); err != nil || !got.Equal(want) {
+		t.Errorf("Embed(%v, %v) = %v, %v, want %v", &WellKnownPrefix, v4, got, err, want)
+	}
+	if back, err := Extract(WellKnownPrefix, got); err != nil || !back.Equal(v4) {
+		t.Errorf("Extract(%v, %v) = %v, %v, want %v", &WellKnownPrefix, got, back, err, v4)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := Embed(WellKnownPrefix, net.ParseIP("10.0.0.1")); !errors.Is(err, ErrNonGlobal) {
+		t.Errorf("Embed(%v, 10.0.0.1) error = %v, want %v", &WellKnownPrefix, err, ErrNonGlobal)
+	}
+	if _, err := Embed(mustCIDR(t, "2001:db8::/96"), net.ParseIP("10.0.0.1")); err != nil {
+		t.Errorf("Embed(2001:db8::/96, 10.0.0.1) error = %v", err)
+	}
+	for _, p := range []string{"2001:db8::/33", "2001:db8::/128", "192.0.2.0/24"} {
+		if _, err := Embed(mustCIDR(t, p), net.ParseIP("192.0.2.1")); err == nil {
+			t.Errorf("Embed(%v) error = nil, want error", p)
+		}
+	}
+	if _, err := Embed(mustCIDR(t, "2001:db8::/32"), net.ParseIP("2001:db8::1")); err == nil {
+		t.Errorf("Embed(2001:db8::/32, 2001:db8::1) error = nil, want error")
+	}
+	for _, v6 := range []string{"2001:db9::1", "2001:db8:c000:221:100::"} {
+		if _, err := Extract(mustCIDR(t, "2001:db8::/32"), net.ParseIP(v6)); err == nil {
+			t.Errorf("Extract(2001:db8::/32, %v) error = nil, want error", v6)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func TestMapStatic(t *testing.T) {
+	tests := []struct {
+		inside  string
+		outside string
+		ip      string
+		want    string
+	}{
+		{"10.0.0.0/24", "192.0.2.0/24", "10.0.0.5", "192.0.2.5"},
+		{"10.0.0.0/24", "192.0.2.0/24", "::ffff:10.0.0.255", "192.0.2.255"},
+		{"10.1.0.0/16", "198.51.0.0/16", "10.1.2.3", "198.51.2.3"},
+		{"fd00::/64", "2001:db8:1::/64", "fd00::dead:beef", "2001:db8:1::dead:beef"},
+		{"10.0.0.0/24", "192.0.2.0/24", "10.0.1.5", ""},
+		{"10.0.0.0/24", "192.0.2.0/25", "10.0.0.5", ""},
+		{"10.0.0.0/24", "2001:db8::/120", "10.0.0.5", ""},
+	}
+	for _, tt := range tests {
+		got, err := MapStatic(mustCIDR(t, tt.inside), mustCIDR(t, tt.outside), net.ParseIP(tt.ip))
+		if err != nil {
+			if tt.want != "" {
+				t.Errorf("MapStatic(%v, %v, %v) error = %v", tt.inside, tt.outside, tt.ip, err)
+			}
+			continue
+		}
+		if tt.want == "" {
+			t.Errorf("MapStatic(%v, %v, %v) error = nil", tt.inside, tt.outside, tt.ip)
+		} else if !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("MapStatic(%v, %v, %v) = %v, want %v", tt.inside, tt.outside, tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestStaticTable(t *testing.T) {
+	table, err := StaticTable(mustCIDR(t, "10.0.0.0/30"), mustCIDR(t, "192.0.2.4/30"))
+	if err != nil {
+		t.Fatalf("StaticTable() error = %v", err)
+	}
+	want := []StaticMapping{
+		{net.ParseIP("10.0.0.0"), net.ParseIP("192.0.2.4")},
+		{net.ParseIP("10.0.0.1"), net.ParseIP("192.0.2.5")},
+		{net.ParseIP("10.0.0.2"), net.ParseIP("192.0.2.6")},
+		{net.ParseIP("10.0.0.3"), net.ParseIP("192.0.2.7")},
+	}
+	if len(table) != len(want) {
+		t.Fatalf("StaticTable() = %v, want %v", table, want)
+	}
+	for i, m := range table {
+		if !m.Inside.Equal(want[i].Inside) || !m.Outside.Equal(want[i].Outside) {
+			t.Errorf("StaticTable()[%v] = %v, want %v", i, m, want[i])
+		}
+	}
+	if _, err := StaticTable(mustCIDR(t, "fd00::/64"), mustCIDR(t, "2001:db8::/64")); err == nil {
+		t.Errorf("StaticTable(/64) error = nil")
+	}
+}
@@ -0,0 +1,81 @@
+// Package netipcalc provides the ipcalc IP arithmetic utilities for net/netip types.
+//
+// Functions mirror their ipcalc counterparts but take and return netip.Addr and netip.Prefix values,
+// avoiding round-trips through net.IP byte slices.
+// As in ipcalc, next/prev functions are subject to wrapping,
+// e.g., NextAddr(255.255.255.255) -> 0.0.0.0.
+package netipcalc
+
+import (
+	"net/netip"
+)
+
+// NextAddr returns the next IP address.
+// e.g., NextAddr(192.168.0.0) -> 192.168.0.1.
+func NextAddr(a netip.Addr) netip.Addr {
+	if n := a.Next(); n.IsValid() || !a.IsValid() {
+		return n
+	}
+	return first(a)
+}
+
+// PrevAddr returns the previous IP address.
+// e.g., PrevAddr(192.168.0.1) -> 192.168.0.0.
+func PrevAddr(a netip.Addr) netip.Addr {
+	if p := a.Prev(); p.IsValid() || !a.IsValid() {
+		return p
+	}
+	return last(a)
+}
+
+// Broadcast returns the last IP address in a Prefix.
+// e.g., Broadcast(192.168.0.0/24) -> 192.168.0.255.
+func Broadcast(p netip.Prefix) netip.Addr {
+	if !p.IsValid() {
+		return netip.Addr{}
+	}
+	a := p.Addr()
+	b := a.As16()
+	off := 16 - a.BitLen()/8
+	for i := p.Bits(); i < a.BitLen(); i++ {
+		b[off+i/8] |= 0x80 >> uint(i%8)
+	}
+	if a.Is4() {
+		return netip.AddrFrom4([4]byte(b[12:])).WithZone(a.Zone())
+	}
+	return netip.AddrFrom16(b).WithZone(a.Zone())
+}
+
+// NextPrefix returns the next Prefix of the same length.
+// e.g., NextPrefix(192.168.0.0/24) -> 192.168.1.0/24.
+func NextPrefix(p netip.Prefix) netip.Prefix {
+	return netip.PrefixFrom(NextAddr(Broadcast(p.Masked())), p.Bits())
+}
+
+// PrevPrefix returns the previous Prefix of the same length.
+// e.g., PrevPrefix(192.168.1.0/24) -> 192.168.0.0/24.
+func PrevPrefix(p netip.Prefix) netip.Prefix {
+	prev, _ := PrevAddr(p.Masked().Addr()).Prefix(p.Bits())
+	return prev
+}
+
+// Contains returns whether the first Prefix wholly contains the second one.
+func Contains(a, b netip.Prefix) bool {
+	return a.IsValid() && b.IsValid() && a.Bits() <= b.Bits() && a.Contains(b.Addr())
+}
+
+// first returns the lowest address of the same family as a.
+func first(a netip.Addr) netip.Addr {
+	if a.Is4() {
+		return netip.IPv4Unspecified()
+	}
+	return netip.IPv6Unspecified()
+}
+
+// last returns the highest address of the same family as a.
+func last(a netip.Addr) netip.Addr {
+	if a.Is4() {
+		return netip.AddrFrom4([4]byte{0xff, 0xff, 0xff, 0xff})
+	}
+	return netip.AddrFrom16([16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+}
@@ -0,0 +1,105 @@
+package netipcalc
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestNextAddr(t *testing.T) {
+	tests := map[string]string{
+		"0.0.0.0":             "0.0.0.1",
+		"192.0.2.255":         "192.0.3.0",
+		"255.255.255.255":     "0.0.0.0",
+		"::ffff:192.0.2.1":    "::ffff:192.0.2.2",
+		"2001:db8::ffff:ffff": "2001:db8::1:0:0",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff": "::",
+	}
+	for a, want := range tests {
+		if got := NextAddr(netip.MustParseAddr(a)); got != netip.MustParseAddr(want) {
+			t.Errorf("NextAddr(%v) = %v, want %v", a, got, want)
+		}
+	}
+	if got := NextAddr(netip.Addr{}); got.IsValid() {
+		t.Errorf("NextAddr(invalid) = %v, want invalid", got)
+	}
+}
+
+func TestPrevAddr(t *testing.T) {
+	tests := map[string]string{
+		"0.0.0.1":         "0.0.0.0",
+		"192.0.2.0":       "192.0.1.255",
+		"0.0.0.0":         "255.255.255.255",
+		"2001:db8::1:0:0": "2001:db8::ffff:ffff",
+		"::":              "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+	}
+	for a, want := range tests {
+		if got := PrevAddr(netip.MustParseAddr(a)); got != netip.MustParseAddr(want) {
+			t.Errorf("PrevAddr(%v) = %v, want %v", a, got, want)
+		}
+	}
+	if got := PrevAddr(netip.Addr{}); got.IsValid() {
+		t.Errorf("PrevAddr(invalid) = %v, want invalid", got)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.0/24":   "192.0.2.255",
+		"192.0.2.17/28":  "192.0.2.31",
+		"192.0.2.1/32":   "192.0.2.1",
+		"0.0.0.0/0":      "255.255.255.255",
+		"2001:db8::/64":  "2001:db8::ffff:ffff:ffff:ffff",
+		"2001:db8::/127": "2001:db8::1",
+		"::ffff:0:0/96":  "::ffff:255.255.255.255",
+	}
+	for p, want := range tests {
+		if got := Broadcast(netip.MustParsePrefix(p)); got != netip.MustParseAddr(want) {
+			t.Errorf("Broadcast(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestNextPrevPrefix(t *testing.T) {
+	tests := []struct {
+		p    string
+		next string
+		prev string
+	}{
+		{"192.0.2.0/24", "192.0.3.0/24", "192.0.1.0/24"},
+		{"192.0.2.130/25", "192.0.3.0/25", "192.0.2.0/25"},
+		{"255.255.255.0/24", "0.0.0.0/24", "255.255.254.0/24"},
+		{"0.0.0.0/24", "0.0.1.0/24", "255.255.255.0/24"},
+		{"2001:db8::/64", "2001:db8:0:1::/64", "2001:db7:ffff:ffff::/64"},
+	}
+	for _, tt := range tests {
+		p := netip.MustParsePrefix(tt.p)
+		if got := NextPrefix(p); got != netip.MustParsePrefix(tt.next) {
+			t.Errorf("NextPrefix(%v) = %v, want %v", tt.p, got, tt.next)
+		}
+		if got := PrevPrefix(p); got != netip.MustParsePrefix(tt.prev) {
+			t.Errorf("PrevPrefix(%v) = %v, want %v", tt.p, got, tt.prev)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"192.0.2.0/24", "192.0.2.128/25", true},
+		{"192.0.2.0/24", "192.0.2.0/24", true},
+		{"192.0.2.0/24", "192.0.2.0/23", false},
+		{"192.0.2.0/24", "192.0.3.0/25", false},
+		{"2001:db8::/32", "2001:db8:1::/48", true},
+		{"::/0", "192.0.2.0/24", false},
+	}
+	for _, tt := range tests {
+		if got := Contains(netip.MustParsePrefix(tt.a), netip.MustParsePrefix(tt.b)); got != tt.want {
+			t.Errorf("Contains(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if Contains(netip.Prefix{}, netip.Prefix{}) {
+		t.Errorf("Contains(invalid, invalid) = true")
+	}
+}
@@ -0,0 +1,54 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+)
+
+// NibbleAdvice describes nibble-aligned alternatives for an IPv6 network whose prefix length isn't a multiple of 4.
+type NibbleAdvice struct {
+	// Net is the original network.
+	Net net.IPNet
+	// Widened is the nearest nibble-aligned network containing Net.
+	Widened net.IPNet
+	// Extra is the number of addresses Widened covers beyond Net.
+	Extra *big.Int
+	// Conflicts are the other networks in the plan overlapping Widened.
+	Conflicts []net.IPNet
+	// SplitLen and SplitCount describe the nibble-aligned networks exactly covering Net,
+	// e.g., a /46 is exactly covered by 4 /48 networks.
+	SplitLen   int
+	SplitCount int
+}
+
+// AdviseNibble audits an IPv6 addressing plan, suggesting nibble-aligned alternatives for each network
+// whose prefix length isn't a multiple of 4, as those can't be cleanly delegated in ip6.arpa.
+// IPv4 networks and nibble-aligned networks are skipped.
+// e.g., AdviseNibble(2001:db8::/46) -> Widened 2001:db8::/44, Extra 3*2^82, SplitLen 48, SplitCount 4.
+func AdviseNibble(nets []net.IPNet) []NibbleAdvice {
+	var advice []NibbleAdvice
+	for i, n := range nets {
+		ones, bits := n.Mask.Size()
+		if bits != 8*net.IPv6len || IPVersion(n.IP) != 6 || ones%4 == 0 {
+			continue
+		}
+		wideLen := ones - ones%4
+		mask := net.CIDRMask(wideLen, bits)
+		a := NibbleAdvice{
+			Net:        n,
+			Widened:    net.IPNet{IP: n.IP.Mask(mask), Mask: mask},
+			Extra:      new(big.Int),
+			SplitLen:   wideLen + 4,
+			SplitCount: 1 << uint(wideLen+4-ones),
+		}
+		a.Extra.Lsh(big.NewInt(1), uint(bits-wideLen))
+		a.Extra.Sub(a.Extra, new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)))
+		for j, m := range nets {
+			if i != j && (a.Widened.Contains(m.IP) || m.Contains(a.Widened.IP)) {
+				a.Conflicts = append(a.Conflicts, m)
+			}
+		}
+		advice = append(advice, a)
+	}
+	return advice
+}
@@ -0,0 +1,38 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAdviseNibble(t *testing.T) {
+	var nets []net.IPNet
+	for _, s := range []string{"2001:db8::/46", "2001:db8:4::/48", "2001:db8:10::/47", "2001:db8:100::/63", "192.0.2.0/23", "2001:db8:200::/60"} {
+		nets = append(nets, mustCIDR(t, s))
+	}
+	want := []struct {
+		net        string
+		widened    string
+		extra      string
+		conflicts  int
+		splitLen   int
+		splitCount int
+	}{
+		{"2001:db8::/46", "2001:db8::/44", "14507109835375550096474112", 1, 48, 4},
+		{"2001:db8:10::/47", "2001:db8:10::/44", "16924961474604808445886464", 0, 48, 2},
+		{"2001:db8:100::/63", "2001:db8:100::/60", "258254417031933722624", 0, 64, 2},
+	}
+	got := AdviseNibble(nets)
+	if len(got) != len(want) {
+		t.Fatalf("AdviseNibble() = %v, want %v entries", got, len(want))
+	}
+	for i, a := range got {
+		w := want[i]
+		if a.Net.String() != w.net || a.Widened.String() != w.widened || a.Extra.String() != w.extra || len(a.Conflicts) != w.conflicts || a.SplitLen != w.splitLen || a.SplitCount != w.splitCount {
+			t.Errorf("AdviseNibble()[%v] = {%v %v %v %v %v %v}, want %+v", i, a.Net.String(), a.Widened.String(), a.Extra, a.Conflicts, a.SplitLen, a.SplitCount, w)
+		}
+	}
+	if got := got[0].Conflicts[0].String(); got != "2001:db8:4::/48" {
+		t.Errorf("AdviseNibble()[0].Conflicts = %v, want [2001:db8:4::/48]", got)
+	}
+}
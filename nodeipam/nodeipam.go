@@ -0,0 +1,179 @@
+// Package nodeipam carves a cluster CIDR into fixed-size per-node subnets,
+// mirroring Kubernetes node IPAM (podCIDR) semantics.
+//
+// Each node is assigned the lowest free subnet, assignments are stable until the node is released,
+// at which point its subnet becomes available to new nodes.
+// An Allocator's assignments can be exported with State and restored with Restore.
+// Subnets are managed by a first-fit allocator.Pool, with node names as allocation names.
+package nodeipam
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hazaelsan/ipcalc"
+	"github.com/hazaelsan/ipcalc/allocator"
+)
+
+// MaxNodeBits is the maximum difference between the cluster and node prefix lengths, i.e., at most 65536 nodes.
+const MaxNodeBits = 16
+
+// ErrFull is returned when every node subnet is already assigned.
+var ErrFull = errors.New("nodeipam: no free node subnets")
+
+// Allocator assigns per-node subnets from a cluster CIDR, it's not safe for concurrent use.
+type Allocator struct {
+	cluster net.IPNet
+	nodeLen int
+	pool    *allocator.Pool
+}
+
+// State is the exported form of an Allocator, suitable for JSON encoding.
+type State struct {
+	Cluster      string            `json:"cluster"`
+	NodeMaskSize int               `json:"nodeMaskSize"`
+	Nodes        map[string]string `json:"nodes"`
+}
+
+// New returns an Allocator splitting a cluster CIDR into subnets with a nodeMaskSize prefix length.
+// e.g., New(10.244.0.0/16, 24) assigns 10.244.0.0/24, 10.244.1.0/24, ... up to 256 nodes.
+func New(cluster net.IPNet, nodeMaskSize int) (*Allocator, error) {
+	norm := ipcalc.Normalize(cluster)
+	ones, bits := norm.Mask.Size()
+	if norm.IP == nil || bits == 0 {
+		return nil, fmt.Errorf("nodeipam: invalid cluster CIDR %v", &cluster)
+	}
+	if nodeMaskSize < ones || nodeMaskSize > bits || nodeMaskSize-ones > MaxNodeBits {
+		return nil, fmt.Errorf("nodeipam: node mask size %v out of range [%v, %v]", nodeMaskSize, ones, min(bits, ones+MaxNodeBits))
+	}
+	pool, err := allocator.New([]net.IPNet{norm}, allocator.WithStrategy(allocator.FirstFit))
+	if err != nil {
+		return nil, err
+	}
+	return &Allocator{cluster: norm, nodeLen: nodeMaskSize, pool: pool}, nil
+}
+
+// Cluster returns the cluster CIDR.
+func (a *Allocator) Cluster() net.IPNet {
+	return a.cluster
+}
+
+// Capacity returns the total number of node subnets.
+func (a *Allocator) Capacity() int {
+	ones, _ := a.cluster.Mask.Size()
+	return 1 << uint(a.nodeLen-ones)
+}
+
+// Len returns the number of assigned node subnets.
+func (a *Allocator) Len() int {
+	return a.pool.Len()
+}
+
+// Get returns the subnet assigned to a node, if any.
+func (a *Allocator) Get(node string) (net.IPNet, bool) {
+	n, ok := a.pool.Find(node)
+	return n.Prefix, ok
+}
+
+// Allocate assigns the lowest free subnet to a node.
+// Allocating an already assigned node returns its existing subnet.
+func (a *Allocator) Allocate(node string) (net.IPNet, error) {
+	if n, ok := a.pool.Find(node); ok {
+		return n.Prefix, nil
+	}
+	n, err := a.pool.AllocateNamed(a.nodeLen, node, nil)
+	if errors.Is(err, allocator.ErrExhausted) {
+		return net.IPNet{}, ErrFull
+	}
+	return n.Prefix, err
+}
+
+// Occupy assigns a specific subnet to a node, e.g., one recorded in the node's spec before a restart.
+// It returns an error if the subnet isn't a node subnet of the cluster, or is assigned to another node.
+func (a *Allocator) Occupy(node string, subnet net.IPNet) error {
+	norm, err := a.nodeSubnet(subnet)
+	if err != nil {
+		return err
+	}
+	if cur, ok := a.pool.Find(node); ok {
+		if ipcalc.CompareNet(cur.Prefix, norm) == 0 {
+			return nil
+		}
+		return fmt.Errorf("nodeipam: node %q already assigned %v", node, &cur.Prefix)
+	}
+	if err := a.pool.OccupyNamed(norm, node, nil); err != nil {
+		if errors.Is(err, allocator.ErrOverlap) {
+			return fmt.Errorf("nodeipam: %v already assigned", &subnet)
+		}
+		return err
+	}
+	return nil
+}
+
+// Release frees the subnet assigned to a node, returning whether the node had one.
+func (a *Allocator) Release(node string) bool {
+	n, ok := a.pool.Find(node)
+	if !ok {
+		return false
+	}
+	// The subnet was just found, so releasing it can't fail.
+	a.pool.Release(n.Prefix)
+	return true
+}
+
+// Nodes returns the names of all nodes with an assigned subnet, sorted by subnet.
+func (a *Allocator) Nodes() []string {
+	nodes := make([]string, 0, a.pool.Len())
+	for _, n := range a.pool.List() {
+		nodes = append(nodes, n.Name)
+	}
+	return nodes
+}
+
+// State exports the Allocator configuration and assignments.
+func (a *Allocator) State() State {
+	s := State{
+		Cluster:      a.cluster.String(),
+		NodeMaskSize: a.nodeLen,
+		Nodes:        make(map[string]string, a.pool.Len()),
+	}
+	for _, n := range a.pool.List() {
+		s.Nodes[n.Name] = n.Prefix.String()
+	}
+	return s
+}
+
+// Restore returns an Allocator from an exported State.
+func Restore(s State) (*Allocator, error) {
+	_, cluster, err := net.ParseCIDR(s.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	a, err := New(*cluster, s.NodeMaskSize)
+	if err != nil {
+		return nil, err
+	}
+	for node, subnet := range s.Nodes {
+		_, n, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.Occupy(node, *n); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// nodeSubnet returns the normalized form of a node subnet of the cluster,
+// or an error if it has the wrong prefix length, host bits set or is outside the cluster.
+func (a *Allocator) nodeSubnet(subnet net.IPNet) (net.IPNet, error) {
+	norm := ipcalc.Normalize(subnet)
+	ones, bits := norm.Mask.Size()
+	_, clusterBits := a.cluster.Mask.Size()
+	if norm.IP == nil || ones != a.nodeLen || bits != clusterBits || !norm.IP.Equal(ipcalc.IP(subnet.IP)) || !ipcalc.Contains(a.cluster, norm) {
+		return net.IPNet{}, fmt.Errorf("nodeipam: %v is not a /%v subnet of %v", &subnet, a.nodeLen, &a.cluster)
+	}
+	return norm, nil
+}
@@ -0,0 +1,150 @@
+package nodeipam
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		cluster string
+		size    int
+		ok      bool
+	}{
+		{"10.244.0.0/16", 24, true},
+		{"10.244.0.0/16", 16, true},
+		{"10.244.0.0/16", 32, true},
+		{"10.244.0.0/14", 31, false},
+		{"10.244.0.0/16", 15, false},
+		{"10.244.0.0/16", 33, false},
+		{"fd00:10:244::/56", 64, true},
+		{"fd00:10:244::/48", 80, false},
+		{"::ffff:10.244.0.0/112", 24, true},
+	}
+	for _, tt := range tests {
+		if _, err := New(mustCIDR(t, tt.cluster), tt.size); (err == nil) != tt.ok {
+			t.Errorf("New(%v, %v) error = %v", tt.cluster, tt.size, err)
+		}
+	}
+}
+
+func TestAllocateRelease(t *testing.T) {
+	a, err := New(mustCIDR(t, "10.244.0.0/22"), 24)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	allocate := func(node, want string) {
+		t.Helper()
+		got, err := a.Allocate(node)
+		if err != nil {
+			t.Fatalf("Allocate(%v) error = %v", node, err)
+		}
+		if got.String() != want {
+			t.Errorf("Allocate(%v) = %v, want %v", node, got.String(), want)
+		}
+	}
+	allocate("a", "10.244.0.0/24")
+	allocate("b", "10.244.1.0/24")
+	allocate("c", "10.244.2.0/24")
+	allocate("a", "10.244.0.0/24")
+	if !a.Release("b") {
+		t.Errorf("Release(b) = false")
+	}
+	if a.Release("b") {
+		t.Errorf("Release(b) = true after release")
+	}
+	allocate("d", "10.244.1.0/24")
+	allocate("e", "10.244.3.0/24")
+	if _, err := a.Allocate("f"); err != ErrFull {
+		t.Errorf("Allocate(f) error = %v, want ErrFull", err)
+	}
+	if got, want := a.Nodes(), []string{"a", "d", "c", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Nodes() = %v, want %v", got, want)
+	}
+	if got, ok := a.Get("c"); !ok || got.String() != "10.244.2.0/24" {
+		t.Errorf("Get(c) = %v, %v, want 10.244.2.0/24", got.String(), ok)
+	}
+	if _, ok := a.Get("b"); ok {
+		t.Errorf("Get(b) = true after release")
+	}
+	if a.Len() != 4 || a.Capacity() != 4 {
+		t.Errorf("Len(), Capacity() = %v, %v, want 4, 4", a.Len(), a.Capacity())
+	}
+}
+
+func TestOccupy(t *testing.T) {
+	a, err := New(mustCIDR(t, "fd00:10:244::/62"), 64)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := a.Occupy("a", mustCIDR(t, "fd00:10:244:2::/64")); err != nil {
+		t.Errorf("Occupy(a) error = %v", err)
+	}
+	if err := a.Occupy("a", mustCIDR(t, "fd00:10:244:2::/64")); err != nil {
+		t.Errorf("Occupy(a) again error = %v", err)
+	}
+	tests := map[string]string{
+		"a": "fd00:10:244:3::/64",
+		"b": "fd00:10:244:2::/64",
+		"c": "fd00:10:244:4::/64",
+		"d": "fd00:10:244::/63",
+		"e": "10.0.0.0/24",
+	}
+	for node, subnet := range tests {
+		if err := a.Occupy(node, mustCIDR(t, subnet)); err == nil {
+			t.Errorf("Occupy(%v, %v) error = nil", node, subnet)
+		}
+	}
+	if err := a.Occupy("f", net.IPNet{IP: net.ParseIP("fd00:10:244:1::1"), Mask: net.CIDRMask(64, 128)}); err == nil {
+		t.Errorf("Occupy(f, fd00:10:244:1::1/64) error = nil")
+	}
+	if got, _ := a.Allocate("g"); got.String() != "fd00:10:244::/64" {
+		t.Errorf("Allocate(g) = %v, want fd00:10:244::/64", got.String())
+	}
+}
+
+func TestStateRestore(t *testing.T) {
+	a, err := New(mustCIDR(t, "10.244.0.0/16"), 24)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, node := range []string{"a", "b", "c"} {
+		if _, err := a.Allocate(node); err != nil {
+			t.Fatalf("Allocate(%v) error = %v", node, err)
+		}
+	}
+	a.Release("b")
+	b, err := json.Marshal(a.State())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	r, err := Restore(s)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if !reflect.DeepEqual(r.State(), a.State()) {
+		t.Errorf("Restore().State() = %v, want %v", r.State(), a.State())
+	}
+	if got, _ := r.Allocate("d"); got.String() != "10.244.1.0/24" {
+		t.Errorf("Allocate(d) = %v, want 10.244.1.0/24", got.String())
+	}
+	s.Nodes["x"] = "10.244.0.0/24"
+	if _, err := Restore(s); err == nil {
+		t.Errorf("Restore() with conflicting nodes error = nil")
+	}
+}
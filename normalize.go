@@ -0,0 +1,69 @@
+package ipcalc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Errors returned by ValidateNet.
+var (
+	ErrNonContiguousMask = errors.New("ipcalc: non-contiguous mask")
+	ErrHostBitsSet       = errors.New("ipcalc: host bits set")
+)
+
+// Normalize returns the canonical form of a network: IPv4 addresses and masks are 4 bytes long,
+// IPv4-mapped masks are converted to IPv4 masks and host bits are cleared.
+// It returns a zero IPNet if the address and mask can't be reconciled, see ValidateNet.
+// e.g., Normalize(::ffff:192.0.2.1/120) -> 192.0.2.0/24.
+func Normalize(n net.IPNet) net.IPNet {
+	ip, mask, ok := normalizeNet(n)
+	if !ok {
+		return net.IPNet{}
+	}
+	return net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// ValidateNet returns an error if a network can't be normalized, has a non-contiguous mask or has host bits set.
+// The errors are an *IPError for invalid addresses, a *MismatchError for address and mask families which differ,
+// ErrNonContiguousMask or ErrHostBitsSet.
+func ValidateNet(n net.IPNet) error {
+	if err := checkNet("ValidateNet", n); err != nil {
+		return err
+	}
+	ip, mask, _ := normalizeNet(n)
+	if _, bits := mask.Size(); bits == 0 {
+		return fmt.Errorf("%w: %v", ErrNonContiguousMask, mask)
+	}
+	if !ip.Mask(mask).Equal(ip) {
+		return fmt.Errorf("%w: %v", ErrHostBitsSet, &n)
+	}
+	return nil
+}
+
+// normalizeNet returns the address and mask of a network with the same canonical length,
+// or false if they can't be reconciled.
+func normalizeNet(n net.IPNet) (net.IP, net.IPMask, bool) {
+	ip := IP(n.IP)
+	mask := n.Mask
+	switch {
+	case len(ip) != net.IPv4len && len(ip) != net.IPv6len:
+		return nil, nil, false
+	case len(mask) == len(ip):
+	case len(ip) == net.IPv4len && len(mask) == net.IPv6len && allFF(mask[:net.IPv6len-net.IPv4len]):
+		mask = mask[net.IPv6len-net.IPv4len:]
+	default:
+		return nil, nil, false
+	}
+	return ip, append(net.IPMask(nil), mask...), true
+}
+
+// allFF returns whether every byte is 0xff.
+func allFF(b []byte) bool {
+	for _, x := range b {
+		if x != 0xff {
+			return false
+		}
+	}
+	return true
+}
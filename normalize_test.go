@@ -0,0 +1,55 @@
+package ipcalc
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		n    net.IPNet
+		want string
+	}{
+		{mustCIDR(t, "192.0.2.0/24"), "192.0.2.0/24"},
+		{net.IPNet{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(24, 32)}, "192.0.2.0/24"},
+		{net.IPNet{IP: net.IP{192, 0, 2, 1}, Mask: net.CIDRMask(120, 128)}, "192.0.2.0/24"},
+		{net.IPNet{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(120, 128)}, "192.0.2.0/24"},
+		{net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(32, 128)}, "2001:db8::/32"},
+		{net.IPNet{IP: net.IP{192, 0, 2, 1}, Mask: net.IPMask{255, 0, 255, 0}}, "192.0.2.0/ff00ff00"},
+		{net.IPNet{IP: net.IP{192, 0, 2, 1}, Mask: net.CIDRMask(64, 128)}, "<nil>"},
+		{net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(24, 32)}, "<nil>"},
+		{net.IPNet{IP: net.IP{192, 0, 2}, Mask: net.CIDRMask(24, 32)}, "<nil>"},
+	}
+	for _, tt := range tests {
+		got := Normalize(tt.n)
+		if got.String() != tt.want {
+			t.Errorf("Normalize(%v) = %v, want %v", &tt.n, &got, tt.want)
+		}
+		if got.IP != nil && (len(got.IP) != len(got.Mask) || len(got.IP) != IPSize(got.IP)) {
+			t.Errorf("Normalize(%v) = %#v, want canonical lengths", &tt.n, got)
+		}
+	}
+}
+
+func TestValidateNet(t *testing.T) {
+	var ipErr *IPError
+	var mismatchErr *MismatchError
+	tests := []struct {
+		n    net.IPNet
+		want func(error) bool
+	}{
+		{mustCIDR(t, "192.0.2.0/24"), func(err error) bool { return err == nil }},
+		{mustCIDR(t, "2001:db8::/32"), func(err error) bool { return err == nil }},
+		{net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.CIDRMask(120, 128)}, func(err error) bool { return err == nil }},
+		{net.IPNet{IP: net.IP{192, 0, 2, 1}, Mask: net.CIDRMask(24, 32)}, func(err error) bool { return errors.Is(err, ErrHostBitsSet) }},
+		{net.IPNet{IP: net.IP{192, 0, 2, 0}, Mask: net.IPMask{255, 0, 255, 0}}, func(err error) bool { return errors.Is(err, ErrNonContiguousMask) }},
+		{net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}, func(err error) bool { return errors.As(err, &mismatchErr) }},
+		{net.IPNet{IP: nil, Mask: net.CIDRMask(24, 32)}, func(err error) bool { return errors.As(err, &ipErr) }},
+	}
+	for _, tt := range tests {
+		if err := ValidateNet(tt.n); !tt.want(err) {
+			t.Errorf("ValidateNet(%v) = %v, unexpected error", &tt.n, err)
+		}
+	}
+}
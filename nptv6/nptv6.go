@@ -0,0 +1,136 @@
+// Package nptv6 implements IPv6-to-IPv6 Network Prefix Translation (RFC 6296).
+//
+// NPTv6 statelessly rewrites addresses between an inside and an outside prefix of the same length,
+// adjusting one 16-bit word of the address so transport checksums remain valid:
+//   - For /48 prefixes the adjustment is applied to the subnet ID (bits 48-63)
+//   - For /49 to /64 prefixes it's applied to the first word of the Interface Identifier that isn't 0xffff
+//
+// e.g., with inside fd01:203:405::/48 and outside 2001:db8:1::/48,
+// fd01:203:405:1::1234 is translated to 2001:db8:1:d550::1234.
+package nptv6
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// Supported prefix lengths.
+const (
+	MinPrefixLen = 48
+	MaxPrefixLen = 64
+)
+
+// ErrUntranslatable is returned for addresses which can't be translated without breaking checksum neutrality,
+// i.e., the word carrying the adjustment is 0xffff.
+var ErrUntranslatable = errors.New("nptv6: address can't be translated")
+
+// Translator maps addresses between an inside and an outside prefix.
+type Translator struct {
+	inside  net.IPNet
+	outside net.IPNet
+	// adj is the one's complement adjustment applied to inside addresses.
+	adj uint16
+}
+
+// New returns a Translator between two IPv6 prefixes of the same length, between /48 and /64.
+func New(inside, outside net.IPNet) (*Translator, error) {
+	in, err := normalize(inside)
+	if err != nil {
+		return nil, err
+	}
+	out, err := normalize(outside)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(in.Mask, out.Mask) {
+		return nil, fmt.Errorf("nptv6: prefix lengths differ: %v, %v", &in, &out)
+	}
+	return &Translator{
+		inside:  in,
+		outside: out,
+		adj:     sub(checksum(in.IP), checksum(out.IP)),
+	}, nil
+}
+
+// Inside returns the inside prefix.
+func (t *Translator) Inside() net.IPNet {
+	return t.inside
+}
+
+// Outside returns the outside prefix.
+func (t *Translator) Outside() net.IPNet {
+	return t.outside
+}
+
+// Outbound translates an address from the inside prefix to the outside prefix.
+func (t *Translator) Outbound(ip net.IP) (net.IP, error) {
+	return translate(ip, t.inside, t.outside, t.adj)
+}
+
+// Inbound translates an address from the outside prefix to the inside prefix.
+func (t *Translator) Inbound(ip net.IP) (net.IP, error) {
+	return translate(ip, t.outside, t.inside, ^t.adj)
+}
+
+func translate(ip net.IP, from, to net.IPNet, adj uint16) (net.IP, error) {
+	if len(ip) != net.IPv6len || ip.To4() != nil || !from.Contains(ip) {
+		return nil, fmt.Errorf("nptv6: %v not in %v", ip, &from)
+	}
+	ip = ipcalc.Merge(ip, to.IP, to.Mask)
+	w, ok := adjustmentWord(ip, to.Mask)
+	if !ok {
+		return nil, ErrUntranslatable
+	}
+	v := add(binary.BigEndian.Uint16(ip[2*w:]), adj)
+	if v == 0xffff {
+		v = 0
+	}
+	binary.BigEndian.PutUint16(ip[2*w:], v)
+	return ip, nil
+}
+
+// adjustmentWord returns the index of the 16-bit word carrying the checksum adjustment.
+func adjustmentWord(ip net.IP, mask net.IPMask) (int, bool) {
+	if ones, _ := mask.Size(); ones == MinPrefixLen {
+		return 3, binary.BigEndian.Uint16(ip[6:]) != 0xffff
+	}
+	for w := 4; w < net.IPv6len/2; w++ {
+		if binary.BigEndian.Uint16(ip[2*w:]) != 0xffff {
+			return w, true
+		}
+	}
+	return 0, false
+}
+
+func normalize(n net.IPNet) (net.IPNet, error) {
+	ones, bits := n.Mask.Size()
+	if bits != 8*net.IPv6len || ones < MinPrefixLen || ones > MaxPrefixLen || len(n.IP) != net.IPv6len || n.IP.To4() != nil {
+		return net.IPNet{}, fmt.Errorf("nptv6: %v is not an IPv6 /%v to /%v prefix", &n, MinPrefixLen, MaxPrefixLen)
+	}
+	return net.IPNet{IP: n.IP.Mask(n.Mask), Mask: n.Mask}, nil
+}
+
+// checksum returns the one's complement sum of the routing prefix words of a masked prefix.
+func checksum(prefix net.IP) uint16 {
+	var sum uint16
+	for i := 0; i < MaxPrefixLen/8; i += 2 {
+		sum = add(sum, binary.BigEndian.Uint16(prefix[i:]))
+	}
+	return sum
+}
+
+// add returns the one's complement sum of two words.
+func add(a, b uint16) uint16 {
+	s := uint32(a) + uint32(b)
+	return uint16(s&0xffff + s>>16)
+}
+
+// sub returns the one's complement difference of two words.
+func sub(a, b uint16) uint16 {
+	return add(a, ^b)
+}
@@ -0,0 +1,113 @@
+package nptv6
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+// sum returns the one's complement sum of all words of an address, normalizing 0xffff to 0.
+func sum(ip net.IP) uint16 {
+	var s uint16
+	for i := 0; i < len(ip); i += 2 {
+		s = add(s, binary.BigEndian.Uint16(ip[i:]))
+	}
+	if s == 0xffff {
+		return 0
+	}
+	return s
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		inside  string
+		outside string
+		ok      bool
+	}{
+		{"fd01:203:405::/48", "2001:db8:1::/48", true},
+		{"fd01:203:405:600::/56", "2001:db8:1:200::/56", true},
+		{"fd01:203:405:6::/64", "2001:db8:1:2::/64", true},
+		{"fd01:203::/32", "2001:db8::/32", false},
+		{"fd01:203:405:6::/65", "2001:db8:1:2::/65", false},
+		{"fd01:203:405::/48", "2001:db8:1::/56", false},
+		{"192.0.2.0/24", "198.51.100.0/24", false},
+	}
+	for _, tt := range tests {
+		if _, err := New(mustCIDR(t, tt.inside), mustCIDR(t, tt.outside)); (err == nil) != tt.ok {
+			t.Errorf("New(%v, %v) error = %v", tt.inside, tt.outside, err)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		inside  string
+		outside string
+		in      string
+		out     string
+	}{
+		{"fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:1::1234", "2001:db8:1:d550::1234"},
+		{"fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:2ab0::1", "2001:db8:1::1"},
+		{"fd01:203:405:600::/56", "2001:db8:1:200::/56", "fd01:203:405:6ab::1", ""},
+		{"fd01:203:405:6::/64", "2001:db8:1:2::/64", "fd01:203:405:6:ffff:1:2:3", ""},
+		{"fd01:203:405:6::/64", "2001:db8:1:2::/64", "fd01:203:405:6::", ""},
+	}
+	for _, tt := range tests {
+		tr, err := New(mustCIDR(t, tt.inside), mustCIDR(t, tt.outside))
+		if err != nil {
+			t.Fatalf("New(%v, %v) error = %v", tt.inside, tt.outside, err)
+		}
+		in := net.ParseIP(tt.in)
+		out, err := tr.Outbound(in)
+		if err != nil {
+			t.Errorf("Outbound(%v) error = %v", tt.in, err)
+			continue
+		}
+		if tt.out != "" && !out.Equal(net.ParseIP(tt.out)) {
+			t.Errorf("Outbound(%v) = %v, want %v", tt.in, out, tt.out)
+		}
+		if outside := tr.Outside(); !outside.Contains(out) {
+			t.Errorf("Outbound(%v) = %v, not in %v", tt.in, out, tt.outside)
+		}
+		if a, b := sum(in), sum(out); a != b {
+			t.Errorf("Outbound(%v) = %v, checksum %#x, want %#x", tt.in, out, b, a)
+		}
+		back, err := tr.Inbound(out)
+		if err != nil {
+			t.Errorf("Inbound(%v) error = %v", out, err)
+		} else if !back.Equal(in) {
+			t.Errorf("Inbound(%v) = %v, want %v", out, back, in)
+		}
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tr, err := New(mustCIDR(t, "fd01:203:405::/48"), mustCIDR(t, "2001:db8:1::/48"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, ip := range []string{"fd01:203:406::1", "fd01:203:405:ffff::1", "192.0.2.1"} {
+		if got, err := tr.Outbound(net.ParseIP(ip)); err == nil {
+			t.Errorf("Outbound(%v) = %v, want error", ip, got)
+		}
+	}
+	if got, err := tr.Inbound(net.ParseIP("fd01:203:405::1")); err == nil {
+		t.Errorf("Inbound(fd01:203:405::1) = %v, want error", got)
+	}
+	tr, err = New(mustCIDR(t, "fd01:203:405:6::/64"), mustCIDR(t, "2001:db8:1:2::/64"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, err := tr.Outbound(net.ParseIP("fd01:203:405:6:ffff:ffff:ffff:ffff")); err != ErrUntranslatable {
+		t.Errorf("Outbound() = %v, %v, want ErrUntranslatable", got, err)
+	}
+}
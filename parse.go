@@ -0,0 +1,61 @@
+package ipcalc
+
+import (
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minParseChunk is the minimum number of items parsed by each goroutine.
+const minParseChunk = 4096
+
+// ParseIPs parses a batch of IP addresses concurrently.
+// ips[i] and errs[i] correspond to s[i], errs is nil if every address was parsed successfully.
+func ParseIPs(s []string) (ips []net.IP, errs []error) {
+	return parseBatch(s, func(s string) (net.IP, error) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: s}
+		}
+		return ip, nil
+	})
+}
+
+// ParseCIDRs parses a batch of CIDR networks concurrently, as net.ParseCIDR does.
+// nets[i] and errs[i] correspond to s[i], errs is nil if every network was parsed successfully.
+func ParseCIDRs(s []string) (nets []net.IPNet, errs []error) {
+	return parseBatch(s, func(s string) (net.IPNet, error) {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return net.IPNet{}, err
+		}
+		return *n, nil
+	})
+}
+
+// parseBatch parses s in parallel chunks, writing results in place.
+func parseBatch[T any](s []string, parse func(string) (T, error)) ([]T, []error) {
+	out := make([]T, len(s))
+	errs := make([]error, len(s))
+	var failed atomic.Bool
+	chunk := max(minParseChunk, (len(s)+runtime.GOMAXPROCS(0)-1)/runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(s); lo += chunk {
+		hi := min(lo+chunk, len(s))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				if out[i], errs[i] = parse(s[i]); errs[i] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if !failed.Load() {
+		errs = nil
+	}
+	return out, errs
+}
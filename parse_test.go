@@ -0,0 +1,83 @@
+package ipcalc
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestParseIPs(t *testing.T) {
+	s := []string{"192.0.2.1", "invalid", "2001:db8::1"}
+	ips, errs := ParseIPs(s)
+	if len(ips) != 3 || len(errs) != 3 {
+		t.Fatalf("ParseIPs(%v) = %v, %v", s, ips, errs)
+	}
+	if !ips[0].Equal(net.ParseIP("192.0.2.1")) || ips[1] != nil || !ips[2].Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("ParseIPs(%v) = %v", s, ips)
+	}
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("ParseIPs(%v) errs = %v", s, errs)
+	}
+	if _, errs := ParseIPs([]string{"192.0.2.1"}); errs != nil {
+		t.Errorf("ParseIPs(192.0.2.1) errs = %v, want nil", errs)
+	}
+}
+
+func TestParseIPsLarge(t *testing.T) {
+	s := make([]string, 3*minParseChunk+7)
+	for i := range s {
+		s[i] = fmt.Sprintf("10.%v.%v.%v", i>>16&0xff, i>>8&0xff, i&0xff)
+	}
+	s[minParseChunk+1] = "bogus"
+	ips, errs := ParseIPs(s)
+	for i, ip := range ips {
+		if i == minParseChunk+1 {
+			if ip != nil || errs[i] == nil {
+				t.Errorf("ParseIPs()[%v] = %v, %v, want error", i, ip, errs[i])
+			}
+		} else if ip.String() != s[i] || errs[i] != nil {
+			t.Errorf("ParseIPs()[%v] = %v, %v, want %v", i, ip, errs[i], s[i])
+		}
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	s := []string{"192.0.2.1/24", "2001:db8::/33", "192.0.2.0/33", ""}
+	nets, errs := ParseCIDRs(s)
+	if nets[0].String() != "192.0.2.0/24" || nets[1].String() != "2001:db8::/33" {
+		t.Errorf("ParseCIDRs(%v) = %v", s, nets)
+	}
+	if errs[0] != nil || errs[1] != nil || errs[2] == nil || errs[3] == nil {
+		t.Errorf("ParseCIDRs(%v) errs = %v", s, errs)
+	}
+	if nets, errs := ParseCIDRs(nil); len(nets) != 0 || errs != nil {
+		t.Errorf("ParseCIDRs(nil) = %v, %v", nets, errs)
+	}
+}
+
+func benchInput() []string {
+	s := make([]string, 1<<16)
+	for i := range s {
+		s[i] = fmt.Sprintf("2001:db8::%x:%x", i>>8, i&0xff)
+	}
+	return s
+}
+
+func BenchmarkParseIPs(b *testing.B) {
+	s := benchInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseIPs(s)
+	}
+}
+
+func BenchmarkParseIPSequential(b *testing.B) {
+	s := benchInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ips := make([]net.IP, len(s))
+		for j, x := range s {
+			ips[j] = net.ParseIP(x)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ipcalc
+
+import (
+	"iter"
+	"math/big"
+	"net"
+)
+
+// ReservePolicy describes which addresses of a subnet can't be assigned to hosts,
+// as a number of addresses reserved at the start and at the end of the subnet.
+// Custom policies can be defined with a ReservePolicy literal.
+type ReservePolicy struct {
+	// Name identifies the policy.
+	Name string
+	// Head is the number of reserved addresses at the start of a subnet, e.g., the network address.
+	Head int
+	// Tail is the number of reserved addresses at the end of a subnet, e.g., the broadcast address.
+	Tail int
+}
+
+// Predefined reservation policies.
+var (
+	// ReserveNone reserves no addresses, e.g., IPv4 /31 point-to-point links (RFC 3021).
+	ReserveNone = ReservePolicy{Name: "none"}
+	// ReserveNetworkBroadcast reserves the network and broadcast addresses, the classic IPv4 convention.
+	ReserveNetworkBroadcast = ReservePolicy{Name: "network+broadcast", Head: 1, Tail: 1}
+	// ReserveSubnetRouterAnycast reserves the IPv6 Subnet-Router anycast address (RFC 4291).
+	ReserveSubnetRouterAnycast = ReservePolicy{Name: "subnet-router anycast", Head: 1}
+	// ReserveAWS reserves the network address, VPC router, DNS, future use and broadcast addresses.
+	ReserveAWS = ReservePolicy{Name: "aws", Head: 4, Tail: 1}
+	// ReserveAzure reserves the network address, default gateway, two DNS and broadcast addresses.
+	ReserveAzure = ReservePolicy{Name: "azure", Head: 4, Tail: 1}
+	// ReserveGCP reserves the network address, default gateway, second-to-last and broadcast addresses.
+	ReserveGCP = ReservePolicy{Name: "gcp", Head: 2, Tail: 2}
+)
+
+// UsableHosts returns the number of usable host addresses in a subnet, or 0 if it's invalid.
+// e.g., ReserveAWS.UsableHosts(10.0.0.0/24) -> 251.
+func (p ReservePolicy) UsableHosts(n net.IPNet) *big.Int {
+	_, ones, size, err := splitBase(n)
+	if err != nil {
+		return new(big.Int)
+	}
+	count := new(big.Int).Lsh(big.NewInt(1), uint(size-ones))
+	count.Sub(count, big.NewInt(int64(p.Head+p.Tail)))
+	if count.Sign() < 0 {
+		return count.SetInt64(0)
+	}
+	return count
+}
+
+// FirstHost returns the first usable host address in a subnet, or false if there is none.
+// e.g., ReserveAWS.FirstHost(10.0.0.0/24) -> 10.0.0.4.
+func (p ReservePolicy) FirstHost(n net.IPNet) (net.IP, bool) {
+	ip := p.NthHost(n, new(big.Int))
+	return ip, ip != nil
+}
+
+// LastHost returns the last usable host address in a subnet, or false if there is none.
+// e.g., ReserveAWS.LastHost(10.0.0.0/24) -> 10.0.0.254.
+func (p ReservePolicy) LastHost(n net.IPNet) (net.IP, bool) {
+	first, ones, size, err := splitBase(n)
+	if err != nil || p.UsableHosts(n).Sign() == 0 {
+		return nil, false
+	}
+	return SubBig(Broadcast(net.IPNet{IP: first, Mask: net.CIDRMask(ones, size)}), big.NewInt(int64(p.Tail))), true
+}
+
+// NthHost returns the usable host address at the given zero-based index in a subnet, or nil if there is none.
+// e.g., ReserveAWS.NthHost(10.0.0.0/24, 4) -> 10.0.0.8.
+func (p ReservePolicy) NthHost(n net.IPNet, i *big.Int) net.IP {
+	first, _, _, err := splitBase(n)
+	if err != nil || i.Sign() < 0 || i.Cmp(p.UsableHosts(n)) >= 0 {
+		return nil
+	}
+	return AddBig(first, new(big.Int).Add(i, big.NewInt(int64(p.Head))))
+}
+
+// HostIndex returns the zero-based index of a usable host address in a subnet, the inverse of NthHost.
+// It returns nil if ip isn't a usable host address in the subnet.
+// e.g., ReserveAWS.HostIndex(10.0.0.0/24, 10.0.0.8) -> 4.
+func (p ReservePolicy) HostIndex(n net.IPNet, ip net.IP) *big.Int {
+	first, _, _, err := splitBase(n)
+	if err != nil || !n.Contains(ip) {
+		return nil
+	}
+	i := Delta(first, ip)
+	if i == nil {
+		return nil
+	}
+	if i.Sub(i, big.NewInt(int64(p.Head))); i.Sign() < 0 || i.Cmp(p.UsableHosts(n)) >= 0 {
+		return nil
+	}
+	return i
+}
+
+// Hosts returns an iterator over every usable host address in a subnet, in ascending order.
+// e.g., ReserveGCP.Hosts(10.0.0.0/29) -> 10.0.0.2, 10.0.0.3, 10.0.0.4, 10.0.0.5.
+func (p ReservePolicy) Hosts(n net.IPNet) iter.Seq[net.IP] {
+	first, ok := p.FirstHost(n)
+	if !ok {
+		return rangeSeq(Range{})
+	}
+	last, _ := p.LastHost(n)
+	return rangeSeq(Range{First: first, Last: last})
+}
+
+// Reserved returns whether an address in a subnet is reserved by the policy.
+// Addresses outside the subnet are not reserved.
+func (p ReservePolicy) Reserved(n net.IPNet, ip net.IP) bool {
+	return n.Contains(ip) && p.HostIndex(n, ip) == nil
+}
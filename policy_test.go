@@ -0,0 +1,131 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestReservePolicy(t *testing.T) {
+	tests := []struct {
+		p     ReservePolicy
+		n     string
+		hosts string
+		first string
+		last  string
+	}{
+		{ReserveNone, "192.0.2.0/31", "2", "192.0.2.0", "192.0.2.1"},
+		{ReserveNetworkBroadcast, "192.0.2.0/24", "254", "192.0.2.1", "192.0.2.254"},
+		{ReserveNetworkBroadcast, "192.0.2.0/31", "0", "", ""},
+		{ReserveAWS, "10.0.0.0/24", "251", "10.0.0.4", "10.0.0.254"},
+		{ReserveAWS, "10.0.0.16/28", "11", "10.0.0.20", "10.0.0.30"},
+		{ReserveAzure, "10.0.0.0/29", "3", "10.0.0.4", "10.0.0.6"},
+		{ReserveGCP, "10.0.0.0/29", "4", "10.0.0.2", "10.0.0.5"},
+		{ReserveGCP, "10.0.0.0/30", "0", "", ""},
+		{ReserveSubnetRouterAnycast, "2001:db8::/64", "18446744073709551615", "2001:db8::1", "2001:db8::ffff:ffff:ffff:ffff"},
+		{ReservePolicy{Name: "custom", Head: 10, Tail: 5}, "198.51.100.128/25", "113", "198.51.100.138", "198.51.100.250"},
+	}
+	for _, tt := range tests {
+		n := mustCIDR(t, tt.n)
+		if got := tt.p.UsableHosts(n); got.String() != tt.hosts {
+			t.Errorf("%v.UsableHosts(%v) = %v, want %v", tt.p.Name, tt.n, got, tt.hosts)
+		}
+		first, ok := tt.p.FirstHost(n)
+		if ok != (tt.first != "") || ok && !first.Equal(net.ParseIP(tt.first)) {
+			t.Errorf("%v.FirstHost(%v) = %v, %v, want %v", tt.p.Name, tt.n, first, ok, tt.first)
+		}
+		last, ok := tt.p.LastHost(n)
+		if ok != (tt.last != "") || ok && !last.Equal(net.ParseIP(tt.last)) {
+			t.Errorf("%v.LastHost(%v) = %v, %v, want %v", tt.p.Name, tt.n, last, ok, tt.last)
+		}
+	}
+}
+
+func TestReservePolicyNthHost(t *testing.T) {
+	tests := []struct {
+		p    ReservePolicy
+		n    string
+		i    int64
+		want string
+	}{
+		{ReserveAWS, "10.0.0.0/24", 0, "10.0.0.4"},
+		{ReserveAWS, "10.0.0.0/24", 4, "10.0.0.8"},
+		{ReserveAWS, "10.0.0.0/24", 250, "10.0.0.254"},
+		{ReserveAWS, "10.0.0.0/24", 251, ""},
+		{ReserveAWS, "10.0.0.0/24", -1, ""},
+		{ReserveAWS, "::ffff:10.0.0.0/120", 4, "10.0.0.8"},
+		{ReserveGCP, "10.0.0.0/30", 0, ""},
+		{ReserveNone, "192.0.2.1/32", 0, "192.0.2.1"},
+		{ReserveSubnetRouterAnycast, "2001:db8::/64", 1 << 40, "2001:db8::100:0:1"},
+	}
+	for _, tt := range tests {
+		n := mustCIDR(t, tt.n)
+		got := tt.p.NthHost(n, big.NewInt(tt.i))
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("%v.NthHost(%v, %v) = %v, want nil", tt.p.Name, tt.n, tt.i, got)
+			}
+			continue
+		}
+		if !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("%v.NthHost(%v, %v) = %v, want %v", tt.p.Name, tt.n, tt.i, got, tt.want)
+			continue
+		}
+		if i := tt.p.HostIndex(n, got); i == nil || i.Int64() != tt.i {
+			t.Errorf("%v.HostIndex(%v, %v) = %v, want %v", tt.p.Name, tt.n, got, i, tt.i)
+		}
+	}
+}
+
+func TestReservePolicyHostIndexInvalid(t *testing.T) {
+	n := mustCIDR(t, "10.0.0.0/24")
+	for _, ip := range []string{"10.0.0.3", "10.0.0.255", "10.0.1.4", "2001:db8::4"} {
+		if got := ReserveAWS.HostIndex(n, net.ParseIP(ip)); got != nil {
+			t.Errorf("aws.HostIndex(10.0.0.0/24, %v) = %v, want nil", ip, got)
+		}
+	}
+}
+
+func TestReservePolicyHosts(t *testing.T) {
+	tests := []struct {
+		p    ReservePolicy
+		n    string
+		want []string
+	}{
+		{ReserveGCP, "10.0.0.0/29", []string{"10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5"}},
+		{ReserveAzure, "10.0.0.0/29", []string{"10.0.0.4", "10.0.0.5", "10.0.0.6"}},
+		{ReserveAWS, "10.0.0.0/29", []string{"10.0.0.4", "10.0.0.5", "10.0.0.6"}},
+		{ReserveGCP, "10.0.0.0/30", nil},
+		{ReserveNone, "2001:db8::/127", []string{"2001:db8::", "2001:db8::1"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for ip := range tt.p.Hosts(mustCIDR(t, tt.n)) {
+			got = append(got, ip.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v.Hosts(%v) = %v, want %v", tt.p.Name, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestReserved(t *testing.T) {
+	n := mustCIDR(t, "10.0.0.0/24")
+	tests := map[string]bool{
+		"10.0.0.0":   true,
+		"10.0.0.3":   true,
+		"10.0.0.4":   false,
+		"10.0.0.254": false,
+		"10.0.0.255": true,
+		"10.0.1.0":   false,
+	}
+	for ip, want := range tests {
+		if got := ReserveAWS.Reserved(n, net.ParseIP(ip)); got != want {
+			t.Errorf("Reserved(%v, %v) = %v, want %v", n.String(), ip, got, want)
+		}
+	}
+	if !ReserveGCP.Reserved(mustCIDR(t, "10.0.0.0/30"), net.ParseIP("10.0.0.1")) {
+		t.Errorf("Reserved(10.0.0.0/30, 10.0.0.1) = false, want true")
+	}
+}
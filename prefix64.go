@@ -0,0 +1,52 @@
+package ipcalc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrLongerThan64 is returned by Check64 for IPv6 subnets longer than /64.
+var ErrLongerThan64 = errors.New("ipcalc: IPv6 subnet longer than /64")
+
+var mask64 = net.CIDRMask(64, 8*net.IPv6len)
+
+// Prefix64 returns the /64 network containing an IPv6 address,
+// or a zero IPNet if ip isn't an IPv6 address, including IPv4-mapped addresses.
+// e.g., Prefix64(2001:db8::1) -> 2001:db8::/64.
+func Prefix64(ip net.IP) net.IPNet {
+	if ip = IP(ip); len(ip) != net.IPv6len {
+		return net.IPNet{}
+	}
+	return net.IPNet{
+		IP:   ip.Mask(mask64),
+		Mask: mask64,
+	}
+}
+
+// IID returns the 64-bit Interface Identifier of an IPv6 address, i.e., its low 64 bits,
+// or false if ip isn't an IPv6 address, including IPv4-mapped addresses.
+// e.g., IID(2001:db8::1:2) -> 0x10002, true.
+func IID(ip net.IP) (uint64, bool) {
+	if ip = IP(ip); len(ip) != net.IPv6len {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(ip[8:]), true
+}
+
+// Respects64 returns whether n is an IPv6 prefix no longer than /64,
+// i.e., it's either a /64 subnet or can be carved into /64 subnets.
+func Respects64(n net.IPNet) bool {
+	ones, bits := n.Mask.Size()
+	return bits == 8*net.IPv6len && ones <= 64 && IPVersion(n.IP) == 6
+}
+
+// Check64 returns an error wrapping ErrLongerThan64 if n is an IPv6 subnet longer than /64,
+// which breaks SLAAC and other tooling assuming a 64-bit Interface Identifier.
+func Check64(n net.IPNet) error {
+	if ones, bits := n.Mask.Size(); bits == 8*net.IPv6len && ones > 64 && IPVersion(n.IP) == 6 {
+		return fmt.Errorf("%w: %v", ErrLongerThan64, &n)
+	}
+	return nil
+}
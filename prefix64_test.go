@@ -0,0 +1,83 @@
+package ipcalc
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestPrefix64(t *testing.T) {
+	tests := map[string]string{
+		"2001:db8::1":                    "2001:db8::/64",
+		"2001:db8:1:2:3:4:5:6":           "2001:db8:1:2::/64",
+		"fe80::ffff:ffff:ffff:ffff":      "fe80::/64",
+		"ffff:ffff:ffff:ffff:ffff::ffff": "ffff:ffff:ffff:ffff::/64",
+		"192.0.2.1":                      "<nil>",
+		"::ffff:192.0.2.1":               "<nil>",
+		"invalid":                        "<nil>",
+	}
+	for ip, want := range tests {
+		if got := Prefix64(net.ParseIP(ip)); got.String() != want {
+			t.Errorf("Prefix64(%v) = %v, want %v", ip, got.String(), want)
+		}
+	}
+}
+
+func TestIID(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want uint64
+		ok   bool
+	}{
+		{"2001:db8::1:2", 0x10002, true},
+		{"2001:db8::", 0, true},
+		{"fe80::211:22ff:fe33:4455", 0x021122fffe334455, true},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", 0xffffffffffffffff, true},
+		{"192.0.2.1", 0, false},
+		{"::ffff:192.0.2.1", 0, false},
+		{"invalid", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := IID(net.ParseIP(tt.ip)); got != tt.want || ok != tt.ok {
+			t.Errorf("IID(%v) = %#x, %v, want %#x, %v", tt.ip, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRespects64(t *testing.T) {
+	tests := map[string]bool{
+		"2001:db8::/64":  true,
+		"2001:db8::/48":  true,
+		"2001:db8::/65":  false,
+		"2001:db8::/127": false,
+		"192.0.2.0/24":   false,
+	}
+	for addr, want := range tests {
+		_, n, err := net.ParseCIDR(addr)
+		if err != nil {
+			t.Fatalf("ParseCIDR(%v) error = %v", addr, err)
+		}
+		if got := Respects64(*n); got != want {
+			t.Errorf("Respects64(%v) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheck64(t *testing.T) {
+	tests := map[string]bool{
+		"2001:db8::/64":  false,
+		"2001:db8::/32":  false,
+		"2001:db8::/65":  true,
+		"2001:db8::/127": true,
+		"192.0.2.0/31":   false,
+	}
+	for addr, want := range tests {
+		_, n, err := net.ParseCIDR(addr)
+		if err != nil {
+			t.Fatalf("ParseCIDR(%v) error = %v", addr, err)
+		}
+		if got := errors.Is(Check64(*n), ErrLongerThan64); got != want {
+			t.Errorf("Check64(%v) error = %v, want ErrLongerThan64 = %v", addr, Check64(*n), want)
+		}
+	}
+}
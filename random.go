@@ -0,0 +1,34 @@
+package ipcalc
+
+import (
+	"crypto/rand"
+	"net"
+)
+
+// RandomIP returns a cryptographically random address uniformly distributed over a network.
+// e.g., RandomIP(2001:db8::/64) -> 2001:db8::8c1f:27a0:5b3e:d412.
+func RandomIP(n net.IPNet) (net.IP, error) {
+	first, _, _, err := splitBase(n)
+	if err != nil {
+		return nil, err
+	}
+	i, err := rand.Int(rand.Reader, AddressCount(n))
+	if err != nil {
+		return nil, err
+	}
+	return AddBig(first, i), nil
+}
+
+// RandomHost returns a cryptographically random usable host address uniformly distributed over a network.
+// See NthHost for which addresses are usable.
+// e.g., RandomHost(192.0.2.0/30) -> 192.0.2.2.
+func RandomHost(n net.IPNet) (net.IP, error) {
+	if _, _, _, err := splitBase(n); err != nil {
+		return nil, err
+	}
+	i, err := rand.Int(rand.Reader, UsableHosts(n))
+	if err != nil {
+		return nil, err
+	}
+	return NthHost(n, i), nil
+}
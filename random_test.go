@@ -0,0 +1,53 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRandomIP(t *testing.T) {
+	for _, s := range []string{"192.0.2.0/30", "192.0.2.1/32", "::ffff:192.0.2.0/126", "2001:db8::/126", "2001:db8::/64", "::/0"} {
+		n := mustCIDR(t, s)
+		seen := map[string]bool{}
+		for i := 0; i < 200; i++ {
+			ip, err := RandomIP(n)
+			if err != nil {
+				t.Fatalf("RandomIP(%v) error = %v", s, err)
+			}
+			if !n.Contains(ip) || len(ip) != IPSize(n.IP) {
+				t.Errorf("RandomIP(%v) = %v, want an address in the network", s, ip)
+			}
+			seen[ip.String()] = true
+		}
+		// Small networks should be fully covered, large ones shouldn't repeat.
+		if count := AddressCount(n); count.IsInt64() && count.Int64() <= 4 {
+			if int64(len(seen)) != count.Int64() {
+				t.Errorf("RandomIP(%v) returned %v distinct addresses, want %v", s, len(seen), count)
+			}
+		} else if len(seen) < 190 {
+			t.Errorf("RandomIP(%v) returned %v distinct addresses out of 200", s, len(seen))
+		}
+	}
+}
+
+func TestRandomHost(t *testing.T) {
+	n := mustCIDR(t, "192.0.2.0/30")
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		ip, err := RandomHost(n)
+		if err != nil {
+			t.Fatalf("RandomHost(%v) error = %v", &n, err)
+		}
+		seen[ip.String()] = true
+	}
+	if len(seen) != 2 || !seen["192.0.2.1"] || !seen["192.0.2.2"] {
+		t.Errorf("RandomHost(%v) returned %v, want 192.0.2.1 and 192.0.2.2", &n, seen)
+	}
+	bad := net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}
+	if ip, err := RandomHost(bad); err == nil {
+		t.Errorf("RandomHost(%v) = %v, want error", &bad, ip)
+	}
+	if ip, err := RandomIP(bad); err == nil {
+		t.Errorf("RandomIP(%v) = %v, want error", &bad, ip)
+	}
+}
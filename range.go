@@ -0,0 +1,179 @@
+package ipcalc
+
+import (
+	"math/big"
+	"net"
+	"strings"
+)
+
+// Range is an inclusive interval of IP addresses of the same version, which needn't be CIDR-aligned.
+type Range struct {
+	First net.IP
+	Last  net.IP
+}
+
+// String returns the "first-last" form of a Range.
+func (r Range) String() string {
+	return r.First.String() + "-" + r.Last.String()
+}
+
+// ParseRange returns a Range from a "first-last" string representation, spaces around the dash are allowed.
+// Both endpoints must be from the same family and first must not be greater than last.
+// e.g., ParseRange("192.0.2.10 - 192.0.2.50") -> 192.0.2.10-192.0.2.50.
+func ParseRange(s string) (Range, error) {
+	first, last, ok := strings.Cut(s, "-")
+	if !ok {
+		return Range{}, &net.ParseError{Type: "IP range", Text: s}
+	}
+	r := Range{First: net.ParseIP(strings.TrimSpace(first)), Last: net.ParseIP(strings.TrimSpace(last))}
+	if r.First == nil {
+		return Range{}, &net.ParseError{Type: "IP address", Text: first}
+	}
+	if r.Last == nil {
+		return Range{}, &net.ParseError{Type: "IP address", Text: last}
+	}
+	if !sameFamily(r.First, r.Last) || Compare(r.First, r.Last) > 0 {
+		return Range{}, &net.ParseError{Type: "IP range", Text: s}
+	}
+	r.First = IP(r.First)
+	r.Last = IP(r.Last)
+	return r, nil
+}
+
+// Len returns the number of addresses in a Range, or 0 if it's empty or its endpoints are from different families.
+// e.g., Len(192.0.2.5-192.0.3.130) -> 382.
+func (r Range) Len() *big.Int {
+	l := Delta(r.First, r.Last)
+	if l == nil || l.Sign() < 0 {
+		return new(big.Int)
+	}
+	return l.Add(l, big.NewInt(1))
+}
+
+// Contains returns whether an IP address is within a Range.
+// e.g., Contains(192.0.2.5-192.0.3.130, 192.0.3.1) -> true.
+func (r Range) Contains(ip net.IP) bool {
+	return sameFamily(r.First, ip) && Compare(r.First, ip) <= 0 && Compare(ip, r.Last) <= 0
+}
+
+// Overlaps returns whether two Ranges share at least one address.
+// e.g., Overlaps(192.0.2.0-192.0.2.10, 192.0.2.10-192.0.2.20) -> true.
+func (r Range) Overlaps(o Range) bool {
+	return sameFamily(r.First, o.First) && Compare(r.First, o.Last) <= 0 && Compare(o.First, r.Last) <= 0
+}
+
+// Equal returns whether two Ranges have the same endpoints, IPv4 and IPv4-mapped IPv6 addresses are considered equal.
+func (r Range) Equal(o Range) bool {
+	return r.First.Equal(o.First) && r.Last.Equal(o.Last)
+}
+
+// RangeToCIDRs returns the minimal list of networks covering exactly a Range, in ascending order.
+// It returns nil if the Range is reversed or its endpoints are from different families.
+// e.g., RangeToCIDRs(192.0.2.5-192.0.2.12) -> [192.0.2.5/32 192.0.2.6/31 192.0.2.8/30 192.0.2.12/32].
+func RangeToCIDRs(r Range) []net.IPNet {
+	if !sameFamily(r.First, r.Last) || Compare(r.First, r.Last) > 0 {
+		return nil
+	}
+	size := IPSize(r.First)
+	bits := 8 * size
+	start := IPToBig(r.First)
+	end := IPToBig(r.Last)
+	var nets []net.IPNet
+	for start.Cmp(end) <= 0 {
+		// The largest block aligned on start which doesn't go past end.
+		hostBits := bits
+		if start.Sign() != 0 {
+			hostBits = int(start.TrailingZeroBits())
+		}
+		span := new(big.Int).Sub(end, start)
+		hostBits = min(hostBits, span.Add(span, big.NewInt(1)).BitLen()-1)
+		ip, _ := BigToIP(start, size)
+		nets = append(nets, net.IPNet{IP: ip, Mask: net.CIDRMask(bits-hostBits, bits)})
+		start.Add(start, new(big.Int).Lsh(big.NewInt(1), uint(hostBits)))
+	}
+	return nets
+}
+
+// CIDRToRange returns the first and last addresses of a network as a Range.
+// It returns a zero Range if the network's address and mask are from different families.
+// e.g., CIDRToRange(192.0.2.0/31) -> 192.0.2.0-192.0.2.1.
+func CIDRToRange(n net.IPNet) Range {
+	ip, mask, ok := normalizeNet(n)
+	if !ok {
+		return Range{}
+	}
+	return Range{First: ip.Mask(mask), Last: Broadcast(n)}
+}
+
+// Midpoint returns the address halfway between two addresses, rounded towards a,
+// or nil if they're from different families.
+// e.g., Midpoint(192.0.2.0, 192.0.2.255) -> 192.0.2.127.
+func Midpoint(a, b net.IP) net.IP {
+	d := Delta(a, b)
+	if d == nil {
+		return nil
+	}
+	return AddBig(a, d.Quo(d, big.NewInt(2)))
+}
+
+// ChunkRange splits a Range into n contiguous Ranges whose lengths differ by at most one, longest first.
+// Fewer Ranges are returned if the Range has less than n addresses, nil is returned if it's invalid or n < 1.
+// e.g., ChunkRange(192.0.2.0-192.0.2.9, 3) -> [192.0.2.0-192.0.2.3 192.0.2.4-192.0.2.6 192.0.2.7-192.0.2.9].
+func ChunkRange(r Range, n int) []Range {
+	l := r.Len()
+	if n < 1 || l.Sign() == 0 {
+		return nil
+	}
+	if l.IsInt64() && l.Int64() < int64(n) {
+		n = int(l.Int64())
+	}
+	size, rem := new(big.Int).QuoRem(l, big.NewInt(int64(n)), new(big.Int))
+	chunks := make([]Range, n)
+	first := IP(r.First)
+	for i := range chunks {
+		step := new(big.Int).Set(size)
+		if int64(i) < rem.Int64() {
+			step.Add(step, big.NewInt(1))
+		}
+		last := AddBig(first, step.Sub(step, big.NewInt(1)))
+		chunks[i] = Range{First: first, Last: last}
+		first = NextIP(last)
+	}
+	return chunks
+}
+
+// sameFamily returns whether two IP addresses are valid and of the same family.
+func sameFamily(a, b net.IP) bool {
+	a = IP(a)
+	b = IP(b)
+	return len(a) != 0 && len(a) == len(b)
+}
+
+// CollapseIPs merges a list of IP addresses into the minimal list of Ranges covering them.
+// The input needn't be sorted and may contain duplicates, Ranges are returned in ascending order
+// with all IPv4 Ranges first.
+// e.g., CollapseIPs(192.0.2.3, 192.0.2.1, 192.0.2.2, 192.0.2.9) -> [192.0.2.1-192.0.2.3 192.0.2.9-192.0.2.9].
+func CollapseIPs(ips []net.IP) []Range {
+	sorted := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		if ip = IP(ip); len(ip) != 0 {
+			sorted = append(sorted, ip)
+		}
+	}
+	SortIPs(sorted)
+	var ranges []Range
+	for _, ip := range sorted {
+		if n := len(ranges); n > 0 {
+			last := ranges[n-1].Last
+			if last.Equal(ip) {
+				continue
+			}
+			if len(last) == len(ip) && NextIP(last).Equal(ip) {
+				ranges[n-1].Last = ip
+				continue
+			}
+		}
+		ranges = append(ranges, Range{First: ip, Last: ip})
+	}
+	return ranges
+}
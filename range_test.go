@@ -0,0 +1,265 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestRangeString(t *testing.T) {
+	r := Range{net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.10")}
+	if got, want := r.String(), "192.0.2.1-192.0.2.10"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestCollapseIPs(t *testing.T) {
+	tests := []struct {
+		ips  []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"192.0.2.1"}, []string{"192.0.2.1-192.0.2.1"}},
+		{[]string{"192.0.2.3", "192.0.2.1", "192.0.2.2", "192.0.2.9"}, []string{"192.0.2.1-192.0.2.3", "192.0.2.9-192.0.2.9"}},
+		{[]string{"192.0.2.255", "192.0.3.0", "192.0.2.255", "::ffff:192.0.3.1"}, []string{"192.0.2.255-192.0.3.1"}},
+		{[]string{"2001:db8::2", "192.0.2.1", "2001:db8::1", "invalid"}, []string{"192.0.2.1-192.0.2.1", "2001:db8::1-2001:db8::2"}},
+		{[]string{"255.255.255.255", "0.0.0.0", "::", "::1"}, []string{"0.0.0.0-0.0.0.0", "255.255.255.255-255.255.255.255", "::-::1"}},
+	}
+	for _, tt := range tests {
+		var ips []net.IP
+		for _, ip := range tt.ips {
+			ips = append(ips, net.ParseIP(ip))
+		}
+		got := CollapseIPs(ips)
+		if len(got) != len(tt.want) {
+			t.Errorf("CollapseIPs(%v) = %v, want %v", tt.ips, got, tt.want)
+			continue
+		}
+		for i, r := range got {
+			if r.String() != tt.want[i] {
+				t.Errorf("CollapseIPs(%v)[%v] = %v, want %v", tt.ips, i, r, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRangeLen(t *testing.T) {
+	tests := []struct {
+		first, last string
+		want        string
+	}{
+		{"192.0.2.5", "192.0.3.130", "382"},
+		{"192.0.2.1", "192.0.2.1", "1"},
+		{"192.0.2.2", "192.0.2.1", "0"},
+		{"192.0.2.1", "2001:db8::1", "0"},
+		{"0.0.0.0", "255.255.255.255", "4294967296"},
+		{"::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "340282366920938463463374607431768211456"},
+	}
+	for _, tt := range tests {
+		r := Range{net.ParseIP(tt.first), net.ParseIP(tt.last)}
+		if got := r.Len(); got.String() != tt.want {
+			t.Errorf("Len(%v) = %v, want %v", r, got, tt.want)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	r := Range{net.ParseIP("192.0.2.5"), net.ParseIP("192.0.3.130")}
+	tests := map[string]bool{
+		"192.0.2.4":          false,
+		"192.0.2.5":          true,
+		"192.0.3.1":          true,
+		"::ffff:192.0.3.130": true,
+		"192.0.3.131":        false,
+		"::c000:205":         false,
+	}
+	for ip, want := range tests {
+		if got := r.Contains(net.ParseIP(ip)); got != want {
+			t.Errorf("Contains(%v, %v) = %v, want %v", r, ip, got, want)
+		}
+	}
+}
+
+func TestRangeOverlaps(t *testing.T) {
+	tests := []struct {
+		a, b [2]string
+		want bool
+	}{
+		{[2]string{"192.0.2.0", "192.0.2.10"}, [2]string{"192.0.2.10", "192.0.2.20"}, true},
+		{[2]string{"192.0.2.0", "192.0.2.10"}, [2]string{"192.0.2.11", "192.0.2.20"}, false},
+		{[2]string{"192.0.2.0", "192.0.2.255"}, [2]string{"192.0.2.11", "192.0.2.20"}, true},
+		{[2]string{"192.0.2.11", "192.0.2.20"}, [2]string{"192.0.2.0", "192.0.2.255"}, true},
+		{[2]string{"0.0.0.0", "255.255.255.255"}, [2]string{"::", "::1"}, false},
+	}
+	for _, tt := range tests {
+		a := Range{net.ParseIP(tt.a[0]), net.ParseIP(tt.a[1])}
+		b := Range{net.ParseIP(tt.b[0]), net.ParseIP(tt.b[1])}
+		if got := a.Overlaps(b); got != tt.want {
+			t.Errorf("Overlaps(%v, %v) = %v, want %v", a, b, got, tt.want)
+		}
+	}
+}
+
+func TestRangeEqual(t *testing.T) {
+	a := Range{net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.10")}
+	b := Range{net.IP{192, 0, 2, 1}, net.IP{192, 0, 2, 10}}
+	c := Range{net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.11")}
+	if !a.Equal(b) {
+		t.Errorf("Equal(%v, %v) = false, want true", a, b)
+	}
+	if a.Equal(c) {
+		t.Errorf("Equal(%v, %v) = true, want false", a, c)
+	}
+}
+
+func TestRangeToCIDRs(t *testing.T) {
+	tests := []struct {
+		first, last string
+		want        []string
+	}{
+		{"192.0.2.5", "192.0.2.12", []string{"192.0.2.5/32", "192.0.2.6/31", "192.0.2.8/30", "192.0.2.12/32"}},
+		{"192.0.2.5", "192.0.3.130", []string{"192.0.2.5/32", "192.0.2.6/31", "192.0.2.8/29", "192.0.2.16/28", "192.0.2.32/27", "192.0.2.64/26", "192.0.2.128/25", "192.0.3.0/25", "192.0.3.128/31", "192.0.3.130/32"}},
+		{"192.0.2.0", "192.0.2.255", []string{"192.0.2.0/24"}},
+		{"192.0.2.1", "192.0.2.1", []string{"192.0.2.1/32"}},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}},
+		{"255.255.255.254", "255.255.255.255", []string{"255.255.255.254/31"}},
+		{"::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", []string{"::/0"}},
+		{"2001:db8::1", "2001:db8::ff", []string{"2001:db8::1/128", "2001:db8::2/127", "2001:db8::4/126", "2001:db8::8/125", "2001:db8::10/124", "2001:db8::20/123", "2001:db8::40/122", "2001:db8::80/121"}},
+		{"2001:db8::", "2001:db8:0:1::", []string{"2001:db8::/64", "2001:db8:0:1::/128"}},
+		{"192.0.2.2", "192.0.2.1", nil},
+		{"192.0.2.1", "2001:db8::1", nil},
+	}
+	for _, tt := range tests {
+		r := Range{net.ParseIP(tt.first), net.ParseIP(tt.last)}
+		var got []string
+		for _, n := range RangeToCIDRs(r) {
+			got = append(got, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RangeToCIDRs(%v) = %v, want %v", r, got, tt.want)
+		}
+	}
+}
+
+func TestCIDRToRange(t *testing.T) {
+	tests := []struct {
+		n    net.IPNet
+		want string
+	}{
+		{mustCIDR(t, "192.0.2.0/24"), "192.0.2.0-192.0.2.255"},
+		{mustCIDR(t, "192.0.2.77/24"), "192.0.2.0-192.0.2.255"},
+		{mustCIDR(t, "192.0.2.0/31"), "192.0.2.0-192.0.2.1"},
+		{mustCIDR(t, "192.0.2.1/32"), "192.0.2.1-192.0.2.1"},
+		{mustCIDR(t, "0.0.0.0/0"), "0.0.0.0-255.255.255.255"},
+		{mustCIDR(t, "2001:db8::/127"), "2001:db8::-2001:db8::1"},
+		{mustCIDR(t, "2001:db8::/32"), "2001:db8::-2001:db8:ffff:ffff:ffff:ffff:ffff:ffff"},
+		{net.IPNet{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(24, 32)}, "192.0.2.0-192.0.2.255"},
+		{net.IPNet{IP: net.IP{192, 0, 2, 1}, Mask: net.CIDRMask(120, 128)}, "192.0.2.0-192.0.2.255"},
+		{net.IPNet{IP: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(24, 32)}, "<nil>-<nil>"},
+	}
+	for _, tt := range tests {
+		got := CIDRToRange(tt.n)
+		if got.String() != tt.want {
+			t.Errorf("CIDRToRange(%v) = %v, want %v", &tt.n, got, tt.want)
+		}
+		if got.First == nil {
+			continue
+		}
+		if nets := RangeToCIDRs(got); len(nets) != 1 || !CIDRToRange(nets[0]).Equal(got) {
+			t.Errorf("RangeToCIDRs(%v) = %v, want a single network", got, nets)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.10-192.0.2.50":       "192.0.2.10-192.0.2.50",
+		"192.0.2.10 - 192.0.2.50":     "192.0.2.10-192.0.2.50",
+		" 192.0.2.10  -192.0.2.10 ":   "192.0.2.10-192.0.2.10",
+		"2001:db8::1-2001:db8::ff":    "2001:db8::1-2001:db8::ff",
+		"::ffff:192.0.2.1-192.0.2.2":  "192.0.2.1-192.0.2.2",
+		"192.0.2.50-192.0.2.10":       "",
+		"192.0.2.1-2001:db8::1":       "",
+		"192.0.2.1":                   "",
+		"192.0.2.1-":                  "",
+		"-192.0.2.1":                  "",
+		"192.0.2.1-192.0.2.2-192.0.2": "",
+		"192.0.2.0/24":                "",
+	}
+	for s, want := range tests {
+		got, err := ParseRange(s)
+		if want == "" {
+			if err == nil {
+				t.Errorf("ParseRange(%q) = %v, want error", s, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRange(%q) error = %v", s, err)
+			continue
+		}
+		if got.String() != want || len(got.First) != len(got.Last) {
+			t.Errorf("ParseRange(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"192.0.2.0", "192.0.2.255", "192.0.2.127"},
+		{"192.0.2.255", "192.0.2.0", "192.0.2.128"},
+		{"192.0.2.1", "192.0.2.1", "192.0.2.1"},
+		{"0.0.0.0", "255.255.255.255", "127.255.255.255"},
+		{"::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "7fff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+		{"2001:db8::", "2001:db8:0:2::", "2001:db8:0:1::"},
+		{"192.0.2.1", "2001:db8::1", ""},
+	}
+	for _, tt := range tests {
+		got := Midpoint(net.ParseIP(tt.a), net.ParseIP(tt.b))
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("Midpoint(%v, %v) = %v, want nil", tt.a, tt.b, got)
+			}
+			continue
+		}
+		if !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("Midpoint(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestChunkRange(t *testing.T) {
+	tests := []struct {
+		r    string
+		n    int
+		want []string
+	}{
+		{"192.0.2.0-192.0.2.9", 3, []string{"192.0.2.0-192.0.2.3", "192.0.2.4-192.0.2.6", "192.0.2.7-192.0.2.9"}},
+		{"192.0.2.0-192.0.2.9", 1, []string{"192.0.2.0-192.0.2.9"}},
+		{"192.0.2.0-192.0.2.2", 5, []string{"192.0.2.0-192.0.2.0", "192.0.2.1-192.0.2.1", "192.0.2.2-192.0.2.2"}},
+		{"10.0.0.0-10.0.255.255", 4, []string{"10.0.0.0-10.0.63.255", "10.0.64.0-10.0.127.255", "10.0.128.0-10.0.191.255", "10.0.192.0-10.0.255.255"}},
+		{"255.255.255.254-255.255.255.255", 2, []string{"255.255.255.254-255.255.255.254", "255.255.255.255-255.255.255.255"}},
+		{"::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", 2, []string{"::-7fff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "8000::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"}},
+		{"192.0.2.0-192.0.2.9", 0, nil},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, c := range ChunkRange(r, tt.n) {
+			got = append(got, c.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ChunkRange(%v, %v) = %v, want %v", tt.r, tt.n, got, tt.want)
+		}
+	}
+	bad := Range{net.ParseIP("192.0.2.9"), net.ParseIP("192.0.2.0")}
+	if got := ChunkRange(bad, 2); got != nil {
+		t.Errorf("ChunkRange(%v, 2) = %v, want nil", bad, got)
+	}
+}
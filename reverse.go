@@ -0,0 +1,128 @@
+package ipcalc
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	ipv4ReverseSuffix = ".in-addr.arpa."
+	ipv6ReverseSuffix = ".ip6.arpa."
+)
+
+// ReverseName returns the fully qualified PTR name of an IP address, or "" if it's invalid.
+// e.g., ReverseName(192.0.2.1) -> 1.2.0.192.in-addr.arpa.,
+// ReverseName(2001:db8::1) -> 1.0.0.0.[...].8.b.d.0.1.0.0.2.ip6.arpa.
+func ReverseName(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return strconv.Itoa(int(ip4[3])) + "." + strconv.Itoa(int(ip4[2])) + "." +
+			strconv.Itoa(int(ip4[1])) + "." + strconv.Itoa(int(ip4[0])) + ipv4ReverseSuffix
+	}
+	if len(ip) != net.IPv6len {
+		return ""
+	}
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	b.Grow(4*net.IPv6len + len(ipv6ReverseSuffix) - 1)
+	for i := len(ip) - 1; i >= 0; i-- {
+		b.WriteByte(hex[ip[i]&0xf])
+		b.WriteByte('.')
+		b.WriteByte(hex[ip[i]>>4])
+		if i > 0 {
+			b.WriteByte('.')
+		}
+	}
+	b.WriteString(ipv6ReverseSuffix)
+	return b.String()
+}
+
+// ReverseZones returns the reverse DNS zones delegated for a network, or nil if it's invalid.
+// IPv4 networks longer than /24 use RFC 2317 classless delegation naming, other networks not on an
+// octet (IPv4) or nibble (IPv6) boundary are split into the zones of the next boundary.
+// e.g., ReverseZones(192.0.2.0/23) -> [2.0.192.in-addr.arpa. 3.0.192.in-addr.arpa.],
+// ReverseZones(192.0.2.64/26) -> [64/26.2.0.192.in-addr.arpa.],
+// ReverseZones(2001:db8::/31) -> [8.b.d.0.1.0.0.2.ip6.arpa. 9.b.d.0.1.0.0.2.ip6.arpa.].
+func ReverseZones(n net.IPNet) []string {
+	first, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil
+	}
+	labelBits := 4
+	if size == 8*net.IPv4len {
+		labelBits = 8
+		if ones > 24 && ones < 32 {
+			return []string{strconv.Itoa(int(first[3])) + "/" + strconv.Itoa(ones) + "." + reverseZone(first, 3, size, labelBits)}
+		}
+	}
+	boundary := (ones + labelBits - 1) / labelBits * labelBits
+	subnets, err := Split(net.IPNet{IP: first, Mask: net.CIDRMask(ones, size)}, boundary)
+	if err != nil {
+		return nil
+	}
+	zones := make([]string, len(subnets))
+	for i, s := range subnets {
+		zones[i] = reverseZone(s.IP, boundary/labelBits, size, labelBits)
+	}
+	return zones
+}
+
+// reverseZone returns the reverse zone made up of the first labels octets or nibbles of an IP address.
+func reverseZone(ip net.IP, labels, size, labelBits int) string {
+	parts := strings.Split(ReverseName(ip), ".")
+	return strings.Join(parts[size/labelBits-labels:], ".")
+}
+
+// ParseReverseName returns the IP address for a PTR name, the trailing dot is optional and case is ignored.
+// e.g., ParseReverseName(1.2.0.192.in-addr.arpa) -> 192.0.2.1.
+func ParseReverseName(s string) (net.IP, error) {
+	name := strings.ToLower(s)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	var ip net.IP
+	switch {
+	case strings.HasSuffix(name, ipv4ReverseSuffix):
+		ip = parseReverse4(strings.Split(strings.TrimSuffix(name, ipv4ReverseSuffix), "."))
+	case strings.HasSuffix(name, ipv6ReverseSuffix):
+		ip = parseReverse6(strings.Split(strings.TrimSuffix(name, ipv6ReverseSuffix), "."))
+	}
+	if ip == nil {
+		return nil, &net.ParseError{Type: "reverse name", Text: s}
+	}
+	return ip, nil
+}
+
+func parseReverse4(labels []string) net.IP {
+	if len(labels) != net.IPv4len {
+		return nil
+	}
+	ip := make(net.IP, net.IPv4len)
+	for i, l := range labels {
+		v, err := strconv.ParseUint(l, 10, 8)
+		if err != nil || (len(l) > 1 && l[0] == '0') {
+			return nil
+		}
+		ip[net.IPv4len-1-i] = byte(v)
+	}
+	return ip
+}
+
+func parseReverse6(labels []string) net.IP {
+	if len(labels) != 2*net.IPv6len {
+		return nil
+	}
+	ip := make(net.IP, net.IPv6len)
+	for i, l := range labels {
+		if len(l) != 1 {
+			return nil
+		}
+		v, err := strconv.ParseUint(l, 16, 4)
+		if err != nil {
+			return nil
+		}
+		j := len(labels) - 1 - i
+		ip[j/2] |= byte(v) << (4 * uint(1-j%2))
+	}
+	return ip
+}
@@ -0,0 +1,90 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestReverseName(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		want string
+	}{
+		{net.ParseIP("192.0.2.1"), "1.2.0.192.in-addr.arpa."},
+		{net.IP{10, 0, 0, 255}, "255.0.0.10.in-addr.arpa."},
+		{net.ParseIP("2001:db8::1"), "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."},
+		{net.ParseIP("fe80::abcd"), "d.c.b.a.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.e.f.ip6.arpa."},
+		{nil, ""},
+		{net.IP{1, 2, 3}, ""},
+	}
+	for _, tt := range tests {
+		got := ReverseName(tt.ip)
+		if got != tt.want {
+			t.Errorf("ReverseName(%v) = %v, want %v", tt.ip, got, tt.want)
+		}
+		if tt.want == "" {
+			continue
+		}
+		if ip, err := ParseReverseName(got); err != nil || !ip.Equal(tt.ip) {
+			t.Errorf("ParseReverseName(%v) = %v, %v, want %v", got, ip, err, tt.ip)
+		}
+	}
+}
+
+func TestReverseZones(t *testing.T) {
+	tests := []struct {
+		n    net.IPNet
+		want []string
+	}{
+		{mustCIDR(t, "192.0.2.0/24"), []string{"2.0.192.in-addr.arpa."}},
+		{mustCIDR(t, "192.0.2.0/23"), []string{"2.0.192.in-addr.arpa.", "3.0.192.in-addr.arpa."}},
+		{mustCIDR(t, "10.0.0.0/8"), []string{"10.in-addr.arpa."}},
+		{mustCIDR(t, "0.0.0.0/0"), []string{"in-addr.arpa."}},
+		{mustCIDR(t, "192.0.2.64/26"), []string{"64/26.2.0.192.in-addr.arpa."}},
+		{mustCIDR(t, "192.0.2.1/32"), []string{"1.2.0.192.in-addr.arpa."}},
+		{mustCIDR(t, "::ffff:192.0.2.0/120"), []string{"2.0.192.in-addr.arpa."}},
+		{mustCIDR(t, "2001:db8::/32"), []string{"8.b.d.0.1.0.0.2.ip6.arpa."}},
+		{mustCIDR(t, "2001:db8::/31"), []string{"8.b.d.0.1.0.0.2.ip6.arpa.", "9.b.d.0.1.0.0.2.ip6.arpa."}},
+		{mustCIDR(t, "2001:db8::/30"), []string{"8.b.d.0.1.0.0.2.ip6.arpa.", "9.b.d.0.1.0.0.2.ip6.arpa.", "a.b.d.0.1.0.0.2.ip6.arpa.", "b.b.d.0.1.0.0.2.ip6.arpa."}},
+		{mustCIDR(t, "::/0"), []string{"ip6.arpa."}},
+		{net.IPNet{IP: net.IP{192, 0, 2, 0}, Mask: net.IPMask{255, 0, 255, 0}}, nil},
+		{net.IPNet{}, nil},
+	}
+	for _, tt := range tests {
+		if got := ReverseZones(tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReverseZones(%v) = %v, want %v", &tt.n, got, tt.want)
+		}
+	}
+	if got := ReverseZones(mustCIDR(t, "2001:db8::/125")); len(got) != 8 {
+		t.Errorf("ReverseZones(2001:db8::/125) = %v, want 8 zones", got)
+	}
+}
+
+func TestParseReverseName(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"1.2.0.192.in-addr.arpa", "192.0.2.1"},
+		{"1.2.0.192.IN-ADDR.ARPA.", "192.0.2.1"},
+		{"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.B.D.0.1.0.0.2.ip6.arpa", "2001:db8::1"},
+		{"2.0.192.in-addr.arpa.", ""},
+		{"256.2.0.192.in-addr.arpa.", ""},
+		{"01.2.0.192.in-addr.arpa.", ""},
+		{"1.2.0.192.example.com.", ""},
+		{"10.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", ""},
+		{"g.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseReverseName(tt.s)
+		if tt.want == "" {
+			if _, ok := err.(*net.ParseError); !ok {
+				t.Errorf("ParseReverseName(%v) = %v, %v, want ParseError", tt.s, got, err)
+			}
+		} else if err != nil || !got.Equal(net.ParseIP(tt.want)) {
+			t.Errorf("ParseReverseName(%v) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package ipcalc
+
+import (
+	"net"
+	"sort"
+)
+
+// Address scopes (RFC 6724, section 3.1).
+const (
+	scopeLinkLocal = 0x2
+	scopeSiteLocal = 0x5
+	scopeGlobal    = 0xe
+)
+
+// PolicyEntry is an entry in an RFC 6724 policy table.
+type PolicyEntry struct {
+	Prefix     net.IPNet
+	Precedence int
+	Label      int
+}
+
+// PolicyTable is an RFC 6724 policy table, IPv4 addresses are matched in their IPv4-mapped form.
+type PolicyTable []PolicyEntry
+
+// DefaultPolicyTable is the default RFC 6724 policy table.
+var DefaultPolicyTable = PolicyTable{
+	{mustParseCIDR("::1/128"), 50, 0},
+	{mustParseCIDR("::/0"), 40, 1},
+	{mustParseCIDR("::ffff:0:0/96"), 35, 4},
+	{mustParseCIDR("2002::/16"), 30, 2},
+	{mustParseCIDR("2001::/32"), 5, 5},
+	{mustParseCIDR("fc00::/7"), 3, 13},
+	{mustParseCIDR("::/96"), 1, 3},
+	{mustParseCIDR("fec0::/10"), 1, 11},
+	{mustParseCIDR("3ffe::/16"), 1, 12},
+}
+
+// SortByRFC6724 sorts destination addresses by preference using the DefaultPolicyTable.
+// See PolicyTable.Sort.
+func SortByRFC6724(dsts []net.IP, source func(net.IP) net.IP) {
+	DefaultPolicyTable.Sort(dsts, source)
+}
+
+// Sort sorts destination addresses by preference, following the RFC 6724 destination address selection rules.
+// source returns the source address which would be used to reach a destination, or nil if it's unreachable.
+// Rules depending on state unavailable here (deprecated and home addresses, native transport) are skipped,
+// and the source prefix length for rule 9 is assumed to be 64 for IPv6.
+// The sort is stable, destinations tied on all rules keep their relative order.
+func (t PolicyTable) Sort(dsts []net.IP, source func(net.IP) net.IP) {
+	attrs := make([]addrAttrs, len(dsts))
+	for i, d := range dsts {
+		attrs[i] = t.attrs(d, source(d))
+	}
+	sort.Stable(&byRFC6724{dsts, attrs})
+}
+
+// Classify returns the precedence and label of the longest matching policy table entry for an IP address.
+func (t PolicyTable) Classify(ip net.IP) (precedence, label int) {
+	ip = ip.To16()
+	best := -1
+	for _, e := range t {
+		if ones, _ := e.Prefix.Mask.Size(); ones > best && e.Prefix.Contains(ip) {
+			best, precedence, label = ones, e.Precedence, e.Label
+		}
+	}
+	return precedence, label
+}
+
+type addrAttrs struct {
+	dst, src    net.IP
+	scope       int
+	srcScope    int
+	precedence  int
+	label       int
+	srcLabel    int
+	prefixLen   int
+	unreachable bool
+}
+
+func (t PolicyTable) attrs(dst, src net.IP) addrAttrs {
+	a := addrAttrs{dst: IP(dst), scope: scope(dst), unreachable: src == nil}
+	a.precedence, a.label = t.Classify(dst)
+	if src != nil {
+		a.src = IP(src)
+		a.srcScope = scope(src)
+		_, a.srcLabel = t.Classify(src)
+		if len(a.src) == len(a.dst) {
+			a.prefixLen = CommonPrefixLen(a.src, a.dst)
+			if len(a.dst) == net.IPv6len && a.prefixLen > 64 {
+				a.prefixLen = 64
+			}
+		}
+	}
+	return a
+}
+
+type byRFC6724 struct {
+	dsts  []net.IP
+	attrs []addrAttrs
+}
+
+func (s *byRFC6724) Len() int {
+	return len(s.dsts)
+}
+
+func (s *byRFC6724) Swap(i, j int) {
+	s.dsts[i], s.dsts[j] = s.dsts[j], s.dsts[i]
+	s.attrs[i], s.attrs[j] = s.attrs[j], s.attrs[i]
+}
+
+// Less returns whether destination i is preferred over destination j.
+func (s *byRFC6724) Less(i, j int) bool {
+	a, b := s.attrs[i], s.attrs[j]
+	// Rule 1: Avoid unusable destinations.
+	if a.unreachable != b.unreachable {
+		return b.unreachable
+	}
+	if a.unreachable {
+		return false
+	}
+	// Rule 2: Prefer matching scope.
+	if am, bm := a.scope == a.srcScope, b.scope == b.srcScope; am != bm {
+		return am
+	}
+	// Rule 5: Prefer matching label.
+	if am, bm := a.label == a.srcLabel, b.label == b.srcLabel; am != bm {
+		return am
+	}
+	// Rule 6: Prefer higher precedence.
+	if a.precedence != b.precedence {
+		return a.precedence > b.precedence
+	}
+	// Rule 8: Prefer smaller scope.
+	if a.scope != b.scope {
+		return a.scope < b.scope
+	}
+	// Rule 9: Use longest matching prefix, for destinations of the same family.
+	if len(a.dst) == len(b.dst) && a.prefixLen != b.prefixLen {
+		return a.prefixLen > b.prefixLen
+	}
+	// Rule 10: Otherwise, leave the order unchanged.
+	return false
+}
+
+// scope returns the RFC 6724 scope of an IP address.
+func scope(ip net.IP) int {
+	if ip4 := ip.To4(); ip4 != nil {
+		if ip4.IsLoopback() || ip4.IsLinkLocalUnicast() {
+			return scopeLinkLocal
+		}
+		return scopeGlobal
+	}
+	switch {
+	case ip.IsMulticast():
+		return int(ip[1] & 0xf)
+	case ip.IsLoopback(), ip.IsLinkLocalUnicast():
+		return scopeLinkLocal
+	case len(ip) == net.IPv6len && ip[0] == 0xfe && ip[1]&0xc0 == 0xc0:
+		return scopeSiteLocal
+	}
+	return scopeGlobal
+}
+
+func mustParseCIDR(s string) net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return *n
+}
@@ -0,0 +1,105 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSortByRFC6724(t *testing.T) {
+	// Examples from RFC 6724, section 10.2.
+	tests := []struct {
+		dsts []string
+		srcs map[string]string
+		want []string
+	}{
+		{
+			[]string{"198.51.100.121", "2001:db8:1::1"},
+			map[string]string{"2001:db8:1::1": "2001:db8:1::2", "198.51.100.121": "169.254.13.78"},
+			[]string{"2001:db8:1::1", "198.51.100.121"},
+		},
+		{
+			[]string{"2001:db8:1::1", "198.51.100.121"},
+			map[string]string{"2001:db8:1::1": "fe80::1", "198.51.100.121": "198.51.100.117"},
+			[]string{"198.51.100.121", "2001:db8:1::1"},
+		},
+		{
+			[]string{"10.1.2.3", "2001:db8:1::1"},
+			map[string]string{"2001:db8:1::1": "2001:db8:1::2", "10.1.2.3": "10.1.2.4"},
+			[]string{"2001:db8:1::1", "10.1.2.3"},
+		},
+		{
+			[]string{"2001:db8:1::1", "fe80::1"},
+			map[string]string{"2001:db8:1::1": "2001:db8:1::2", "fe80::1": "fe80::2"},
+			[]string{"fe80::1", "2001:db8:1::1"},
+		},
+		{
+			[]string{"2001:db8:3ffe::1", "2001:db8:1::1"},
+			map[string]string{"2001:db8:1::1": "2001:db8:1::2", "2001:db8:3ffe::1": "2001:db8:3f44::2"},
+			[]string{"2001:db8:1::1", "2001:db8:3ffe::1"},
+		},
+		{
+			[]string{"2001:db8:1::1", "2002:c633:6401::1"},
+			map[string]string{"2002:c633:6401::1": "2002:c633:6401::2", "2001:db8:1::1": "fe80::2"},
+			[]string{"2002:c633:6401::1", "2001:db8:1::1"},
+		},
+		{
+			[]string{"2001:db8:1::1", "2002:c633:6401::1"},
+			map[string]string{"2002:c633:6401::1": "2002:c633:6401::2", "2001:db8:1::1": "2002:c633:6401::2"},
+			[]string{"2002:c633:6401::1", "2001:db8:1::1"},
+		},
+		{
+			[]string{"2001:db8:1::1", "2002:c633:6401::1"},
+			map[string]string{"2002:c633:6401::1": "2001:db8:1::2", "2001:db8:1::1": "2001:db8:1::2"},
+			[]string{"2001:db8:1::1", "2002:c633:6401::1"},
+		},
+		{
+			[]string{"2001:db8:1::1", "fe80::1", "2001:db8:2::1"},
+			map[string]string{"fe80::1": "fe80::2"},
+			[]string{"fe80::1", "2001:db8:1::1", "2001:db8:2::1"},
+		},
+	}
+	for _, tt := range tests {
+		dsts := make([]net.IP, len(tt.dsts))
+		for i, d := range tt.dsts {
+			dsts[i] = net.ParseIP(d)
+		}
+		SortByRFC6724(dsts, func(ip net.IP) net.IP {
+			return net.ParseIP(tt.srcs[ip.String()])
+		})
+		for i, d := range dsts {
+			if d.String() != tt.want[i] {
+				t.Errorf("SortByRFC6724(%v) = %v, want %v", tt.dsts, dsts, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		ip         string
+		precedence int
+		label      int
+	}{
+		{"::1", 50, 0},
+		{"2001:db8::1", 40, 1},
+		{"192.0.2.1", 35, 4},
+		{"2002::1", 30, 2},
+		{"2001::1", 5, 5},
+		{"fd00::1", 3, 13},
+		{"::192.0.2.1", 1, 3},
+		{"fec0::1", 1, 11},
+		{"3ffe::1", 1, 12},
+	}
+	for _, tt := range tests {
+		if p, l := DefaultPolicyTable.Classify(net.ParseIP(tt.ip)); p != tt.precedence || l != tt.label {
+			t.Errorf("Classify(%v) = %v, %v, want %v, %v", tt.ip, p, l, tt.precedence, tt.label)
+		}
+	}
+	custom := PolicyTable{{mustCIDR(t, "::/0"), 10, 1}, {mustCIDR(t, "::ffff:0:0/96"), 100, 4}}
+	dsts := []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")}
+	custom.Sort(dsts, func(ip net.IP) net.IP { return ip })
+	if !dsts[0].Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Sort() with custom table = %v, want IPv4 first", dsts)
+	}
+}
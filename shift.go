@@ -0,0 +1,31 @@
+package ipcalc
+
+import (
+	"net"
+
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// ShiftLeft returns an IP address shifted left by n bits as a big-endian integer, or nil if it's invalid.
+// Bits shifted out are discarded.
+// e.g., ShiftLeft(0.0.1.128, 1) -> 0.0.3.0.
+func ShiftLeft(ip net.IP, n uint) net.IP {
+	ip = canonical(ip)
+	u, ok := uint128.FromIP(ip)
+	if !ok {
+		return nil
+	}
+	return u.ShiftLeft(n).IP(len(ip))
+}
+
+// ShiftRight returns an IP address shifted right by n bits as a big-endian integer, or nil if it's invalid.
+// Bits shifted out are discarded.
+// e.g., ShiftRight(0.0.3.0, 1) -> 0.0.1.128.
+func ShiftRight(ip net.IP, n uint) net.IP {
+	ip = canonical(ip)
+	u, ok := uint128.FromIP(ip)
+	if !ok {
+		return nil
+	}
+	return u.ShiftRight(n).IP(len(ip))
+}
@@ -0,0 +1,46 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		ip    string
+		n     uint
+		left  string
+		right string
+	}{
+		{"0.0.1.128", 1, "0.0.3.0", "0.0.0.192"},
+		{"0.0.3.0", 1, "0.0.6.0", "0.0.1.128"},
+		{"192.0.2.1", 0, "192.0.2.1", "192.0.2.1"},
+		{"192.0.2.1", 8, "0.2.1.0", "0.192.0.2"},
+		{"255.255.255.255", 31, "128.0.0.0", "0.0.0.1"},
+		{"255.255.255.255", 32, "0.0.0.0", "0.0.0.0"},
+		{"255.255.255.255", 100, "0.0.0.0", "0.0.0.0"},
+		{"::ffff:192.0.2.1", 8, "0.2.1.0", "0.192.0.2"},
+		{"::2a", 72, "0:0:0:2a00::", "::"},
+		{"2001:db8::", 64, "::", "::2001:db8:0:0"},
+		{"8000::1", 1, "::2", "4000::"},
+		{"ffff::ffff", 1 << 62, "::", "::"},
+		{"255.255.255.255", 1 << 62, "0.0.0.0", "0.0.0.0"},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if got := ShiftLeft(ip, tt.n); !got.Equal(net.ParseIP(tt.left)) || len(got) != IPSize(ip) {
+			t.Errorf("ShiftLeft(%v, %v) = %v, want %v", tt.ip, tt.n, got, tt.left)
+		}
+		if got := ShiftRight(ip, tt.n); !got.Equal(net.ParseIP(tt.right)) || len(got) != IPSize(ip) {
+			t.Errorf("ShiftRight(%v, %v) = %v, want %v", tt.ip, tt.n, got, tt.right)
+		}
+	}
+	for _, ip := range []net.IP{nil, {1, 2, 3}} {
+		if got := ShiftLeft(ip, 1); got != nil {
+			t.Errorf("ShiftLeft(%v, 1) = %v, want nil", ip, got)
+		}
+		if got := ShiftRight(ip, 1); got != nil {
+			t.Errorf("ShiftRight(%v, 1) = %v, want nil", ip, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package ipcalc
+
+import (
+	"fmt"
+	"iter"
+	"net"
+
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// MaxShuffleBits is the maximum number of host bits of a network iterated by a Shuffler.
+const MaxShuffleBits = 64
+
+// ShuffleState is the resumable state of a Shuffler.
+type ShuffleState struct {
+	// Seed determines the visiting order.
+	Seed uint64
+	// Next is the internal state for the next address to visit.
+	Next uint64
+	// Done is set once every address has been visited.
+	Done bool
+}
+
+// Shuffler visits every address of a network exactly once in a pseudo-random order determined by a seed,
+// it's not safe for concurrent use and isn't suitable for cryptographic purposes.
+//
+// The order is produced by a full-period linear congruential generator modulo the network size
+// whose output is scrambled by a seeded bijection, so it needs no memory proportional to the network size.
+type Shuffler struct {
+	first    uint128.Uint128
+	size     int
+	hostBits uint
+	mask     uint64
+	mul, inc uint64
+	key      uint64
+	mix1     uint64
+	mix2     uint64
+	start    uint64
+	seed     uint64
+	next     uint64
+	done     bool
+}
+
+// NewShuffler returns a Shuffler over a network with at most MaxShuffleBits host bits.
+func NewShuffler(n net.IPNet, seed uint64) (*Shuffler, error) {
+	s, err := newShuffler(n, seed)
+	if err != nil {
+		return nil, err
+	}
+	s.next = s.start
+	return s, nil
+}
+
+// ResumeShuffler returns a Shuffler over a network resuming from a state returned by Shuffler.State.
+// The network must be the same one the state was saved from.
+func ResumeShuffler(n net.IPNet, st ShuffleState) (*Shuffler, error) {
+	s, err := newShuffler(n, st.Seed)
+	if err != nil {
+		return nil, err
+	}
+	if st.Next&^s.mask != 0 {
+		return nil, fmt.Errorf("ipcalc: invalid shuffle state %+v for %v", st, &n)
+	}
+	s.next, s.done = st.Next, st.Done
+	return s, nil
+}
+
+func newShuffler(n net.IPNet, seed uint64) (*Shuffler, error) {
+	first, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil, err
+	}
+	if size-ones > MaxShuffleBits {
+		return nil, fmt.Errorf("ipcalc: %v has more than %v host bits", &n, MaxShuffleBits)
+	}
+	s := &Shuffler{size: len(first), hostBits: uint(size - ones), seed: seed, mask: ^uint64(0)}
+	s.first, _ = uint128.FromIP(first)
+	if s.hostBits < 64 {
+		s.mask = 1<<s.hostBits - 1
+	}
+	// The LCG has full period modulo 2^k iff inc is odd and mul is 1 modulo 4 (Hull-Dobell).
+	x := seed
+	s.mul = splitMix64(&x)<<2 | 1
+	s.inc = splitMix64(&x) | 1
+	s.key = splitMix64(&x)
+	s.mix1 = splitMix64(&x) | 1
+	s.mix2 = splitMix64(&x) | 1
+	s.start = splitMix64(&x) & s.mask
+	return s, nil
+}
+
+// State returns the current state of the Shuffler, which can be resumed with ResumeShuffler.
+func (s *Shuffler) State() ShuffleState {
+	return ShuffleState{Seed: s.seed, Next: s.next, Done: s.done}
+}
+
+// All returns an iterator over the addresses not visited yet, an address counts as visited once it's yielded.
+func (s *Shuffler) All() iter.Seq[net.IP] {
+	return func(yield func(net.IP) bool) {
+		for !s.done {
+			x := s.next
+			s.next = (s.mul*x + s.inc) & s.mask
+			s.done = s.next == s.start
+			if !yield(s.first.Add(uint128.Uint128{Lo: s.scramble(x)}).IP(s.size)) {
+				return
+			}
+		}
+	}
+}
+
+// scramble is a seeded bijection on hostBits-bit values, hiding the LCG's weak low bits.
+func (s *Shuffler) scramble(x uint64) uint64 {
+	x = (x ^ s.key) * s.mix1 & s.mask
+	x ^= x >> ((s.hostBits + 1) / 2)
+	return x * s.mix2 & s.mask
+}
+
+// splitMix64 returns the next value of a SplitMix64 generator.
+func splitMix64(x *uint64) uint64 {
+	*x += 0x9e3779b97f4a7c15
+	z := *x
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
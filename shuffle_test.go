@@ -0,0 +1,99 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func shuffled(t *testing.T, s *Shuffler) []string {
+	t.Helper()
+	var ips []string
+	for ip := range s.All() {
+		ips = append(ips, ip.String())
+	}
+	return ips
+}
+
+func TestShuffler(t *testing.T) {
+	for _, str := range []string{"192.0.2.0/24", "192.0.2.0/28", "192.0.2.0/31", "192.0.2.1/32", "2001:db8::/116", "255.255.255.0/24"} {
+		n := mustCIDR(t, str)
+		s, err := NewShuffler(n, 42)
+		if err != nil {
+			t.Fatalf("NewShuffler(%v) error = %v", str, err)
+		}
+		got := shuffled(t, s)
+		seen := map[string]bool{}
+		for _, ip := range got {
+			if seen[ip] || !n.Contains(net.ParseIP(ip)) {
+				t.Errorf("NewShuffler(%v) yielded %v twice or outside the network", str, ip)
+			}
+			seen[ip] = true
+		}
+		if want := AddressCount(n).Int64(); int64(len(seen)) != want {
+			t.Errorf("NewShuffler(%v) yielded %v addresses, want %v", str, len(seen), want)
+		}
+		if !s.State().Done || len(shuffled(t, s)) != 0 {
+			t.Errorf("NewShuffler(%v) not done after a full cycle", str)
+		}
+	}
+}
+
+func TestShufflerOrder(t *testing.T) {
+	n := mustCIDR(t, "10.0.0.0/16")
+	a, _ := NewShuffler(n, 1)
+	b, _ := NewShuffler(n, 1)
+	c, _ := NewShuffler(n, 2)
+	sa, sb, sc := shuffled(t, a), shuffled(t, b), shuffled(t, c)
+	if !reflect.DeepEqual(sa, sb) {
+		t.Errorf("NewShuffler(%v, 1) orders differ between runs", &n)
+	}
+	if reflect.DeepEqual(sa, sc) {
+		t.Errorf("NewShuffler(%v) orders are equal for different seeds", &n)
+	}
+	// Not sequential, the first few addresses shouldn't be adjacent.
+	adjacent := 0
+	for i := 1; i < 100; i++ {
+		if NextIP(net.ParseIP(sa[i-1])).Equal(net.ParseIP(sa[i])) {
+			adjacent++
+		}
+	}
+	if adjacent > 5 {
+		t.Errorf("NewShuffler(%v) yielded %v adjacent pairs out of 100", &n, adjacent)
+	}
+}
+
+func TestShufflerResume(t *testing.T) {
+	n := mustCIDR(t, "192.0.2.0/24")
+	full, _ := NewShuffler(n, 7)
+	want := shuffled(t, full)
+
+	s, _ := NewShuffler(n, 7)
+	var got []string
+	for ip := range s.All() {
+		got = append(got, ip.String())
+		if len(got) == 100 {
+			break
+		}
+	}
+	r, err := ResumeShuffler(n, s.State())
+	if err != nil {
+		t.Fatalf("ResumeShuffler(%v, %+v) error = %v", &n, s.State(), err)
+	}
+	got = append(got, shuffled(t, r)...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResumeShuffler(%v) = %v, want %v", &n, got, want)
+	}
+}
+
+func TestShufflerErrors(t *testing.T) {
+	if _, err := NewShuffler(mustCIDR(t, "2001:db8::/63"), 1); err == nil {
+		t.Errorf("NewShuffler(2001:db8::/63) error = nil, want error")
+	}
+	if _, err := NewShuffler(mustCIDR(t, "2001:db8::/64"), 1); err != nil {
+		t.Errorf("NewShuffler(2001:db8::/64) error = %v", err)
+	}
+	if _, err := ResumeShuffler(mustCIDR(t, "192.0.2.0/24"), ShuffleState{Next: 256}); err == nil {
+		t.Errorf("ResumeShuffler(192.0.2.0/24, Next: 256) error = nil, want error")
+	}
+}
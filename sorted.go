@@ -0,0 +1,114 @@
+package ipcalc
+
+import (
+	"net"
+	"slices"
+)
+
+// SortedNets is a list of networks sorted as by SortNets, answering longest-prefix match queries with a binary search.
+// It uses little more memory than the networks themselves, insertions cost O(n) and lookups O(log n) plus
+// the nesting depth of the networks, making it a lightweight alternative to a trie for mid-size tables.
+// The zero value is an empty SortedNets ready to use, it's not safe for concurrent use.
+type SortedNets struct {
+	nets []net.IPNet
+	// parents[i] is the index of the longest network containing nets[i], or -1.
+	parents []int32
+}
+
+// NewSortedNets returns a SortedNets with the given networks, invalid and duplicate networks are ignored.
+// Networks are normalized, i.e., IPv4-mapped networks are stored as IPv4 networks and host bits are cleared.
+func NewSortedNets(nets []net.IPNet) *SortedNets {
+	s := &SortedNets{}
+	for _, n := range nets {
+		if n, ok := sortedKey(n); ok {
+			s.nets = append(s.nets, n)
+		}
+	}
+	SortNets(s.nets)
+	s.nets = slices.CompactFunc(s.nets, func(a, b net.IPNet) bool { return CompareNet(a, b) == 0 })
+	s.link()
+	return s
+}
+
+// Insert adds a network, it returns false if the network is invalid or already present.
+func (s *SortedNets) Insert(n net.IPNet) bool {
+	n, ok := sortedKey(n)
+	if !ok {
+		return false
+	}
+	i, found := slices.BinarySearchFunc(s.nets, n, CompareNet)
+	if found {
+		return false
+	}
+	s.nets = slices.Insert(s.nets, i, n)
+	s.link()
+	return true
+}
+
+// Len returns the number of networks.
+func (s *SortedNets) Len() int {
+	return len(s.nets)
+}
+
+// Nets returns a copy of the networks, in the order defined by CompareNet.
+func (s *SortedNets) Nets() []net.IPNet {
+	return slices.Clone(s.nets)
+}
+
+// Lookup returns the longest network containing an IP address.
+// e.g., Lookup(192.0.2.1) -> 192.0.2.0/24, true with networks [0.0.0.0/0 192.0.2.0/24 192.0.2.128/25].
+func (s *SortedNets) Lookup(ip net.IP) (net.IPNet, bool) {
+	ip = canonical(ip)
+	if ip == nil {
+		return net.IPNet{}, false
+	}
+	// Networks are laminar, so the longest network containing ip is either the last one starting at or before ip,
+	// or one of its ancestors.
+	i, _ := slices.BinarySearchFunc(s.nets, ip, func(n net.IPNet, ip net.IP) int {
+		if c := Compare(n.IP, ip); c != 0 {
+			return c
+		}
+		// Sort networks starting at ip before it, so i ends up after all of them.
+		return -1
+	})
+	for i--; i >= 0; i = int(s.parents[i]) {
+		if s.nets[i].Contains(ip) {
+			return s.nets[i], true
+		}
+	}
+	return net.IPNet{}, false
+}
+
+// Contains returns whether any network contains an IP address.
+func (s *SortedNets) Contains(ip net.IP) bool {
+	_, ok := s.Lookup(ip)
+	return ok
+}
+
+// Matches is the same as Contains, it implements Matcher.
+func (s *SortedNets) Matches(ip net.IP) bool {
+	return s.Contains(ip)
+}
+
+// sortedKey returns a normalized network, ok is false if it's invalid or has a non-contiguous mask.
+func sortedKey(n net.IPNet) (net.IPNet, bool) {
+	n = Normalize(n)
+	_, bits := n.Mask.Size()
+	return n, n.IP != nil && bits != 0
+}
+
+// link recomputes parents, networks are sorted so ancestors are on a stack of the networks seen so far.
+func (s *SortedNets) link() {
+	s.parents = slices.Grow(s.parents[:0], len(s.nets))[:len(s.nets)]
+	var stack []int32
+	for i, n := range s.nets {
+		for len(stack) > 0 && !Contains(s.nets[stack[len(stack)-1]], n) {
+			stack = stack[:len(stack)-1]
+		}
+		s.parents[i] = -1
+		if len(stack) > 0 {
+			s.parents[i] = stack[len(stack)-1]
+		}
+		stack = append(stack, int32(i))
+	}
+}
@@ -0,0 +1,92 @@
+package ipcalc
+
+import (
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// SortedNets implements Matcher.
+var _ Matcher = &SortedNets{}
+
+func TestSortedNets(t *testing.T) {
+	var nets []net.IPNet
+	for _, s := range []string{"192.0.2.128/25", "0.0.0.0/0", "192.0.2.0/24", "192.0.2.1/32", "2001:db8::/32", "192.0.2.0/24", "2001:db8:1::/48", "198.51.100.0/24"} {
+		nets = append(nets, mustCIDR(t, s))
+	}
+	nets = append(nets, net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.IPMask{255, 0, 255, 0}})
+	s := NewSortedNets(nets)
+	want := []string{"0.0.0.0/0", "192.0.2.0/24", "192.0.2.1/32", "192.0.2.128/25", "198.51.100.0/24", "2001:db8::/32", "2001:db8:1::/48"}
+	if got := netStrings(s.Nets()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Nets() = %v, want %v", got, want)
+	}
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"192.0.2.1", "192.0.2.1/32"},
+		{"192.0.2.0", "192.0.2.0/24"},
+		{"192.0.2.2", "192.0.2.0/24"},
+		{"192.0.2.200", "192.0.2.128/25"},
+		{"::ffff:198.51.100.255", "198.51.100.0/24"},
+		{"198.51.101.0", "0.0.0.0/0"},
+		{"2001:db8:1::1", "2001:db8:1::/48"},
+		{"2001:db8:2::1", "2001:db8::/32"},
+		{"2001:db9::", ""},
+		{"::1", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		n, ok := s.Lookup(net.ParseIP(tt.ip))
+		if ok != (tt.want != "") || ok && n.String() != tt.want {
+			t.Errorf("Lookup(%v) = %v, %v, want %v", tt.ip, &n, ok, tt.want)
+		}
+		if got := s.Contains(net.ParseIP(tt.ip)); got != ok {
+			t.Errorf("Contains(%v) = %v, want %v", tt.ip, got, ok)
+		}
+	}
+	if s.Insert(mustCIDR(t, "192.0.2.0/24")) {
+		t.Errorf("Insert(192.0.2.0/24) = true for a duplicate, want false")
+	}
+	if !s.Insert(mustCIDR(t, "2001:db9::/32")) || !s.Insert(mustCIDR(t, "192.0.2.0/30")) {
+		t.Errorf("Insert() = false for new networks, want true")
+	}
+	for ip, want := range map[string]string{"2001:db9::": "2001:db9::/32", "192.0.2.2": "192.0.2.0/30", "192.0.2.1": "192.0.2.1/32", "192.0.2.4": "192.0.2.0/24"} {
+		if n, ok := s.Lookup(net.ParseIP(ip)); !ok || n.String() != want {
+			t.Errorf("Lookup(%v) after Insert = %v, %v, want %v", ip, &n, ok, want)
+		}
+	}
+	if s.Len() != 9 {
+		t.Errorf("Len() = %v, want 9", s.Len())
+	}
+}
+
+func TestSortedNetsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var s SortedNets
+	var nets Nets
+	for i := 0; i < 300; i++ {
+		n := Normalize(net.IPNet{IP: Uint32ToIP(r.Uint32() >> 12 << 12), Mask: net.CIDRMask(4+r.Intn(17), 32)})
+		s.Insert(n)
+		nets = append(nets, n)
+	}
+	for i := 0; i < 2000; i++ {
+		ip := Uint32ToIP(r.Uint32())
+		var want net.IPNet
+		for _, n := range nets {
+			if ones, _ := n.Mask.Size(); n.Contains(ip) && (want.IP == nil || ones > mustOnes(want)) {
+				want = n
+			}
+		}
+		got, ok := s.Lookup(ip)
+		if ok != (want.IP != nil) || ok && got.String() != want.String() {
+			t.Fatalf("Lookup(%v) = %v, %v, want %v", ip, &got, ok, &want)
+		}
+	}
+}
+
+func mustOnes(n net.IPNet) int {
+	ones, _ := n.Mask.Size()
+	return ones
+}
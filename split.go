@@ -0,0 +1,138 @@
+package ipcalc
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"net"
+	"sort"
+)
+
+// MaxSplitBits is the maximum difference between prefix lengths when splitting a network, i.e., at most 2^MaxSplitBits subnets.
+const MaxSplitBits = 16
+
+// Split divides a network into equal subnets of the given prefix length, in ascending order.
+// e.g., Split(192.0.2.0/24, 26) -> [192.0.2.0/26 192.0.2.64/26 192.0.2.128/26 192.0.2.192/26].
+func Split(n net.IPNet, newPrefix int) ([]net.IPNet, error) {
+	first, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil, err
+	}
+	if newPrefix < ones || newPrefix > size {
+		return nil, fmt.Errorf("ipcalc: can't split %v into /%v subnets", &n, newPrefix)
+	}
+	if newPrefix-ones > MaxSplitBits {
+		return nil, fmt.Errorf("ipcalc: splitting %v into /%v subnets yields more than 2^%v subnets", &n, newPrefix, MaxSplitBits)
+	}
+	subnets := make([]net.IPNet, 1<<uint(newPrefix-ones))
+	subnets[0] = net.IPNet{IP: first, Mask: net.CIDRMask(newPrefix, size)}
+	for i := 1; i < len(subnets); i++ {
+		subnets[i] = NextSubnet(subnets[i-1])
+	}
+	return subnets, nil
+}
+
+// SplitN divides a network into the given number of equal subnets, which must be a power of two.
+// e.g., SplitN(192.0.2.0/24, 2) -> [192.0.2.0/25 192.0.2.128/25].
+func SplitN(n net.IPNet, parts int) ([]net.IPNet, error) {
+	if parts <= 0 || parts&(parts-1) != 0 {
+		return nil, fmt.Errorf("ipcalc: can't split %v into %v equal subnets", &n, parts)
+	}
+	_, ones, _, err := splitBase(n)
+	if err != nil {
+		return nil, err
+	}
+	return Split(n, ones+bits.TrailingZeros(uint(parts)))
+}
+
+// SplitByHosts carves subnets out of a network, each with room for the corresponding number of hosts.
+// IPv4 subnets reserve the network and broadcast addresses, IPv6 subnets reserve none.
+// Subnets are returned in the same order as hostCounts, the largest ones are allocated first so they stay aligned.
+// e.g., SplitByHosts(192.0.2.0/24, [50, 100]) -> [192.0.2.128/26 192.0.2.0/25].
+func SplitByHosts(n net.IPNet, hostCounts []int) ([]net.IPNet, error) {
+	first, ones, size, err := splitBase(n)
+	if err != nil {
+		return nil, err
+	}
+	reserved := ReserveNone
+	if size == 8*net.IPv4len {
+		reserved = ReserveNetworkBroadcast
+	}
+	hostBits := make([]int, len(hostCounts))
+	order := make([]int, len(hostCounts))
+	for i, c := range hostCounts {
+		if c <= 0 {
+			return nil, fmt.Errorf("ipcalc: invalid host count %v", c)
+		}
+		hostBits[i] = bits.Len(uint(c + reserved.Head + reserved.Tail - 1))
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return hostBits[order[i]] > hostBits[order[j]]
+	})
+	subnets := make([]net.IPNet, len(hostCounts))
+	offset := new(big.Int)
+	avail := new(big.Int).Lsh(big.NewInt(1), uint(size-ones))
+	for _, i := range order {
+		if hostBits[i] > size-ones {
+			return nil, fmt.Errorf("ipcalc: %v can't fit %v hosts", &n, hostCounts[i])
+		}
+		subnets[i] = net.IPNet{IP: AddBig(first, offset), Mask: net.CIDRMask(size-hostBits[i], size)}
+		if offset.Add(offset, new(big.Int).Lsh(big.NewInt(1), uint(hostBits[i]))).Cmp(avail) > 0 {
+			return nil, fmt.Errorf("ipcalc: %v can't fit %v hosts", &n, hostCounts)
+		}
+	}
+	return subnets, nil
+}
+
+// NthSubnet returns the subnet at the given zero-based index when dividing a network into subnets of the given prefix length.
+// e.g., NthSubnet(2001:db8::/48, 64, 100) -> 2001:db8:0:64::/64.
+func NthSubnet(parent net.IPNet, newPrefix, index int) (net.IPNet, error) {
+	first, ones, size, err := splitBase(parent)
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	if newPrefix < ones || newPrefix > size {
+		return net.IPNet{}, fmt.Errorf("ipcalc: can't split %v into /%v subnets", &parent, newPrefix)
+	}
+	i := big.NewInt(int64(index))
+	if index < 0 || i.BitLen() > newPrefix-ones {
+		return net.IPNet{}, fmt.Errorf("ipcalc: %v has no /%v subnet with index %v", &parent, newPrefix, index)
+	}
+	return net.IPNet{IP: AddBig(first, i.Lsh(i, uint(size-newPrefix))), Mask: net.CIDRMask(newPrefix, size)}, nil
+}
+
+// SubnetIndex returns the zero-based index of a subnet within a network, the inverse of NthSubnet.
+// e.g., SubnetIndex(2001:db8::/48, 2001:db8:0:64::/64) -> 100.
+func SubnetIndex(parent, child net.IPNet) (int, error) {
+	first, ones, size, err := splitBase(parent)
+	if err != nil {
+		return 0, err
+	}
+	childFirst, childOnes, childSize, err := splitBase(child)
+	if err != nil {
+		return 0, err
+	}
+	if childSize != size || childOnes < ones || !parent.Contains(childFirst) {
+		return 0, fmt.Errorf("ipcalc: %v not in %v", &child, &parent)
+	}
+	i := Delta(first, childFirst)
+	if i.Rsh(i, uint(size-childOnes)); !i.IsInt64() || int64(int(i.Int64())) != i.Int64() {
+		return 0, fmt.Errorf("ipcalc: index of %v in %v overflows int", &child, &parent)
+	}
+	return int(i.Int64()), nil
+}
+
+// splitBase returns the network address, prefix length and size in bits of a network with a CIDR mask.
+// IPv4-mapped IPv6 networks are handled as IPv4 networks, e.g., ::ffff:192.0.2.0/120 -> 192.0.2.0, 24, 32.
+func splitBase(n net.IPNet) (net.IP, int, int, error) {
+	ip, mask, ok := normalizeNet(n)
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("ipcalc: invalid network %v", &n)
+	}
+	ones, size := mask.Size()
+	if size == 0 {
+		return nil, 0, 0, fmt.Errorf("ipcalc: invalid network %v", &n)
+	}
+	return ip.Mask(mask), ones, size, nil
+}
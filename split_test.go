@@ -0,0 +1,178 @@
+package ipcalc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func netStrings(nets []net.IPNet) []string {
+	var s []string
+	for _, n := range nets {
+		s = append(s, n.String())
+	}
+	return s
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		n         string
+		newPrefix int
+		want      []string
+	}{
+		{"192.0.2.0/24", 26, []string{"192.0.2.0/26", "192.0.2.64/26", "192.0.2.128/26", "192.0.2.192/26"}},
+		{"192.0.2.77/24", 24, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/30", 32, []string{"192.0.2.0/32", "192.0.2.1/32", "192.0.2.2/32", "192.0.2.3/32"}},
+		{"255.255.255.0/24", 25, []string{"255.255.255.0/25", "255.255.255.128/25"}},
+		{"::ffff:192.0.2.0/120", 25, []string{"192.0.2.0/25", "192.0.2.128/25"}},
+		{"2001:db8::/32", 34, []string{"2001:db8::/34", "2001:db8:4000::/34", "2001:db8:8000::/34", "2001:db8:c000::/34"}},
+		{"192.0.2.0/24", 23, nil},
+		{"192.0.2.0/24", 33, nil},
+		{"10.0.0.0/8", 8 + MaxSplitBits + 1, nil},
+		{"2001:db8::/32", 64, nil},
+	}
+	for _, tt := range tests {
+		nets, err := Split(mustCIDR(t, tt.n), tt.newPrefix)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("Split(%v, %v) = %v, want error", tt.n, tt.newPrefix, netStrings(nets))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Split(%v, %v) error = %v", tt.n, tt.newPrefix, err)
+			continue
+		}
+		if got := netStrings(nets); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%v, %v) = %v, want %v", tt.n, tt.newPrefix, got, tt.want)
+		}
+	}
+	if got, err := Split(mustCIDR(t, "10.0.0.0/8"), 8+MaxSplitBits); err != nil || len(got) != 1<<MaxSplitBits {
+		t.Errorf("Split(10.0.0.0/8, %v) = %v subnets, %v, want %v subnets", 8+MaxSplitBits, len(got), err, 1<<MaxSplitBits)
+	}
+	bad := net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}
+	if got, err := Split(bad, 25); err == nil {
+		t.Errorf("Split(%v, 25) = %v, want error", &bad, netStrings(got))
+	}
+}
+
+func TestSplitN(t *testing.T) {
+	tests := []struct {
+		n     string
+		parts int
+		want  []string
+	}{
+		{"192.0.2.0/24", 1, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/24", 2, []string{"192.0.2.0/25", "192.0.2.128/25"}},
+		{"2001:db8::/127", 2, []string{"2001:db8::/128", "2001:db8::1/128"}},
+		{"192.0.2.0/24", 0, nil},
+		{"192.0.2.0/24", -2, nil},
+		{"192.0.2.0/24", 3, nil},
+		{"192.0.2.0/31", 4, nil},
+	}
+	for _, tt := range tests {
+		nets, err := SplitN(mustCIDR(t, tt.n), tt.parts)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("SplitN(%v, %v) = %v, want error", tt.n, tt.parts, netStrings(nets))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitN(%v, %v) error = %v", tt.n, tt.parts, err)
+			continue
+		}
+		if got := netStrings(nets); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitN(%v, %v) = %v, want %v", tt.n, tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestSplitByHosts(t *testing.T) {
+	tests := []struct {
+		n          string
+		hostCounts []int
+		want       []string
+	}{
+		{"192.0.2.0/24", nil, []string{}},
+		{"192.0.2.0/24", []int{50, 100}, []string{"192.0.2.128/26", "192.0.2.0/25"}},
+		{"192.0.2.0/24", []int{1, 2, 3, 62}, []string{"192.0.2.72/30", "192.0.2.76/30", "192.0.2.64/29", "192.0.2.0/26"}},
+		{"192.0.2.0/24", []int{126, 126}, []string{"192.0.2.0/25", "192.0.2.128/25"}},
+		{"192.0.2.0/24", []int{254}, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/24", []int{255}, nil},
+		{"192.0.2.0/24", []int{126, 127}, nil},
+		{"192.0.2.0/24", []int{100, 100, 1}, nil},
+		{"192.0.2.0/24", []int{0}, nil},
+		{"2001:db8::/64", []int{1, 256, 2}, []string{"2001:db8::102/128", "2001:db8::/120", "2001:db8::100/127"}},
+		{"2001:db8::/126", []int{4}, []string{"2001:db8::/126"}},
+	}
+	for _, tt := range tests {
+		nets, err := SplitByHosts(mustCIDR(t, tt.n), tt.hostCounts)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("SplitByHosts(%v, %v) = %v, want error", tt.n, tt.hostCounts, netStrings(nets))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitByHosts(%v, %v) error = %v", tt.n, tt.hostCounts, err)
+			continue
+		}
+		if got := netStrings(nets); len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitByHosts(%v, %v) = %v, want %v", tt.n, tt.hostCounts, got, tt.want)
+		}
+	}
+}
+
+func TestNthSubnet(t *testing.T) {
+	tests := []struct {
+		parent    string
+		newPrefix int
+		index     int
+		want      string
+	}{
+		{"2001:db8::/48", 64, 0, "2001:db8::/64"},
+		{"2001:db8::/48", 64, 100, "2001:db8:0:64::/64"},
+		{"2001:db8::/48", 64, 65535, "2001:db8:0:ffff::/64"},
+		{"2001:db8::/32", 96, 1 << 40, "2001:db8:0:100::/96"},
+		{"192.0.2.0/24", 26, 3, "192.0.2.192/26"},
+		{"192.0.2.0/24", 24, 0, "192.0.2.0/24"},
+		{"192.0.2.0/24", 26, 4, ""},
+		{"192.0.2.0/24", 26, -1, ""},
+		{"192.0.2.0/24", 24, 1, ""},
+		{"192.0.2.0/24", 23, 0, ""},
+		{"192.0.2.0/24", 33, 0, ""},
+	}
+	for _, tt := range tests {
+		got, err := NthSubnet(mustCIDR(t, tt.parent), tt.newPrefix, tt.index)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("NthSubnet(%v, %v, %v) = %v, want error", tt.parent, tt.newPrefix, tt.index, &got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("NthSubnet(%v, %v, %v) = %v, %v, want %v", tt.parent, tt.newPrefix, tt.index, &got, err, tt.want)
+			continue
+		}
+		if i, err := SubnetIndex(mustCIDR(t, tt.parent), got); err != nil || i != tt.index {
+			t.Errorf("SubnetIndex(%v, %v) = %v, %v, want %v", tt.parent, &got, i, err, tt.index)
+		}
+	}
+}
+
+func TestSubnetIndexErrors(t *testing.T) {
+	tests := []struct {
+		parent, child string
+	}{
+		{"192.0.2.0/24", "192.0.3.0/26"},
+		{"192.0.2.0/24", "192.0.0.0/16"},
+		{"192.0.2.0/24", "2001:db8::/64"},
+		{"::/0", "ffff::/128"},
+	}
+	for _, tt := range tests {
+		if got, err := SubnetIndex(mustCIDR(t, tt.parent), mustCIDR(t, tt.child)); err == nil {
+			t.Errorf("SubnetIndex(%v, %v) = %v, want error", tt.parent, tt.child, got)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package srv6 provides utilities for working with SRv6 SID structures (RFC 8986).
+//
+// A SID is an IPv6 address made up of the following fields, most significant first:
+//   - Locator Block, identifying the SRv6 domain
+//   - Locator Node, identifying a node within the block
+//   - Function, identifying a behavior bound to the node
+//   - Argument, optional per-packet data for the behavior
+//
+// Any bits following the Argument must be zero.
+// Examples below, format is Block/Node/Function/Argument lengths:
+//   - 32/16/16/0 splits 2001:db8:100:e001:: into 2001:db8, 0x100, 0xe001
+//   - 48/16/16/16 splits fc00:0:1:2:e000:7:: into fc00:0:1, 0x2, 0xe000, 0x7
+package srv6
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// maxFieldLen is the maximum length of any single SID field, in bits.
+const maxFieldLen = 64
+
+// ErrNotIPv6 is returned when decomposing an address which isn't IPv6.
+var ErrNotIPv6 = errors.New("srv6: not an IPv6 address")
+
+// Structure describes the bit layout of a SID, field lengths are in bits.
+type Structure struct {
+	BlockLen    int
+	NodeLen     int
+	FunctionLen int
+	ArgumentLen int
+}
+
+// SID holds the fields of a SID, right-aligned.
+type SID struct {
+	Block    uint64
+	Node     uint64
+	Function uint64
+	Argument uint64
+}
+
+// Len returns the total number of bits used by the Structure.
+func (s Structure) Len() int {
+	return s.BlockLen + s.NodeLen + s.FunctionLen + s.ArgumentLen
+}
+
+// Validate returns an error if the Structure can't describe an IPv6 SID,
+// i.e., any field is negative or longer than 64 bits, or all fields don't fit in 128 bits.
+func (s Structure) Validate() error {
+	for _, f := range s.fields() {
+		if f.len < 0 || f.len > maxFieldLen {
+			return fmt.Errorf("srv6: %v length %v out of range [0, %v]", f.name, f.len, maxFieldLen)
+		}
+	}
+	if s.BlockLen == 0 {
+		return errors.New("srv6: Locator Block length must not be zero")
+	}
+	if l := s.Len(); l > 8*net.IPv6len {
+		return fmt.Errorf("srv6: structure length %v exceeds %v bits", l, 8*net.IPv6len)
+	}
+	return nil
+}
+
+// Locator returns the Locator prefix (Block and Node) of a SID.
+// e.g., Structure{32, 16, 16, 0}.Locator(2001:db8:100:e001::) -> 2001:db8:100::/48.
+func (s Structure) Locator(sid net.IP) net.IPNet {
+	mask := net.CIDRMask(s.BlockLen+s.NodeLen, 8*net.IPv6len)
+	return net.IPNet{
+		IP:   sid.To16().Mask(mask),
+		Mask: mask,
+	}
+}
+
+// Decompose splits a SID into its fields.
+// It returns an error if the Structure is invalid, the address isn't IPv6, or any bits past the Argument are set.
+func (s Structure) Decompose(sid net.IP) (SID, error) {
+	if err := s.Validate(); err != nil {
+		return SID{}, err
+	}
+	if len(sid) != net.IPv6len || sid.To4() != nil {
+		return SID{}, ErrNotIPv6
+	}
+	var f SID
+	off := 0
+	for _, fl := range s.fields() {
+		*fl.value(&f) = getBits(sid, off, fl.len)
+		off += fl.len
+	}
+	for ; off < 8*net.IPv6len; off++ {
+		if getBits(sid, off, 1) != 0 {
+			return SID{}, fmt.Errorf("srv6: %v has non-zero bits past the Argument", sid)
+		}
+	}
+	return f, nil
+}
+
+// Compose builds a SID from its fields.
+// It returns an error if the Structure is invalid or any field value doesn't fit in its configured length.
+func (s Structure) Compose(f SID) (net.IP, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	sid := make(net.IP, net.IPv6len)
+	off := 0
+	for _, fl := range s.fields() {
+		v := *fl.value(&f)
+		if fl.len < maxFieldLen && v>>uint(fl.len) != 0 {
+			return nil, fmt.Errorf("srv6: %v %#x does not fit in %v bits", fl.name, v, fl.len)
+		}
+		setBits(sid, off, fl.len, v)
+		off += fl.len
+	}
+	return sid, nil
+}
+
+type field struct {
+	name  string
+	len   int
+	value func(*SID) *uint64
+}
+
+// fields returns the SID fields in the order they appear in the address.
+func (s Structure) fields() []field {
+	return []field{
+		{"Locator Block", s.BlockLen, func(f *SID) *uint64 { return &f.Block }},
+		{"Locator Node", s.NodeLen, func(f *SID) *uint64 { return &f.Node }},
+		{"Function", s.FunctionLen, func(f *SID) *uint64 { return &f.Function }},
+		{"Argument", s.ArgumentLen, func(f *SID) *uint64 { return &f.Argument }},
+	}
+}
+
+// getBits returns n bits from ip starting at bit offset off (0 is the most significant bit).
+func getBits(ip net.IP, off, n int) uint64 {
+	var v uint64
+	for i := off; i < off+n; i++ {
+		v = v<<1 | uint64(ip[i/8]>>uint(7-i%8)&1)
+	}
+	return v
+}
+
+// setBits stores the low n bits of v into ip starting at bit offset off (0 is the most significant bit).
+func setBits(ip net.IP, off, n int, v uint64) {
+	for i := off + n - 1; i >= off; i-- {
+		if v&1 == 1 {
+			ip[i/8] |= 1 << uint(7-i%8)
+		} else {
+			ip[i/8] &^= 1 << uint(7-i%8)
+		}
+		v >>= 1
+	}
+}
@@ -0,0 +1,94 @@
+package srv6
+
+import (
+	"net"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		s  Structure
+		ok bool
+	}{
+		{Structure{32, 16, 16, 0}, true},
+		{Structure{48, 16, 16, 48}, true},
+		{Structure{64, 64, 0, 0}, true},
+		{Structure{0, 16, 16, 0}, false},
+		{Structure{32, -1, 16, 0}, false},
+		{Structure{65, 16, 16, 0}, false},
+		{Structure{64, 32, 32, 8}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.s.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%+v.Validate() error = %v", tt.s, err)
+		}
+	}
+}
+
+func TestLocator(t *testing.T) {
+	s := Structure{32, 16, 16, 0}
+	if got, want := s.Locator(net.ParseIP("2001:db8:100:e001::")), "2001:db8:100::/48"; got.String() != want {
+		t.Errorf("Locator() = %v, want %v", got.String(), want)
+	}
+}
+
+func TestDecomposeCompose(t *testing.T) {
+	tests := []struct {
+		s   Structure
+		sid string
+		f   SID
+	}{
+		{Structure{32, 16, 16, 0}, "2001:db8:100:e001::", SID{0x20010db8, 0x100, 0xe001, 0}},
+		{Structure{48, 16, 16, 16}, "fc00:0:1:2:e000:7::", SID{0xfc0000000001, 0x2, 0xe000, 0x7}},
+		{Structure{40, 24, 12, 20}, "2001:db8:ab12:3456:789a:bcde::", SID{0x20010db8ab, 0x123456, 0x789, 0xabcde}},
+		{Structure{64, 64, 0, 0}, "ffff:ffff:ffff:ffff:1:2:3:4", SID{0xffffffffffffffff, 0x0001000200030004, 0, 0}},
+	}
+	for _, tt := range tests {
+		got, err := tt.s.Decompose(net.ParseIP(tt.sid))
+		if err != nil {
+			t.Errorf("%+v.Decompose(%v) error = %v", tt.s, tt.sid, err)
+		} else if got != tt.f {
+			t.Errorf("%+v.Decompose(%v) = %+x, want %+x", tt.s, tt.sid, got, tt.f)
+		}
+		ip, err := tt.s.Compose(tt.f)
+		if err != nil {
+			t.Errorf("%+v.Compose(%+x) error = %v", tt.s, tt.f, err)
+		} else if !ip.Equal(net.ParseIP(tt.sid)) {
+			t.Errorf("%+v.Compose(%+x) = %v, want %v", tt.s, tt.f, ip, tt.sid)
+		}
+	}
+}
+
+func TestDecomposeErrors(t *testing.T) {
+	tests := []struct {
+		s   Structure
+		sid net.IP
+	}{
+		{Structure{32, 16, 16, 0}, net.ParseIP("2001:db8:100:e001::1")},
+		{Structure{32, 16, 16, 0}, net.ParseIP("192.0.2.1")},
+		{Structure{32, 16, 16, 0}, nil},
+		{Structure{0, 16, 16, 0}, net.ParseIP("2001:db8::")},
+	}
+	for _, tt := range tests {
+		if _, err := tt.s.Decompose(tt.sid); err == nil {
+			t.Errorf("%+v.Decompose(%v) error = nil", tt.s, tt.sid)
+		}
+	}
+}
+
+func TestComposeErrors(t *testing.T) {
+	tests := []struct {
+		s Structure
+		f SID
+	}{
+		{Structure{32, 16, 16, 0}, SID{0x1ffffffff, 0, 0, 0}},
+		{Structure{32, 16, 16, 0}, SID{0, 0x10000, 0, 0}},
+		{Structure{32, 16, 16, 0}, SID{0, 0, 0, 1}},
+		{Structure{96, 16, 16, 0}, SID{}},
+	}
+	for _, tt := range tests {
+		if _, err := tt.s.Compose(tt.f); err == nil {
+			t.Errorf("%+v.Compose(%+x) error = nil", tt.s, tt.f)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package ipcalc
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+)
+
+// Report summarizes a list of IP addresses within an enclosing network.
+type Report struct {
+	// Min and Max are the lowest and highest addresses, nil if the list is empty.
+	Min net.IP
+	Max net.IP
+	// Count is the number of distinct addresses.
+	Count int
+	// Coverage is the fraction of the enclosing network's addresses in the list.
+	Coverage float64
+	// Gaps are the largest ranges of the enclosing network not in the list, largest first.
+	Gaps []Range
+}
+
+// Stats returns a Report for a list of IP addresses within an enclosing network,
+// keeping at most maxGaps gaps, ties are broken by address.
+// The list needn't be sorted and may contain duplicates, it returns an error if any address is outside n
+// or maxGaps is negative.
+// e.g., Stats([192.0.2.1 192.0.2.2 192.0.2.10], 192.0.2.0/28, 2) -> Min 192.0.2.1, Max 192.0.2.10, Count 3,
+// Coverage 0.1875, Gaps [192.0.2.3-192.0.2.9 192.0.2.11-192.0.2.15].
+func Stats(ips []net.IP, n net.IPNet, maxGaps int) (Report, error) {
+	if maxGaps < 0 {
+		return Report{}, fmt.Errorf("ipcalc: negative maxGaps %v", maxGaps)
+	}
+	ones, bits := n.Mask.Size()
+	if bits == 0 || bits != 8*IPSize(n.IP) {
+		return Report{}, fmt.Errorf("ipcalc: invalid network %v", &n)
+	}
+	for _, ip := range ips {
+		if !n.Contains(ip) {
+			return Report{}, fmt.Errorf("ipcalc: %v not in %v", ip, &n)
+		}
+	}
+	var r Report
+	ranges := CollapseIPs(ips)
+	count := new(big.Int)
+	for _, x := range ranges {
+		count.Add(count, x.Len())
+	}
+	r.Count = int(count.Int64())
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	r.Coverage, _ = new(big.Rat).SetFrac(count, size).Float64()
+
+	first := IP(n.IP.Mask(n.Mask))
+	last := Broadcast(net.IPNet{IP: first, Mask: n.Mask})
+	var gaps []Range
+	next := first
+	for _, x := range ranges {
+		if !x.First.Equal(next) {
+			gaps = append(gaps, Range{next, PrevIP(x.First)})
+		}
+		next = NextIP(x.Last)
+	}
+	if len(ranges) == 0 {
+		gaps = append(gaps, Range{first, last})
+	} else {
+		r.Min = ranges[0].First
+		r.Max = ranges[len(ranges)-1].Last
+		if !r.Max.Equal(last) {
+			gaps = append(gaps, Range{next, last})
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gaps[i].Len().Cmp(gaps[j].Len()) > 0
+	})
+	if len(gaps) > maxGaps {
+		gaps = gaps[:maxGaps]
+	}
+	r.Gaps = gaps
+	return r, nil
+}
@@ -0,0 +1,67 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		ips      []string
+		n        string
+		maxGaps  int
+		min      string
+		max      string
+		count    int
+		coverage float64
+		gaps     []string
+	}{
+		{
+			[]string{"192.0.2.10", "192.0.2.1", "192.0.2.2", "192.0.2.2"}, "192.0.2.0/28", 2,
+			"192.0.2.1", "192.0.2.10", 3, 0.1875,
+			[]string{"192.0.2.3-192.0.2.9", "192.0.2.11-192.0.2.15"},
+		},
+		{
+			[]string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3"}, "192.0.2.0/30", 5,
+			"192.0.2.0", "192.0.2.3", 4, 1, nil,
+		},
+		{
+			nil, "192.0.2.0/30", 5,
+			"<nil>", "<nil>", 0, 0, []string{"192.0.2.0-192.0.2.3"},
+		},
+		{
+			[]string{"2001:db8::1", "2001:db8::3"}, "2001:db8::/126", 10,
+			"2001:db8::1", "2001:db8::3", 2, 0.5, []string{"2001:db8::-2001:db8::", "2001:db8::2-2001:db8::2"},
+		},
+		{
+			[]string{"2001:db8::8"}, "2001:db8::/64", 0,
+			"2001:db8::8", "2001:db8::8", 1, 1.0 / (1 << 64), nil,
+		},
+	}
+	for _, tt := range tests {
+		var ips []net.IP
+		for _, ip := range tt.ips {
+			ips = append(ips, net.ParseIP(ip))
+		}
+		r, err := Stats(ips, mustCIDR(t, tt.n), tt.maxGaps)
+		if err != nil {
+			t.Errorf("Stats(%v, %v) error = %v", tt.ips, tt.n, err)
+			continue
+		}
+		if r.Min.String() != tt.min || r.Max.String() != tt.max || r.Count != tt.count || r.Coverage != tt.coverage || len(r.Gaps) != len(tt.gaps) {
+			t.Errorf("Stats(%v, %v) = %+v, want %v %v %v %v %v", tt.ips, tt.n, r, tt.min, tt.max, tt.count, tt.coverage, tt.gaps)
+			continue
+		}
+		for i, g := range r.Gaps {
+			if g.String() != tt.gaps[i] {
+				t.Errorf("Stats(%v, %v).Gaps[%v] = %v, want %v", tt.ips, tt.n, i, g, tt.gaps[i])
+			}
+		}
+	}
+	if _, err := Stats([]net.IP{net.ParseIP("192.0.3.1")}, mustCIDR(t, "192.0.2.0/24"), 1); err == nil {
+		t.Errorf("Stats(192.0.3.1, 192.0.2.0/24) error = nil")
+	}
+	if _, err := Stats([]net.IP{net.ParseIP("192.0.2.1")}, mustCIDR(t, "192.0.2.0/24"), -1); err == nil {
+		t.Errorf("Stats(192.0.2.1, 192.0.2.0/24, -1) error = nil")
+	}
+}
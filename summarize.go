@@ -0,0 +1,422 @@
+package ipcalc
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+	"net"
+	"slices"
+
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// Summarize returns the minimal list of networks covering exactly the same addresses as the input, in ascending order.
+// Overlapping and covered networks are removed and adjacent ones are merged,
+// invalid networks, including those with non-contiguous masks, are ignored.
+// e.g., Summarize(192.0.2.0/25, 192.0.2.128/25, 192.0.2.64/26) -> [192.0.2.0/24].
+func Summarize(nets []net.IPNet) []net.IPNet {
+	ranges := make([]Range, 0, len(nets))
+	for _, n := range nets {
+		if _, bits := n.Mask.Size(); bits == 0 {
+			// Non-contiguous masks, e.g., 255.0.255.0, don't describe a range of addresses.
+			continue
+		}
+		if r := CIDRToRange(n); r.First != nil {
+			ranges = append(ranges, r)
+		}
+	}
+	var out []net.IPNet
+	for _, r := range mergeRanges(ranges) {
+		out = append(out, RangeToCIDRs(r)...)
+	}
+	return out
+}
+
+// AggregateWithSlack is like Summarize but may cover up to maxExtra addresses not in the input to return fewer networks.
+// Networks are greedily replaced by the supernet of two neighbors which adds the fewest extra addresses,
+// until no such supernet fits in the remaining budget.
+// Candidate supernets are kept in a heap keyed by extra addresses, so each merge only updates the supernets around it.
+// e.g., AggregateWithSlack([192.0.2.0/25, 192.0.3.0/24], 128) -> [192.0.2.0/23].
+func AggregateWithSlack(nets []net.IPNet, maxExtra *big.Int) []net.IPNet {
+	out := Summarize(nets)
+	if maxExtra != nil && maxExtra.Sign() < 0 {
+		return out
+	}
+	budget := uint128.Zero
+	if maxExtra != nil {
+		budget = saturate(maxExtra)
+	}
+	a := newSlackAggregator(out)
+	for a.h.Len() > 0 {
+		m := heap.Pop(&a.h).(*slackMerge)
+		delete(a.merges, m.super)
+		if m.a.removed {
+			// The supernet is inside an earlier merge.
+			continue
+		}
+		if m.cost.Cmp(budget) > 0 {
+			break
+		}
+		budget = budget.Sub(m.cost)
+		a.merge(m)
+	}
+	return a.nets()
+}
+
+// saturate returns a non-negative big.Int as a Uint128, or uint128.Max if it doesn't fit.
+func saturate(v *big.Int) uint128.Uint128 {
+	if v.BitLen() > 128 {
+		return uint128.Max
+	}
+	b := v.FillBytes(make([]byte, 16))
+	return uint128.Uint128{Hi: binary.BigEndian.Uint64(b[:8]), Lo: binary.BigEndian.Uint64(b[8:])}
+}
+
+// slackKey identifies a network by its first address, prefix length and address size in bytes.
+type slackKey struct {
+	lo   uint128.Uint128
+	ones int
+	size int
+}
+
+// last returns the last address of the network.
+func (k slackKey) last() uint128.Uint128 {
+	return k.lo.Or(uint128.One.ShiftLeft(uint(8*k.size - k.ones)).Sub(uint128.One))
+}
+
+// slackNode is a network being aggregated, linked to its neighbors in ascending order.
+type slackNode struct {
+	key        slackKey
+	hi         uint128.Uint128
+	prev, next *slackNode
+	removed    bool
+}
+
+// slackMerge is a candidate supernet covering node a and its neighbors, cost is the number of extra addresses.
+type slackMerge struct {
+	super slackKey
+	a     *slackNode
+	cost  uint128.Uint128
+	index int
+}
+
+// slackHeap orders candidate merges by cost, then by address.
+type slackHeap []*slackMerge
+
+func (h slackHeap) Len() int { return len(h) }
+
+func (h slackHeap) Less(i, j int) bool {
+	if c := h[i].cost.Cmp(h[j].cost); c != 0 {
+		return c < 0
+	}
+	if h[i].super.size != h[j].super.size {
+		return h[i].super.size < h[j].super.size
+	}
+	return h[i].super.lo.Cmp(h[j].super.lo) < 0
+}
+
+func (h slackHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *slackHeap) Push(x any) {
+	m := x.(*slackMerge)
+	m.index = len(*h)
+	*h = append(*h, m)
+}
+
+func (h *slackHeap) Pop() any {
+	old := *h
+	m := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return m
+}
+
+// slackAggregator merges a sorted list of disjoint networks, see AggregateWithSlack.
+type slackAggregator struct {
+	head   *slackNode
+	h      slackHeap
+	merges map[slackKey]*slackMerge
+}
+
+func newSlackAggregator(nets []net.IPNet) *slackAggregator {
+	a := &slackAggregator{merges: make(map[slackKey]*slackMerge)}
+	var prev *slackNode
+	for _, n := range nets {
+		ip := IP(n.IP)
+		lo, _ := uint128.FromIP(ip)
+		ones, _ := n.Mask.Size()
+		x := &slackNode{key: slackKey{lo, ones, len(ip)}, prev: prev}
+		x.hi = x.key.last()
+		if prev == nil {
+			a.head = x
+		} else {
+			prev.next = x
+		}
+		prev = x
+	}
+	for x := a.head; x != nil; x = x.next {
+		a.add(x)
+	}
+	return a
+}
+
+// add adds the supernet of a node and its next neighbor as a candidate, unless it's already one.
+func (a *slackAggregator) add(x *slackNode) {
+	y := x.next
+	if y == nil || y.key.size != x.key.size {
+		return
+	}
+	total := 8 * x.key.size
+	ones := min(x.key.lo.Xor(y.hi).LeadingZeros()-(128-total), total)
+	host := uint128.One.ShiftLeft(uint(total - ones)).Sub(uint128.One)
+	super := slackKey{x.key.lo.And(host.Not()), ones, x.key.size}
+	if _, ok := a.merges[super]; ok {
+		return
+	}
+	// The cost is the supernet size minus the size of every network it covers, computed without overflowing.
+	first, last := covered(super, x)
+	cost := super.last().Sub(super.lo)
+	for n := first; ; n = n.next {
+		cost = cost.Sub(n.hi.Sub(n.key.lo))
+		if n == last {
+			break
+		}
+		cost = cost.Sub(uint128.One)
+	}
+	m := &slackMerge{super: super, a: x, cost: cost}
+	a.merges[super] = m
+	heap.Push(&a.h, m)
+}
+
+// merge replaces the networks covered by a candidate supernet with it.
+func (a *slackAggregator) merge(m *slackMerge) {
+	first, last := covered(m.super, m.a)
+	x := &slackNode{key: m.super, hi: m.super.last(), prev: first.prev, next: last.next}
+	for n := first; n != last.next; n = n.next {
+		n.removed = true
+	}
+	if x.prev == nil {
+		a.head = x
+	} else {
+		x.prev.next = x
+	}
+	if x.next != nil {
+		x.next.prev = x
+	}
+	// Every candidate containing x now covers m.cost more addresses.
+	for ones := m.super.ones - 1; ones >= 0; ones-- {
+		k := slackKey{m.super.lo.And(uint128.One.ShiftLeft(uint(8*m.super.size - ones)).Sub(uint128.One).Not()), ones, m.super.size}
+		if c, ok := a.merges[k]; ok {
+			c.a, c.cost = x, c.cost.Sub(m.cost)
+			heap.Fix(&a.h, c.index)
+		}
+	}
+	if x.prev != nil {
+		a.add(x.prev)
+	}
+	a.add(x)
+}
+
+// covered returns the first and last networks covered by a supernet which covers node x.
+// Networks are sorted and disjoint, so the ones covered by the supernet are contiguous.
+func covered(super slackKey, x *slackNode) (*slackNode, *slackNode) {
+	first, last := x, x
+	for first.prev != nil && first.prev.key.size == super.size && first.prev.key.lo.Cmp(super.lo) >= 0 {
+		first = first.prev
+	}
+	for hi := super.last(); last.next != nil && last.next.key.size == super.size && last.next.hi.Cmp(hi) <= 0; {
+		last = last.next
+	}
+	return first, last
+}
+
+// nets returns the remaining networks in ascending order.
+func (a *slackAggregator) nets() []net.IPNet {
+	var out []net.IPNet
+	for x := a.head; x != nil; x = x.next {
+		out = append(out, net.IPNet{IP: x.key.lo.IP(x.key.size), Mask: net.CIDRMask(x.key.ones, 8*x.key.size)})
+	}
+	return out
+}
+
+// Exclude returns the minimal list of networks covering outer minus the excluded networks, in ascending order.
+// Excluded networks needn't be inside outer, only the part overlapping it is removed.
+// e.g., Exclude(192.0.2.0/24, 192.0.2.64/26) -> [192.0.2.0/26 192.0.2.128/25].
+func Exclude(outer net.IPNet, inner ...net.IPNet) []net.IPNet {
+	o := CIDRToRange(outer)
+	if o.First == nil {
+		return nil
+	}
+	var ranges []Range
+	for _, n := range inner {
+		if r := CIDRToRange(n); r.First != nil && o.Overlaps(r) {
+			ranges = append(ranges, r)
+		}
+	}
+	var out []net.IPNet
+	next := o.First
+	for _, r := range mergeRanges(ranges) {
+		if Compare(r.First, next) > 0 {
+			out = append(out, RangeToCIDRs(Range{First: next, Last: PrevIP(r.First)})...)
+		}
+		if Compare(r.Last, o.Last) >= 0 {
+			return out
+		}
+		next = NextIP(r.Last)
+	}
+	return append(out, RangeToCIDRs(Range{First: next, Last: o.Last})...)
+}
+
+// Gaps returns the minimal list of networks inside parent not covered by any of the given networks, in ascending order.
+// It's the same as Exclude, e.g., finding the free space of a pool given its allocations.
+// e.g., Gaps(10.0.0.0/8, [10.0.0.0/9 10.192.0.0/10]) -> [10.128.0.0/10].
+func Gaps(parent net.IPNet, nets []net.IPNet) []net.IPNet {
+	return Exclude(parent, nets...)
+}
+
+// Covers returns whether every address in parent is in at least one of the given networks, false if parent is invalid.
+// e.g., Covers(192.0.2.0/24, [192.0.2.0/25 192.0.2.128/25]) -> true.
+func Covers(parent net.IPNet, nets []net.IPNet) bool {
+	p := CIDRToRange(parent)
+	if p.First == nil {
+		return false
+	}
+	var ranges []Range
+	for _, n := range nets {
+		if r := CIDRToRange(n); r.First != nil && p.Overlaps(r) {
+			ranges = append(ranges, r)
+		}
+	}
+	// Merged ranges are disjoint and not adjacent, so parent must be within a single one.
+	for _, r := range mergeRanges(ranges) {
+		if Compare(r.First, p.First) <= 0 && Compare(r.Last, p.Last) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Supernet returns the smallest network containing all the given networks,
+// or a zero IPNet if there are none or they're from different families.
+// e.g., Supernet(192.0.2.0/24, 192.0.5.0/24) -> 192.0.0.0/21.
+func Supernet(nets ...net.IPNet) net.IPNet {
+	var r Range
+	for _, n := range nets {
+		x := CIDRToRange(n)
+		switch {
+		case x.First == nil:
+			return net.IPNet{}
+		case r.First == nil:
+			r = x
+		case !sameFamily(r.First, x.First):
+			return net.IPNet{}
+		default:
+			if Compare(x.First, r.First) < 0 {
+				r.First = x.First
+			}
+			if Compare(x.Last, r.Last) > 0 {
+				r.Last = x.Last
+			}
+		}
+	}
+	if r.First == nil {
+		return net.IPNet{}
+	}
+	mask := net.CIDRMask(CommonPrefixLen(r.First, r.Last), 8*len(r.First))
+	return net.IPNet{IP: r.First.Mask(mask), Mask: mask}
+}
+
+// CommonPrefixLen returns the number of leading bits shared by two IP addresses,
+// or 0 if they're from different families.
+// e.g., CommonPrefixLen(192.0.2.1, 192.0.5.1) -> 21.
+func CommonPrefixLen(a, b net.IP) int {
+	a = IP(a)
+	b = IP(b)
+	if len(a) != len(b) {
+		return 0
+	}
+	n := 0
+	for i := range a {
+		x := a[i] ^ b[i]
+		n += bits.LeadingZeros8(x)
+		if x != 0 {
+			break
+		}
+	}
+	return n
+}
+
+// mergeRanges sorts a list of valid Ranges in place and merges overlapping and adjacent ones.
+func mergeRanges(ranges []Range) []Range {
+	slices.SortFunc(ranges, func(a, b Range) int {
+		return Compare(a.First, b.First)
+	})
+	var merged []Range
+	for _, r := range ranges {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			next, wrapped := NextIPOk(last.Last)
+			if sameFamily(last.Last, r.First) && (Compare(r.First, last.Last) <= 0 || !wrapped && next.Equal(r.First)) {
+				if Compare(r.Last, last.Last) > 0 {
+					last.Last = r.Last
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// MergeAdjacent returns the parent network of two sibling networks,
+// i.e., equal-sized networks which together form an aligned network of twice their size.
+// The networks can be in any order, false is returned if they aren't siblings.
+// e.g., MergeAdjacent(192.0.2.128/25, 192.0.2.0/25) -> 192.0.2.0/24, true.
+func MergeAdjacent(a, b net.IPNet) (net.IPNet, bool) {
+	fa, aOnes, aSize, err := splitBase(a)
+	if err != nil {
+		return net.IPNet{}, false
+	}
+	fb, bOnes, bSize, err := splitBase(b)
+	if err != nil || aOnes != bOnes || aSize != bSize || aOnes == 0 || fa.Equal(fb) {
+		return net.IPNet{}, false
+	}
+	mask := net.CIDRMask(aOnes-1, aSize)
+	if !fa.Mask(mask).Equal(fb.Mask(mask)) {
+		return net.IPNet{}, false
+	}
+	return net.IPNet{IP: fa.Mask(mask), Mask: mask}, true
+}
+
+// MergeAll repeatedly merges sibling networks in a list until none are left, see MergeAdjacent.
+// Networks are returned in the order defined by CompareNet, duplicates are removed but covered networks are kept.
+// e.g., MergeAll(192.0.2.0/25, 192.0.2.128/26, 192.0.2.192/26) -> [192.0.2.0/24].
+func MergeAll(nets []net.IPNet) []net.IPNet {
+	sorted := make([]net.IPNet, 0, len(nets))
+	for _, n := range nets {
+		if first, ones, size, err := splitBase(n); err == nil {
+			sorted = append(sorted, net.IPNet{IP: first, Mask: net.CIDRMask(ones, size)})
+		}
+	}
+	SortNets(sorted)
+	var out []net.IPNet
+	for _, n := range sorted {
+		out = append(out, n)
+		for len(out) > 1 {
+			a, b := out[len(out)-2], out[len(out)-1]
+			if CompareNet(a, b) == 0 {
+				out = out[:len(out)-1]
+				continue
+			}
+			parent, ok := MergeAdjacent(a, b)
+			if !ok {
+				break
+			}
+			out = append(out[:len(out)-2], parent)
+		}
+	}
+	return out
+}
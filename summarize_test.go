@@ -0,0 +1,270 @@
+package ipcalc
+
+import (
+	"math/big"
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		nets []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"192.0.2.0/25", "192.0.2.128/25", "192.0.2.64/26"}, []string{"192.0.2.0/24"}},
+		{[]string{"10.0.0.0/8", "10.1.0.0/16", "10.0.0.0/8"}, []string{"10.0.0.0/8"}},
+		{[]string{"192.0.2.0/24", "192.0.3.0/24"}, []string{"192.0.2.0/23"}},
+		{[]string{"192.0.1.0/24", "192.0.2.0/24"}, []string{"192.0.1.0/24", "192.0.2.0/24"}},
+		{[]string{"192.0.2.0/24", "192.0.3.0/24", "192.0.4.0/24"}, []string{"192.0.2.0/23", "192.0.4.0/24"}},
+		{[]string{"192.0.2.1/32", "192.0.2.0/32", "192.0.2.3/32", "192.0.2.2/32"}, []string{"192.0.2.0/30"}},
+		{[]string{"255.255.255.255/32", "::/128", "0.0.0.0/32"}, []string{"0.0.0.0/32", "255.255.255.255/32", "::/128"}},
+		{[]string{"2001:db8::/33", "2001:db8:8000::/33", "2001:db9::/32"}, []string{"2001:db8::/31"}},
+		{[]string{"0.0.0.0/1", "128.0.0.0/1", "::/1", "8000::/1"}, []string{"0.0.0.0/0", "::/0"}},
+	}
+	for _, tt := range tests {
+		var nets []net.IPNet
+		for _, s := range tt.nets {
+			nets = append(nets, mustCIDR(t, s))
+		}
+		var got []string
+		for _, n := range Summarize(nets) {
+			got = append(got, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Summarize(%v) = %v, want %v", tt.nets, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeNonContiguous(t *testing.T) {
+	nets := []net.IPNet{
+		{IP: net.IP{10, 0, 0, 0}, Mask: net.IPv4Mask(255, 0, 255, 0)},
+		mustCIDR(t, "192.0.2.0/24"),
+		{IP: net.ParseIP("2001:db8::"), Mask: net.IPMask(net.ParseIP("ffff::ffff"))},
+	}
+	var got []string
+	for _, n := range Summarize(nets) {
+		got = append(got, n.String())
+	}
+	if want := []string{"192.0.2.0/24"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize(%v) = %v, want %v", nets, got, want)
+	}
+}
+
+func TestAggregateWithSlack(t *testing.T) {
+	tests := []struct {
+		nets     []string
+		maxExtra int64
+		want     []string
+	}{
+		{nil, 100, nil},
+		{[]string{"192.0.2.0/25", "192.0.3.0/24"}, 0, []string{"192.0.2.0/25", "192.0.3.0/24"}},
+		{[]string{"192.0.2.0/25", "192.0.3.0/24"}, 127, []string{"192.0.2.0/25", "192.0.3.0/24"}},
+		{[]string{"192.0.2.0/25", "192.0.3.0/24"}, 128, []string{"192.0.2.0/23"}},
+		{[]string{"192.0.2.0/24", "192.0.3.0/24"}, 0, []string{"192.0.2.0/23"}},
+		// The cheapest merge is done first, the second one no longer fits.
+		{[]string{"10.0.0.0/32", "10.0.0.2/32", "10.0.1.0/32"}, 10, []string{"10.0.0.0/30", "10.0.1.0/32"}},
+		{[]string{"10.0.0.0/32", "10.0.0.2/32", "10.0.1.0/32"}, 512, []string{"10.0.0.0/23"}},
+		{[]string{"10.0.0.0/32", "10.0.0.5/32", "10.0.0.7/32"}, 4, []string{"10.0.0.0/32", "10.0.0.4/30"}},
+		{[]string{"255.255.255.255/32", "::/128"}, 1 << 40, []string{"255.255.255.255/32", "::/128"}},
+		{[]string{"2001:db8::/48", "2001:db8:2::/48"}, 1 << 62, []string{"2001:db8::/48", "2001:db8:2::/48"}},
+	}
+	for _, tt := range tests {
+		var nets []net.IPNet
+		for _, s := range tt.nets {
+			nets = append(nets, mustCIDR(t, s))
+		}
+		var got []string
+		for _, n := range AggregateWithSlack(nets, big.NewInt(tt.maxExtra)) {
+			got = append(got, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AggregateWithSlack(%v, %v) = %v, want %v", tt.nets, tt.maxExtra, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkAggregateWithSlack(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	nets := make([]net.IPNet, 5000)
+	for i := range nets {
+		mask := net.CIDRMask(24+r.Intn(9), 32)
+		nets[i] = net.IPNet{IP: Uint32ToIP(10<<24 | r.Uint32()>>8).Mask(mask), Mask: mask}
+	}
+	budget := big.NewInt(1 << 22)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AggregateWithSlack(nets, budget)
+	}
+}
+
+func TestGapsCovers(t *testing.T) {
+	tests := []struct {
+		parent string
+		nets   []string
+		gaps   []string
+	}{
+		{"10.0.0.0/8", []string{"10.0.0.0/9", "10.192.0.0/10"}, []string{"10.128.0.0/10"}},
+		{"192.0.2.0/24", []string{"192.0.2.0/25", "192.0.2.128/25"}, nil},
+		{"192.0.2.0/24", []string{"192.0.2.128/25", "192.0.2.0/26", "192.0.2.64/26"}, nil},
+		{"192.0.2.0/24", []string{"0.0.0.0/0"}, nil},
+		{"192.0.2.0/24", nil, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/24", []string{"::/0"}, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/24", []string{"192.0.2.0/25", "192.0.2.192/26"}, []string{"192.0.2.128/26"}},
+		{"2001:db8::/32", []string{"2001:db8::/33", "2001:db8:8000::/33"}, nil},
+	}
+	for _, tt := range tests {
+		var nets []net.IPNet
+		for _, s := range tt.nets {
+			nets = append(nets, mustCIDR(t, s))
+		}
+		var gaps []string
+		for _, n := range Gaps(mustCIDR(t, tt.parent), nets) {
+			gaps = append(gaps, n.String())
+		}
+		if !reflect.DeepEqual(gaps, tt.gaps) {
+			t.Errorf("Gaps(%v, %v) = %v, want %v", tt.parent, tt.nets, gaps, tt.gaps)
+		}
+		if got, want := Covers(mustCIDR(t, tt.parent), nets), tt.gaps == nil; got != want {
+			t.Errorf("Covers(%v, %v) = %v, want %v", tt.parent, tt.nets, got, want)
+		}
+	}
+	if Covers(net.IPNet{}, nil) {
+		t.Errorf("Covers(invalid, nil) = true, want false")
+	}
+}
+
+func TestExclude(t *testing.T) {
+	tests := []struct {
+		outer string
+		inner []string
+		want  []string
+	}{
+		{"192.0.2.0/24", nil, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/24", []string{"192.0.2.64/26"}, []string{"192.0.2.0/26", "192.0.2.128/25"}},
+		{"192.0.2.0/24", []string{"192.0.2.0/24"}, nil},
+		{"192.0.2.0/24", []string{"192.0.0.0/16"}, nil},
+		{"192.0.2.0/24", []string{"198.51.100.0/24", "2001:db8::/32"}, []string{"192.0.2.0/24"}},
+		{"192.0.2.0/30", []string{"192.0.2.1/32", "192.0.2.2/32"}, []string{"192.0.2.0/32", "192.0.2.3/32"}},
+		{"192.0.2.0/30", []string{"192.0.2.2/32", "192.0.2.0/32", "192.0.2.0/31"}, []string{"192.0.2.3/32"}},
+		{"10.0.0.0/8", []string{"10.0.0.0/9", "10.192.0.0/10"}, []string{"10.128.0.0/10"}},
+		{"0.0.0.0/0", []string{"255.255.255.255/32"}, []string{"0.0.0.0/1", "128.0.0.0/2", "192.0.0.0/3", "224.0.0.0/4", "240.0.0.0/5", "248.0.0.0/6", "252.0.0.0/7", "254.0.0.0/8", "255.0.0.0/9", "255.128.0.0/10", "255.192.0.0/11", "255.224.0.0/12", "255.240.0.0/13", "255.248.0.0/14", "255.252.0.0/15", "255.254.0.0/16", "255.255.0.0/17", "255.255.128.0/18", "255.255.192.0/19", "255.255.224.0/20", "255.255.240.0/21", "255.255.248.0/22", "255.255.252.0/23", "255.255.254.0/24", "255.255.255.0/25", "255.255.255.128/26", "255.255.255.192/27", "255.255.255.224/28", "255.255.255.240/29", "255.255.255.248/30", "255.255.255.252/31", "255.255.255.254/32"}},
+		{"2001:db8::/32", []string{"2001:db8::/33"}, []string{"2001:db8:8000::/33"}},
+	}
+	for _, tt := range tests {
+		var inner []net.IPNet
+		for _, s := range tt.inner {
+			inner = append(inner, mustCIDR(t, s))
+		}
+		var got []string
+		for _, n := range Exclude(mustCIDR(t, tt.outer), inner...) {
+			got = append(got, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Exclude(%v, %v) = %v, want %v", tt.outer, tt.inner, got, tt.want)
+		}
+	}
+}
+
+func TestSupernet(t *testing.T) {
+	tests := []struct {
+		nets []string
+		want string
+	}{
+		{nil, "<nil>"},
+		{[]string{"192.0.2.0/24"}, "192.0.2.0/24"},
+		{[]string{"192.0.2.0/24", "192.0.5.0/24"}, "192.0.0.0/21"},
+		{[]string{"192.0.2.0/25", "192.0.2.128/25"}, "192.0.2.0/24"},
+		{[]string{"192.0.2.0/24", "192.0.2.64/26"}, "192.0.2.0/24"},
+		{[]string{"10.0.0.0/8", "192.0.2.0/24"}, "0.0.0.0/0"},
+		{[]string{"2001:db8::/48", "2001:db8:1::/48", "2001:db8:ff::/64"}, "2001:db8::/40"},
+		{[]string{"192.0.2.0/24", "2001:db8::/32"}, "<nil>"},
+	}
+	for _, tt := range tests {
+		var nets []net.IPNet
+		for _, s := range tt.nets {
+			nets = append(nets, mustCIDR(t, s))
+		}
+		got := Supernet(nets...)
+		if got.String() != tt.want {
+			t.Errorf("Supernet(%v) = %v, want %v", tt.nets, &got, tt.want)
+		}
+	}
+}
+
+func TestCommonPrefixLen(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"192.0.2.1", "192.0.5.1", 21},
+		{"192.0.2.1", "192.0.2.1", 32},
+		{"192.0.2.1", "::ffff:192.0.2.1", 32},
+		{"0.0.0.0", "128.0.0.0", 0},
+		{"2001:db8::1", "2001:db8::", 127},
+		{"2001:db8::", "2001:db8::", 128},
+		{"192.0.2.1", "2001:db8::1", 0},
+	}
+	for _, tt := range tests {
+		if got := CommonPrefixLen(net.ParseIP(tt.a), net.ParseIP(tt.b)); got != tt.want {
+			t.Errorf("CommonPrefixLen(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMergeAdjacent(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"192.0.2.0/25", "192.0.2.128/25", "192.0.2.0/24"},
+		{"192.0.2.128/25", "192.0.2.0/25", "192.0.2.0/24"},
+		{"192.0.3.0/24", "192.0.4.0/24", ""},
+		{"192.0.2.0/24", "192.0.3.0/25", ""},
+		{"192.0.2.0/24", "192.0.2.0/24", ""},
+		{"0.0.0.0/0", "0.0.0.0/0", ""},
+		{"0.0.0.0/1", "128.0.0.0/1", "0.0.0.0/0"},
+		{"::ffff:192.0.2.0/121", "192.0.2.128/25", "192.0.2.0/24"},
+		{"2001:db8::/128", "2001:db8::1/128", "2001:db8::/127"},
+		{"0.0.0.0/32", "::1/128", ""},
+	}
+	for _, tt := range tests {
+		got, ok := MergeAdjacent(mustCIDR(t, tt.a), mustCIDR(t, tt.b))
+		if tt.want == "" {
+			if ok {
+				t.Errorf("MergeAdjacent(%v, %v) = %v, true, want false", tt.a, tt.b, &got)
+			}
+			continue
+		}
+		if !ok || got.String() != tt.want {
+			t.Errorf("MergeAdjacent(%v, %v) = %v, %v, want %v, true", tt.a, tt.b, &got, ok, tt.want)
+		}
+	}
+}
+
+func TestMergeAll(t *testing.T) {
+	tests := []struct {
+		nets []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"192.0.2.0/25", "192.0.2.128/26", "192.0.2.192/26"}, []string{"192.0.2.0/24"}},
+		{[]string{"192.0.2.192/26", "192.0.2.0/25", "192.0.2.128/26", "192.0.2.0/25"}, []string{"192.0.2.0/24"}},
+		{[]string{"192.0.3.0/24", "192.0.4.0/24"}, []string{"192.0.3.0/24", "192.0.4.0/24"}},
+		{[]string{"10.0.0.0/24", "10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24"}, []string{"10.0.0.0/23"}},
+		{[]string{"10.0.0.0/16", "10.0.1.0/24"}, []string{"10.0.0.0/16", "10.0.1.0/24"}},
+		{[]string{"2001:db8:1::/48", "192.0.2.1/32", "2001:db8::/48", "192.0.2.0/32"}, []string{"192.0.2.0/31", "2001:db8::/47"}},
+	}
+	for _, tt := range tests {
+		var nets []net.IPNet
+		for _, s := range tt.nets {
+			nets = append(nets, mustCIDR(t, s))
+		}
+		if got := netStrings(MergeAll(nets)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MergeAll(%v) = %v, want %v", tt.nets, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package ipcalc
+
+import (
+	"fmt"
+	"net"
+)
+
+// Prefix6to4 is the 6to4 prefix, 2002::/16 (RFC 3056).
+var Prefix6to4 = net.IPNet{
+	IP:   net.ParseIP("2002::"),
+	Mask: net.CIDRMask(16, 8*net.IPv6len),
+}
+
+// To6to4 returns the 6to4 /48 prefix of an IPv4 address, or the zero value if it's not an IPv4 address.
+// e.g., To6to4(192.0.2.1) -> 2002:c000:201::/48.
+func To6to4(v4 net.IP) net.IPNet {
+	ip4 := v4.To4()
+	if ip4 == nil {
+		return net.IPNet{}
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, Prefix6to4.IP[:2])
+	copy(ip[2:], ip4)
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(48, 8*net.IPv6len)}
+}
+
+// From6to4 returns the IPv4 address embedded in a 6to4 address.
+// e.g., From6to4(2002:c000:201::1) -> 192.0.2.1.
+func From6to4(v6 net.IP) (net.IP, error) {
+	if !Is6to4(v6) {
+		return nil, fmt.Errorf("ipcalc: %v is not a 6to4 address", v6)
+	}
+	return CopyIP(v6[2:6]), nil
+}
+
+// Is6to4 returns whether an IPv6 address is within Prefix6to4.
+func Is6to4(ip net.IP) bool {
+	return len(ip) == net.IPv6len && Prefix6to4.Contains(ip)
+}
+
+// PrefixTeredo is the Teredo prefix, 2001::/32 (RFC 4380).
+var PrefixTeredo = net.IPNet{
+	IP:   net.ParseIP("2001::"),
+	Mask: net.CIDRMask(32, 8*net.IPv6len),
+}
+
+// Teredo is a decoded Teredo address.
+// The client port and address are stored obfuscated (inverted) in the address so NATs don't rewrite them,
+// Port and Client hold the real values.
+type Teredo struct {
+	// Server is the IPv4 address of the Teredo server.
+	Server net.IP
+	// Flags are the Teredo flags, see Cone.
+	Flags uint16
+	// Port is the external UDP port of the client.
+	Port uint16
+	// Client is the external IPv4 address of the client.
+	Client net.IP
+}
+
+// Cone returns whether the client is behind a cone NAT.
+func (t Teredo) Cone() bool {
+	return t.Flags&0x8000 != 0
+}
+
+// ParseTeredo decodes a Teredo address.
+// e.g., ParseTeredo(2001:0:4136:e378:8000:63bf:3fff:fdd2) -> {65.54.227.120 0x8000 40000 192.0.2.45}.
+func ParseTeredo(ip net.IP) (Teredo, error) {
+	if !IsTeredo(ip) {
+		return Teredo{}, fmt.Errorf("ipcalc: %v is not a Teredo address", ip)
+	}
+	t := Teredo{
+		Server: CopyIP(ip[4:8]),
+		Flags:  uint16(ip[8])<<8 | uint16(ip[9]),
+		Port:   ^(uint16(ip[10])<<8 | uint16(ip[11])),
+		Client: Not(ip[12:16]),
+	}
+	return t, nil
+}
+
+// IsTeredo returns whether an IPv6 address is within PrefixTeredo.
+func IsTeredo(ip net.IP) bool {
+	return len(ip) == net.IPv6len && PrefixTeredo.Contains(ip)
+}
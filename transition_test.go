@@ -0,0 +1,66 @@
+package ipcalc
+
+import (
+	"net"
+	"testing"
+)
+
+func Test6to4(t *testing.T) {
+	tests := []struct {
+		v4     net.IP
+		prefix string
+	}{
+		{net.ParseIP("192.0.2.1"), "2002:c000:201::/48"},
+		{net.IP{10, 0, 0, 1}, "2002:a00:1::/48"},
+		{net.ParseIP("255.255.255.255"), "2002:ffff:ffff::/48"},
+	}
+	for _, tt := range tests {
+		n := To6to4(tt.v4)
+		if got := n.String(); got != tt.prefix {
+			t.Errorf("To6to4(%v) = %v, want %v", tt.v4, got, tt.prefix)
+		}
+		ip := NextIP(n.IP)
+		if !Is6to4(ip) {
+			t.Errorf("Is6to4(%v) = false, want true", ip)
+		}
+		if got, err := From6to4(ip); err != nil || !got.Equal(tt.v4) {
+			t.Errorf("From6to4(%v) = %v, %v, want %v", ip, got, err, tt.v4)
+		}
+	}
+	if n := To6to4(net.ParseIP("2001:db8::1")); n.IP != nil {
+		t.Errorf("To6to4(2001:db8::1) = %v, want zero value", &n)
+	}
+	for _, ip := range []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1"), net.IP{0x20, 0x02, 1, 2}, nil} {
+		if Is6to4(ip) {
+			t.Errorf("Is6to4(%v) = true, want false", ip)
+		}
+		if got, err := From6to4(ip); err == nil {
+			t.Errorf("From6to4(%v) = %v, want error", ip, got)
+		}
+	}
+}
+
+func TestParseTeredo(t *testing.T) {
+	ip := net.ParseIP("2001:0:4136:e378:8000:63bf:3fff:fdd2")
+	got, err := ParseTeredo(ip)
+	if err != nil {
+		t.Fatalf("ParseTeredo(%v) error = %v", ip, err)
+	}
+	if !got.Server.Equal(net.ParseIP("65.54.227.120")) || got.Flags != 0x8000 || !got.Cone() ||
+		got.Port != 40000 || !got.Client.Equal(net.ParseIP("192.0.2.45")) {
+		t.Errorf("ParseTeredo(%v) = %+v, want {65.54.227.120 0x8000 40000 192.0.2.45}", ip, got)
+	}
+	// The decoded addresses must not alias the input.
+	got.Server[0] = 0
+	if ip[4] == 0 {
+		t.Errorf("ParseTeredo(%v) result shares storage with its input", ip)
+	}
+	for _, ip := range []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1"), nil} {
+		if IsTeredo(ip) {
+			t.Errorf("IsTeredo(%v) = true, want false", ip)
+		}
+		if got, err := ParseTeredo(ip); err == nil {
+			t.Errorf("ParseTeredo(%v) = %+v, want error", ip, got)
+		}
+	}
+}
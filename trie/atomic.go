@@ -0,0 +1,49 @@
+package trie
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicTrie is a Trie safe for concurrent use, lookups are lock-free and never wait for updates.
+// Updates are applied to a copy of the Trie which then atomically replaces it (copy-on-write),
+// so they cost O(n) and are meant to be infrequent compared to lookups, e.g., applying routing updates in batches.
+// The zero value is an empty AtomicTrie ready to use.
+type AtomicTrie[V any] struct {
+	mu sync.Mutex // serializes updates
+	p  atomic.Pointer[Trie[V]]
+}
+
+// Load returns a snapshot of the Trie, it must not be modified.
+func (a *AtomicTrie[V]) Load() *Trie[V] {
+	if t := a.p.Load(); t != nil {
+		return t
+	}
+	return &Trie[V]{}
+}
+
+// Store replaces the Trie, t must not be modified afterwards.
+func (a *AtomicTrie[V]) Store(t *Trie[V]) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.p.Store(t)
+}
+
+// Update calls fn with a copy of the current Trie and publishes it, unless fn returns an error.
+// Concurrent updates are serialized, lookups see either the old or the new Trie, never a partial update.
+func (a *AtomicTrie[V]) Update(fn func(t *Trie[V]) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t := a.Load().Clone()
+	if err := fn(t); err != nil {
+		return err
+	}
+	a.p.Store(t)
+	return nil
+}
+
+// Lookup returns the longest network in the current Trie containing an IP address and its value.
+func (a *AtomicTrie[V]) Lookup(ip net.IP) (net.IPNet, V, bool) {
+	return a.Load().Lookup(ip)
+}
@@ -0,0 +1,69 @@
+package trie
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestAtomicTrie(t *testing.T) {
+	var a AtomicTrie[string]
+	if _, _, ok := a.Lookup(net.ParseIP("192.0.2.1")); ok {
+		t.Errorf("Lookup(192.0.2.1) ok = true on empty AtomicTrie")
+	}
+	a.Store(newTrie(t, "0.0.0.0/0", "192.0.2.0/24"))
+	old := a.Load()
+	wantErr := errors.New("rollback")
+	if err := a.Update(func(tr *Trie[string]) error {
+		tr.Insert(mustCIDR(t, "192.0.2.0/25"), "partial")
+		return wantErr
+	}); err != wantErr {
+		t.Errorf("Update() error = %v, want %v", err, wantErr)
+	}
+	if err := a.Update(func(tr *Trie[string]) error {
+		tr.Delete(mustCIDR(t, "0.0.0.0/0"))
+		return tr.Insert(mustCIDR(t, "192.0.2.128/25"), "192.0.2.128/25")
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	want := []string{"192.0.2.0/24=192.0.2.0/24", "192.0.2.128/25=192.0.2.128/25"}
+	if got := entries(a.Load()); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries() = %v, want %v", got, want)
+	}
+	// Earlier snapshots are unaffected by updates.
+	want = []string{"0.0.0.0/0=0.0.0.0/0", "192.0.2.0/24=192.0.2.0/24"}
+	if got := entries(old); !reflect.DeepEqual(got, want) {
+		t.Errorf("old snapshot entries() = %v, want %v", got, want)
+	}
+}
+
+func TestAtomicTrieConcurrent(t *testing.T) {
+	var a AtomicTrie[string]
+	a.Store(newTrie(t, "192.0.2.0/24"))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if _, _, ok := a.Lookup(net.ParseIP("192.0.2.1")); !ok {
+					t.Errorf("Lookup(192.0.2.1) ok = false, want true")
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		n := mustCIDR(t, "2001:db8::/48")
+		n.IP[5] = byte(i)
+		if err := a.Update(func(tr *Trie[string]) error { return tr.Insert(n, "x") }); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	wg.Wait()
+	if got := a.Load().Len(); got != 101 {
+		t.Errorf("Len() = %v, want 101", got)
+	}
+}
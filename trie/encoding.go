@@ -0,0 +1,84 @@
+package trie
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// entry is the serialized form of a network and its value.
+type entry[V any] struct {
+	Prefix string `json:"prefix"`
+	Value  V      `json:"value"`
+}
+
+// entries returns the serialized form of every network in the Trie, in the same order as All.
+func (t *Trie[V]) entries() []entry[V] {
+	e := make([]entry[V], 0, t.n)
+	for n, v := range t.All() {
+		e = append(e, entry[V]{n.String(), v})
+	}
+	return e
+}
+
+// fromEntries returns a Trie with the given networks and values.
+func fromEntries[V any](e []entry[V]) (*Trie[V], error) {
+	var t Trie[V]
+	for _, x := range e {
+		_, n, err := net.ParseCIDR(x.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("trie: %w", err)
+		}
+		if err := t.Insert(*n, x.Value); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, networks and values are encoded with encoding/gob,
+// so V must be encodable by it.
+func (t *Trie[V]) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(t.entries()); err != nil {
+		return nil, fmt.Errorf("trie: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the contents of the Trie.
+func (t *Trie[V]) UnmarshalBinary(data []byte) error {
+	var e []entry[V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return fmt.Errorf("trie: %w", err)
+	}
+	n, err := fromEntries(e)
+	if err != nil {
+		return err
+	}
+	*t = *n
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, a Trie is encoded as a list of {"prefix": CIDR, "value": V} objects
+// in the same order as All.
+func (t *Trie[V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.entries())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the contents of the Trie.
+// Later duplicate networks replace earlier ones.
+func (t *Trie[V]) UnmarshalJSON(data []byte) error {
+	var e []entry[V]
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	n, err := fromEntries(e)
+	if err != nil {
+		return err
+	}
+	*t = *n
+	return nil
+}
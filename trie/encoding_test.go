@@ -0,0 +1,65 @@
+package trie
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"0.0.0.0/0", "192.0.2.0/24", "192.0.2.1/32"},
+		{"::ffff:192.0.2.0/120", "2001:db8::/32", "::/0"},
+	}
+	for _, tt := range tests {
+		tr := newTrie(t, tt...)
+		b, err := tr.MarshalBinary()
+		if err != nil {
+			t.Errorf("MarshalBinary(%v) error = %v", tt, err)
+			continue
+		}
+		var got Trie[string]
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)) error = %v", tt, err)
+			continue
+		}
+		if !reflect.DeepEqual(entries(&got), entries(tr)) || got.Len() != tr.Len() {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)) = %v, want %v", tt, entries(&got), entries(tr))
+		}
+	}
+	var got Trie[string]
+	if err := got.UnmarshalBinary([]byte("garbage")); err == nil {
+		t.Errorf("UnmarshalBinary(garbage) error = nil, want error")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var tr Trie[int]
+	tr.Insert(mustCIDR(t, "2001:db8::/32"), 3)
+	tr.Insert(mustCIDR(t, "192.0.2.0/24"), 1)
+	tr.Insert(mustCIDR(t, "0.0.0.0/0"), 0)
+	b, err := json.Marshal(&tr)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `[{"prefix":"0.0.0.0/0","value":0},{"prefix":"192.0.2.0/24","value":1},{"prefix":"2001:db8::/32","value":3}]`
+	if string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+	if b, _ := json.Marshal(&Trie[int]{}); string(b) != "[]" {
+		t.Errorf("json.Marshal(empty) = %s, want []", b)
+	}
+	var got Trie[int]
+	if err := json.Unmarshal([]byte(want), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if _, v, ok := got.Lookup(mustCIDR(t, "192.0.2.1/32").IP); !ok || v != 1 || got.Len() != 3 {
+		t.Errorf("json.Unmarshal() Lookup(192.0.2.1) = %v, %v, Len() = %v, want 1, true, 3", v, ok, got.Len())
+	}
+	for _, in := range []string{`[{"prefix":"192.0.2.0","value":1}]`, `[{"prefix":"192.0.2.0/24","value":"x"}]`, `{}`} {
+		if err := json.Unmarshal([]byte(in), &got); err == nil {
+			t.Errorf("json.Unmarshal(%v) error = nil, want error", in)
+		}
+	}
+}
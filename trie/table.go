@@ -0,0 +1,113 @@
+package trie
+
+import (
+	"encoding/binary"
+	"iter"
+	"net"
+	"sort"
+
+	"github.com/hazaelsan/ipcalc/uint128"
+)
+
+// Table is an immutable longest-prefix match table, optimized for lookups.
+// The networks are flattened into sorted, disjoint address ranges, each mapped to its longest matching network,
+// so a lookup is a binary search over a contiguous array regardless of prefix lengths.
+// The zero value is an empty Table, a Table is safe for concurrent use.
+type Table[V any] struct {
+	prefixes []PrefixValue[V]
+	starts4  []uint32
+	vals4    []int32
+	starts6  []uint128.Uint128
+	vals6    []int32
+}
+
+// Build returns a Table for a list of networks and values, later duplicate networks replace earlier ones.
+func Build[V any](entries []PrefixValue[V]) (*Table[V], error) {
+	var idx Trie[int32]
+	for i, e := range entries {
+		if err := idx.Insert(e.Prefix, int32(i)); err != nil {
+			return nil, err
+		}
+	}
+	t := &Table[V]{}
+	// Keep only the networks which survived deduplication, in trie order.
+	remap := make(map[int32]int32, idx.Len())
+	for n, i := range idx.All() {
+		remap[i] = int32(len(t.prefixes))
+		t.prefixes = append(t.prefixes, PrefixValue[V]{n, entries[i].Value})
+	}
+	var starts []uint128.Uint128
+	flatten(idx.v4, uint128.Zero, 0, 8*net.IPv4len, -1, remap, &starts, &t.vals4)
+	for _, s := range starts {
+		t.starts4 = append(t.starts4, uint32(s.Lo))
+	}
+	flatten(idx.v6, uint128.Zero, 0, 8*net.IPv6len, -1, remap, &t.starts6, &t.vals6)
+	return t, nil
+}
+
+// flatten appends the ranges covered by x, whose first address is lo, with their longest matching network.
+// best is the index of the longest network covering x, or -1, and adjacent ranges with the same match are merged.
+func flatten(x *node[int32], lo uint128.Uint128, depth, size int, best int32, remap map[int32]int32, starts *[]uint128.Uint128, vals *[]int32) {
+	push := func(start uint128.Uint128, v int32) {
+		if n := len(*vals); n == 0 || (*vals)[n-1] != v {
+			*starts = append(*starts, start)
+			*vals = append(*vals, v)
+		}
+	}
+	if x == nil {
+		push(lo, best)
+		return
+	}
+	if x.set {
+		best = remap[x.value]
+	}
+	if depth == size {
+		push(lo, best)
+		return
+	}
+	for b, c := range x.children {
+		clo := lo
+		if b == 1 {
+			clo = lo.Or(uint128.One.ShiftLeft(uint(size - depth - 1)))
+		}
+		flatten(c, clo, depth+1, size, best, remap, starts, vals)
+	}
+}
+
+// Len returns the number of networks in the Table.
+func (t *Table[V]) Len() int {
+	return len(t.prefixes)
+}
+
+// Lookup returns the longest network containing an IP address and its value.
+// The returned network is shared with the Table and must not be modified.
+func (t *Table[V]) Lookup(ip net.IP) (net.IPNet, V, bool) {
+	i := int32(-1)
+	if ip4 := ip.To4(); ip4 != nil {
+		v := binary.BigEndian.Uint32(ip4)
+		// j is 0 only for the zero Table, which has no ranges.
+		if j := sort.Search(len(t.starts4), func(j int) bool { return t.starts4[j] > v }); j > 0 {
+			i = t.vals4[j-1]
+		}
+	} else if v, ok := uint128.FromIP(ip); ok && len(ip) == net.IPv6len {
+		if j := sort.Search(len(t.starts6), func(j int) bool { return t.starts6[j].Cmp(v) > 0 }); j > 0 {
+			i = t.vals6[j-1]
+		}
+	}
+	if i < 0 {
+		var zero V
+		return net.IPNet{}, zero, false
+	}
+	return t.prefixes[i].Prefix, t.prefixes[i].Value, true
+}
+
+// All returns an iterator over every network in the Table and its value, in the same order as Trie.All.
+func (t *Table[V]) All() iter.Seq2[net.IPNet, V] {
+	return func(yield func(net.IPNet, V) bool) {
+		for _, p := range t.prefixes {
+			if !yield(p.Prefix, p.Value) {
+				return
+			}
+		}
+	}
+}
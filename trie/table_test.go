@@ -0,0 +1,127 @@
+package trie
+
+import (
+	"math/rand"
+	"net"
+	"testing"
+)
+
+// randomEntries returns n random networks of both families, with /8 to /32 IPv4 and /16 to /64 IPv6 prefixes.
+func randomEntries(r *rand.Rand, n int) []PrefixValue[int] {
+	entries := make([]PrefixValue[int], n)
+	for i := range entries {
+		ip, ones, size := make(net.IP, net.IPv4len), 8+r.Intn(25), 32
+		if i%4 == 0 {
+			ip, ones, size = make(net.IP, net.IPv6len), 16+r.Intn(49), 128
+		}
+		r.Read(ip)
+		mask := net.CIDRMask(ones, size)
+		entries[i] = PrefixValue[int]{net.IPNet{IP: ip.Mask(mask), Mask: mask}, i}
+	}
+	return entries
+}
+
+func randomIPs(r *rand.Rand, n int) []net.IP {
+	ips := make([]net.IP, n)
+	for i := range ips {
+		ips[i] = make(net.IP, net.IPv4len)
+		if i%4 == 0 {
+			ips[i] = make(net.IP, net.IPv6len)
+		}
+		r.Read(ips[i])
+	}
+	return ips
+}
+
+func TestBuild(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	entries := randomEntries(r, 2000)
+	// Add some well-known networks so that lookups have matches, and a duplicate which must win.
+	for _, s := range []string{"0.0.0.0/0", "192.0.2.0/24", "192.0.2.0/25", "192.0.2.1/32", "2000::/3", "192.0.2.0/24"} {
+		entries = append(entries, PrefixValue[int]{mustCIDR(t, s), len(entries)})
+	}
+	table, err := Build(entries)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	var tr Trie[int]
+	for _, e := range entries {
+		tr.Insert(e.Prefix, e.Value)
+	}
+	if table.Len() != tr.Len() {
+		t.Errorf("Len() = %v, want %v", table.Len(), tr.Len())
+	}
+	ips := randomIPs(r, 20000)
+	for _, e := range entries {
+		ips = append(ips, e.Prefix.IP)
+	}
+	ips = append(ips, net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.255"), net.ParseIP("::ffff:192.0.2.2"), nil)
+	for _, ip := range ips {
+		wn, wv, wok := tr.Lookup(ip)
+		gn, gv, gok := table.Lookup(ip)
+		if gok != wok || gv != wv || gn.String() != wn.String() {
+			t.Errorf("Lookup(%v) = %v, %v, %v, want %v, %v, %v", ip, &gn, gv, gok, &wn, wv, wok)
+		}
+	}
+	if _, v, _ := table.Lookup(net.ParseIP("192.0.2.200")); v != len(entries)-1 {
+		t.Errorf("Lookup(192.0.2.200) = %v, want the last duplicate %v", v, len(entries)-1)
+	}
+	var got, want []string
+	for n := range table.All() {
+		got = append(got, n.String())
+	}
+	for n := range tr.All() {
+		want = append(want, n.String())
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[len(got)-1] != want[len(want)-1] {
+		t.Errorf("All() = %v networks, want %v", len(got), len(want))
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	table, err := Build[string](nil)
+	if err != nil {
+		t.Fatalf("Build(nil) error = %v", err)
+	}
+	if _, _, ok := table.Lookup(net.ParseIP("192.0.2.1")); ok || table.Len() != 0 {
+		t.Errorf("Lookup(192.0.2.1) = %v on an empty Table", ok)
+	}
+	if _, err := Build([]PrefixValue[string]{{Prefix: net.IPNet{}}}); err == nil {
+		t.Errorf("Build(<nil>) error = nil, want error")
+	}
+	var zero Table[string]
+	for _, ip := range []string{"192.0.2.1", "2001:db8::1"} {
+		if _, _, ok := zero.Lookup(net.ParseIP(ip)); ok || zero.Len() != 0 {
+			t.Errorf("Lookup(%v) = %v on a zero Table", ip, ok)
+		}
+	}
+	for n := range zero.All() {
+		t.Errorf("All() = %v on a zero Table", &n)
+	}
+}
+
+func benchmarkLookup(b *testing.B, lookup func(net.IP)) {
+	ips := randomIPs(rand.New(rand.NewSource(2)), 1<<12)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lookup(ips[i&(len(ips)-1)])
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	entries := randomEntries(rand.New(rand.NewSource(1)), 100000)
+	var tr Trie[int]
+	for _, e := range entries {
+		tr.Insert(e.Prefix, e.Value)
+	}
+	table, err := Build(entries)
+	if err != nil {
+		b.Fatalf("Build() error = %v", err)
+	}
+	b.Run("Trie", func(b *testing.B) {
+		benchmarkLookup(b, func(ip net.IP) { tr.Lookup(ip) })
+	})
+	b.Run("Table", func(b *testing.B) {
+		benchmarkLookup(b, func(ip net.IP) { table.Lookup(ip) })
+	})
+}
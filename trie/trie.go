@@ -0,0 +1,254 @@
+// Package trie provides a binary radix tree mapping IP networks to values, with longest-prefix match lookups
+// as done by routing tables.
+//
+//	var t trie.Trie[string]
+//	t.Insert(*defaultRoute, "upstream")
+//	t.Insert(*office, "vpn")
+//	_, nextHop, _ := t.Lookup(ip)
+//
+// IPv4 and IPv4-mapped IPv6 networks are stored as IPv4 networks, host bits are ignored.
+package trie
+
+import (
+	"fmt"
+	"iter"
+	"net"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// PrefixValue is a network and its associated value.
+type PrefixValue[V any] struct {
+	Prefix net.IPNet
+	Value  V
+}
+
+type node[V any] struct {
+	children [2]*node[V]
+	value    V
+	set      bool
+}
+
+// Trie maps IP networks to values, the zero value is an empty Trie ready to use.
+// A Trie is not safe for concurrent use.
+type Trie[V any] struct {
+	v4, v6 *node[V]
+	n      int
+}
+
+// Len returns the number of networks in the Trie.
+func (t *Trie[V]) Len() int {
+	return t.n
+}
+
+// Insert associates a value with a network, replacing any previous value.
+func (t *Trie[V]) Insert(n net.IPNet, v V) error {
+	ip, ones, err := key(n)
+	if err != nil {
+		return err
+	}
+	root := t.root(len(ip))
+	if *root == nil {
+		*root = &node[V]{}
+	}
+	x := *root
+	for i := 0; i < ones; i++ {
+		b := bit(ip, i)
+		if x.children[b] == nil {
+			x.children[b] = &node[V]{}
+		}
+		x = x.children[b]
+	}
+	if !x.set {
+		t.n++
+	}
+	x.value, x.set = v, true
+	return nil
+}
+
+// Get returns the value associated with exactly the given network.
+func (t *Trie[V]) Get(n net.IPNet) (V, bool) {
+	var zero V
+	ip, ones, err := key(n)
+	if err != nil {
+		return zero, false
+	}
+	x := *t.root(len(ip))
+	for i := 0; x != nil && i < ones; i++ {
+		x = x.children[bit(ip, i)]
+	}
+	if x == nil || !x.set {
+		return zero, false
+	}
+	return x.value, true
+}
+
+// Delete removes a network from the Trie, it returns whether the network was present.
+func (t *Trie[V]) Delete(n net.IPNet) bool {
+	ip, ones, err := key(n)
+	if err != nil {
+		return false
+	}
+	root := t.root(len(ip))
+	path := make([]*node[V], 0, ones+1)
+	x := *root
+	for i := 0; x != nil && i < ones; i++ {
+		path = append(path, x)
+		x = x.children[bit(ip, i)]
+	}
+	if x == nil || !x.set {
+		return false
+	}
+	var zero V
+	x.value, x.set = zero, false
+	t.n--
+	// Prune nodes left without values or children.
+	for i := len(path); i >= 0 && x.children[0] == nil && x.children[1] == nil && !x.set; i-- {
+		if i == 0 {
+			*root = nil
+			break
+		}
+		x = path[i-1]
+		x.children[bit(ip, i-1)] = nil
+	}
+	return true
+}
+
+// Lookup returns the longest network containing an IP address and its value.
+// e.g., Lookup(192.0.2.1) -> 192.0.2.0/24 if the Trie has 192.0.2.0/24 and 192.0.0.0/16.
+func (t *Trie[V]) Lookup(ip net.IP) (net.IPNet, V, bool) {
+	var match PrefixValue[V]
+	found := false
+	t.covering(ip, func(p PrefixValue[V]) {
+		match, found = p, true
+	})
+	return match.Prefix, match.Value, found
+}
+
+// LookupAll returns every network containing an IP address and its value, from the shortest to the longest.
+func (t *Trie[V]) LookupAll(ip net.IP) []PrefixValue[V] {
+	var matches []PrefixValue[V]
+	t.covering(ip, func(p PrefixValue[V]) {
+		matches = append(matches, p)
+	})
+	return matches
+}
+
+// covering calls fn for every network containing an IP address, from the shortest to the longest.
+func (t *Trie[V]) covering(ip net.IP, fn func(PrefixValue[V])) {
+	ip = ipcalc.IP(ip)
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return
+	}
+	x := *t.root(len(ip))
+	for i := 0; x != nil; i++ {
+		if x.set {
+			fn(PrefixValue[V]{prefix(ip, i), x.value})
+		}
+		if i == 8*len(ip) {
+			break
+		}
+		x = x.children[bit(ip, i)]
+	}
+}
+
+// Clone returns a deep copy of the Trie, values are copied by assignment.
+func (t *Trie[V]) Clone() *Trie[V] {
+	return &Trie[V]{v4: clone(t.v4), v6: clone(t.v6), n: t.n}
+}
+
+func clone[V any](x *node[V]) *node[V] {
+	if x == nil {
+		return nil
+	}
+	return &node[V]{
+		children: [2]*node[V]{clone(x.children[0]), clone(x.children[1])},
+		value:    x.value,
+		set:      x.set,
+	}
+}
+
+// All returns an iterator over every network in the Trie and its value, in the same order as ipcalc.SortNets,
+// i.e., IPv4 first and by address, shorter networks before longer ones with the same address.
+// The Trie must not be modified during the iteration.
+func (t *Trie[V]) All() iter.Seq2[net.IPNet, V] {
+	return func(yield func(net.IPNet, V) bool) {
+		for _, r := range []struct {
+			x    *node[V]
+			size int
+		}{{t.v4, net.IPv4len}, {t.v6, net.IPv6len}} {
+			if !walk(r.x, make(net.IP, r.size), 0, yield) {
+				return
+			}
+		}
+	}
+}
+
+// Walk calls fn for every network in the Trie in the same order as All, stopping if fn returns false.
+func (t *Trie[V]) Walk(fn func(n net.IPNet, v V) bool) {
+	for n, v := range t.All() {
+		if !fn(n, v) {
+			return
+		}
+	}
+}
+
+// walk visits x and its descendants in pre-order, ip holds the path to x, which is at the given depth.
+func walk[V any](x *node[V], ip net.IP, depth int, yield func(net.IPNet, V) bool) bool {
+	if x == nil {
+		return true
+	}
+	if x.set && !yield(prefix(ip, depth), x.value) {
+		return false
+	}
+	for b, c := range x.children {
+		if c == nil {
+			continue
+		}
+		setBit(ip, depth, b)
+		if !walk(c, ip, depth+1, yield) {
+			return false
+		}
+	}
+	setBit(ip, depth, 0)
+	return true
+}
+
+func (t *Trie[V]) root(size int) **node[V] {
+	if size == net.IPv4len {
+		return &t.v4
+	}
+	return &t.v6
+}
+
+// key returns the normalized address and prefix length of a network.
+func key(n net.IPNet) (net.IP, int, error) {
+	norm := ipcalc.Normalize(n)
+	ones, bits := norm.Mask.Size()
+	if norm.IP == nil || bits == 0 {
+		return nil, 0, fmt.Errorf("trie: invalid network %v", &n)
+	}
+	return norm.IP, ones, nil
+}
+
+// prefix returns the network of the given length containing ip.
+func prefix(ip net.IP, ones int) net.IPNet {
+	mask := net.CIDRMask(ones, 8*len(ip))
+	return net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+func bit(ip net.IP, i int) int {
+	return int(ip[i/8]>>(7-uint(i%8))) & 1
+}
+
+func setBit(ip net.IP, i, b int) {
+	if i >= 8*len(ip) {
+		return
+	}
+	mask := byte(1) << (7 - uint(i%8))
+	if b == 0 {
+		ip[i/8] &^= mask
+	} else {
+		ip[i/8] |= mask
+	}
+}
@@ -0,0 +1,135 @@
+package trie
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func mustCIDR(t testing.TB, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatalf("ParseCIDR(%v) error = %v", s, err)
+	}
+	return *n
+}
+
+func newTrie(t testing.TB, nets ...string) *Trie[string] {
+	t.Helper()
+	tr := &Trie[string]{}
+	for _, n := range nets {
+		if err := tr.Insert(mustCIDR(t, n), n); err != nil {
+			t.Fatalf("Insert(%v) error = %v", n, err)
+		}
+	}
+	return tr
+}
+
+func entries(tr *Trie[string]) []string {
+	var s []string
+	tr.Walk(func(n net.IPNet, v string) bool {
+		s = append(s, fmt.Sprintf("%v=%v", &n, v))
+		return true
+	})
+	return s
+}
+
+func TestLookup(t *testing.T) {
+	tr := newTrie(t, "0.0.0.0/0", "192.0.0.0/16", "192.0.2.0/24", "192.0.2.128/25", "192.0.2.1/32", "2001:db8::/32", "2001:db8:1::/48")
+	tests := []struct {
+		ip   string
+		want string
+		all  []string
+	}{
+		{"192.0.2.1", "192.0.2.1/32", []string{"0.0.0.0/0", "192.0.0.0/16", "192.0.2.0/24", "192.0.2.1/32"}},
+		{"192.0.2.2", "192.0.2.0/24", []string{"0.0.0.0/0", "192.0.0.0/16", "192.0.2.0/24"}},
+		{"192.0.2.200", "192.0.2.128/25", []string{"0.0.0.0/0", "192.0.0.0/16", "192.0.2.0/24", "192.0.2.128/25"}},
+		{"::ffff:192.0.3.1", "192.0.0.0/16", []string{"0.0.0.0/0", "192.0.0.0/16"}},
+		{"8.8.8.8", "0.0.0.0/0", []string{"0.0.0.0/0"}},
+		{"2001:db8:1::1", "2001:db8:1::/48", []string{"2001:db8::/32", "2001:db8:1::/48"}},
+		{"2001:db9::1", "", nil},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		n, v, ok := tr.Lookup(net.ParseIP(tt.ip))
+		if ok != (tt.want != "") || ok && (n.String() != tt.want || v != tt.want) {
+			t.Errorf("Lookup(%v) = %v, %v, %v, want %v", tt.ip, &n, v, ok, tt.want)
+		}
+		var all []string
+		for _, p := range tr.LookupAll(net.ParseIP(tt.ip)) {
+			all = append(all, p.Prefix.String())
+		}
+		if !reflect.DeepEqual(all, tt.all) {
+			t.Errorf("LookupAll(%v) = %v, want %v", tt.ip, all, tt.all)
+		}
+	}
+}
+
+func TestInsertGetDelete(t *testing.T) {
+	tr := newTrie(t, "192.0.2.0/24", "192.0.2.0/25", "10.0.0.0/8")
+	if err := tr.Insert(mustCIDR(t, "192.0.2.0/24"), "replaced"); err != nil {
+		t.Fatalf("Insert(192.0.2.0/24) error = %v", err)
+	}
+	if v, ok := tr.Get(mustCIDR(t, "192.0.2.0/24")); !ok || v != "replaced" || tr.Len() != 3 {
+		t.Errorf("Get(192.0.2.0/24) = %v, %v, Len() = %v, want replaced, true, 3", v, ok, tr.Len())
+	}
+	if err := tr.Insert(net.IPNet{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(24, 32)}, "host bits"); err != nil {
+		t.Fatalf("Insert(192.0.2.1/24) error = %v", err)
+	}
+	if v, _ := tr.Get(mustCIDR(t, "192.0.2.0/24")); v != "host bits" {
+		t.Errorf("Get(192.0.2.0/24) = %v, want host bits", v)
+	}
+	if _, ok := tr.Get(mustCIDR(t, "192.0.0.0/16")); ok {
+		t.Errorf("Get(192.0.0.0/16) = true, want false")
+	}
+	if err := tr.Insert(net.IPNet{IP: net.ParseIP("192.0.2.0"), Mask: net.IPMask{255, 0, 255, 0}}, "x"); err == nil {
+		t.Errorf("Insert(non-contiguous mask) error = nil, want error")
+	}
+	if !tr.Delete(mustCIDR(t, "192.0.2.0/24")) || tr.Delete(mustCIDR(t, "192.0.2.0/24")) || tr.Delete(mustCIDR(t, "192.0.0.0/16")) {
+		t.Errorf("Delete(192.0.2.0/24) twice or Delete(192.0.0.0/16) returned unexpected results")
+	}
+	if got, want := entries(tr), []string{"10.0.0.0/8=10.0.0.0/8", "192.0.2.0/25=192.0.2.0/25"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+	tr.Delete(mustCIDR(t, "192.0.2.0/25"))
+	tr.Delete(mustCIDR(t, "10.0.0.0/8"))
+	if tr.Len() != 0 || tr.v4 != nil {
+		t.Errorf("Len() = %v, root = %v after deleting everything, want 0, nil", tr.Len(), tr.v4)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	tr := newTrie(t, "2001:db8::/32", "192.0.2.128/25", "192.0.2.0/24", "::/0", "10.0.0.0/8", "192.0.2.0/25", "0.0.0.0/0")
+	want := []string{
+		"0.0.0.0/0=0.0.0.0/0", "10.0.0.0/8=10.0.0.0/8", "192.0.2.0/24=192.0.2.0/24", "192.0.2.0/25=192.0.2.0/25",
+		"192.0.2.128/25=192.0.2.128/25", "::/0=::/0", "2001:db8::/32=2001:db8::/32",
+	}
+	if got := entries(tr); !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+	n := 0
+	tr.Walk(func(net.IPNet, string) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Errorf("Walk() visited %v networks after returning false, want 3", n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	tr := newTrie(t, "0.0.0.0/0", "192.0.2.0/24", "2001:db8::/32")
+	c := tr.Clone()
+	c.Delete(mustCIDR(t, "192.0.2.0/24"))
+	c.Insert(mustCIDR(t, "192.0.2.0/25"), "192.0.2.0/25")
+	want := []string{"0.0.0.0/0=0.0.0.0/0", "192.0.2.0/24=192.0.2.0/24", "2001:db8::/32=2001:db8::/32"}
+	if got := entries(tr); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries() after modifying clone = %v, want %v", got, want)
+	}
+	want = []string{"0.0.0.0/0=0.0.0.0/0", "192.0.2.0/25=192.0.2.0/25", "2001:db8::/32=2001:db8::/32"}
+	if got := entries(c); !reflect.DeepEqual(got, want) || c.Len() != 3 {
+		t.Errorf("Clone() entries() = %v, Len() = %v, want %v", got, c.Len(), want)
+	}
+}
@@ -0,0 +1,149 @@
+// Package uint128 provides an unsigned 128-bit integer type for word-based IP address arithmetic.
+//
+// All arithmetic wraps around modulo 2^128, IPv4 addresses are stored in the low 32 bits:
+//
+//	u, _ := uint128.FromIP(net.ParseIP("2001:db8::ffff:ffff:ffff:ffff"))
+//	fmt.Println(u.Add(uint128.One).IP(net.IPv6len)) // 2001:db8:0:1::
+package uint128
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+	"net"
+)
+
+// Commonly used values.
+var (
+	Zero = Uint128{}
+	One  = Uint128{Lo: 1}
+	Max  = Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+)
+
+// Uint128 is an unsigned 128-bit integer, Hi holds the most significant 64 bits.
+type Uint128 struct {
+	Hi, Lo uint64
+}
+
+// FromIP returns the value of a 4-byte or 16-byte IP address, or false for any other length.
+// Addresses are used as-is, i.e., 16-byte IPv4-mapped addresses include the ::ffff:0:0/96 prefix.
+func FromIP(ip net.IP) (Uint128, bool) {
+	switch len(ip) {
+	case net.IPv4len:
+		return Uint128{Lo: uint64(binary.BigEndian.Uint32(ip))}, true
+	case net.IPv6len:
+		return Uint128{Hi: binary.BigEndian.Uint64(ip[:8]), Lo: binary.BigEndian.Uint64(ip[8:])}, true
+	}
+	return Zero, false
+}
+
+// IP returns the low 32 bits (size net.IPv4len) or all 128 bits (size net.IPv6len) of u as an IP address,
+// or nil for any other size.
+func (u Uint128) IP(size int) net.IP {
+	switch size {
+	case net.IPv4len:
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, uint32(u.Lo))
+		return ip
+	case net.IPv6len:
+		ip := make(net.IP, net.IPv6len)
+		binary.BigEndian.PutUint64(ip[:8], u.Hi)
+		binary.BigEndian.PutUint64(ip[8:], u.Lo)
+		return ip
+	}
+	return nil
+}
+
+// Add returns u+v.
+func (u Uint128) Add(v Uint128) Uint128 {
+	lo, carry := bits.Add64(u.Lo, v.Lo, 0)
+	hi, _ := bits.Add64(u.Hi, v.Hi, carry)
+	return Uint128{hi, lo}
+}
+
+// Sub returns u-v.
+func (u Uint128) Sub(v Uint128) Uint128 {
+	lo, borrow := bits.Sub64(u.Lo, v.Lo, 0)
+	hi, _ := bits.Sub64(u.Hi, v.Hi, borrow)
+	return Uint128{hi, lo}
+}
+
+// Cmp returns -1, 0 or 1 depending on whether u is less than, equal to or greater than v.
+func (u Uint128) Cmp(v Uint128) int {
+	switch {
+	case u == v:
+		return 0
+	case u.Hi < v.Hi || u.Hi == v.Hi && u.Lo < v.Lo:
+		return -1
+	}
+	return 1
+}
+
+// ShiftLeft returns u<<n.
+func (u Uint128) ShiftLeft(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Zero
+	case n >= 64:
+		return Uint128{Hi: u.Lo << (n - 64)}
+	}
+	return Uint128{Hi: u.Hi<<n | u.Lo>>(64-n), Lo: u.Lo << n}
+}
+
+// ShiftRight returns u>>n.
+func (u Uint128) ShiftRight(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Zero
+	case n >= 64:
+		return Uint128{Lo: u.Hi >> (n - 64)}
+	}
+	return Uint128{Hi: u.Hi >> n, Lo: u.Lo>>n | u.Hi<<(64-n)}
+}
+
+// And returns u&v.
+func (u Uint128) And(v Uint128) Uint128 {
+	return Uint128{u.Hi & v.Hi, u.Lo & v.Lo}
+}
+
+// Or returns u|v.
+func (u Uint128) Or(v Uint128) Uint128 {
+	return Uint128{u.Hi | v.Hi, u.Lo | v.Lo}
+}
+
+// Xor returns u^v.
+func (u Uint128) Xor(v Uint128) Uint128 {
+	return Uint128{u.Hi ^ v.Hi, u.Lo ^ v.Lo}
+}
+
+// Not returns ^u.
+func (u Uint128) Not() Uint128 {
+	return Uint128{^u.Hi, ^u.Lo}
+}
+
+// LeadingZeros returns the number of leading zero bits in u, 128 for zero.
+func (u Uint128) LeadingZeros() int {
+	if u.Hi != 0 {
+		return bits.LeadingZeros64(u.Hi)
+	}
+	return 64 + bits.LeadingZeros64(u.Lo)
+}
+
+// TrailingZeros returns the number of trailing zero bits in u, 128 for zero.
+func (u Uint128) TrailingZeros() int {
+	if u.Lo != 0 {
+		return bits.TrailingZeros64(u.Lo)
+	}
+	return 64 + bits.TrailingZeros64(u.Hi)
+}
+
+// Big returns u as a big.Int.
+func (u Uint128) Big() *big.Int {
+	v := new(big.Int).SetUint64(u.Hi)
+	return v.Lsh(v, 64).Or(v, new(big.Int).SetUint64(u.Lo))
+}
+
+// String returns the decimal representation of u.
+func (u Uint128) String() string {
+	return u.Big().String()
+}
@@ -0,0 +1,138 @@
+package uint128
+
+import (
+	"bytes"
+	"math/big"
+	"net"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) Uint128 {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok || v.Sign() < 0 || v.BitLen() > 128 {
+		t.Fatalf("invalid Uint128 %q", s)
+	}
+	lo := new(big.Int).And(v, new(big.Int).SetUint64(^uint64(0)))
+	return Uint128{Hi: new(big.Int).Rsh(v, 64).Uint64(), Lo: lo.Uint64()}
+}
+
+func TestFromIP(t *testing.T) {
+	tests := []struct {
+		ip   net.IP
+		want Uint128
+		ok   bool
+	}{
+		{net.IP{192, 0, 2, 1}, Uint128{Lo: 0xc0000201}, true},
+		{net.ParseIP("192.0.2.1"), Uint128{Lo: 0xffffc0000201}, true},
+		{net.ParseIP("2001:db8::1"), Uint128{Hi: 0x20010db800000000, Lo: 1}, true},
+		{net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"), Max, true},
+		{nil, Zero, false},
+		{net.IP{1, 2, 3}, Zero, false},
+	}
+	for _, tt := range tests {
+		got, ok := FromIP(tt.ip)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FromIP(%v) = %v, %v, want %v, %v", tt.ip, got, ok, tt.want, tt.ok)
+		}
+		if !ok {
+			continue
+		}
+		if ip := got.IP(len(tt.ip)); !bytes.Equal(ip, tt.ip) {
+			t.Errorf("IP(%v) = %v, want %v", len(tt.ip), ip, tt.ip)
+		}
+	}
+	if got := Max.IP(net.IPv4len); !bytes.Equal(got, net.IP{255, 255, 255, 255}) {
+		t.Errorf("Max.IP(4) = %v, want 255.255.255.255", got)
+	}
+	if got := One.IP(8); got != nil {
+		t.Errorf("One.IP(8) = %v, want nil", got)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		u, v     string
+		add, sub string
+		cmp      int
+	}{
+		{"0", "0", "0", "0", 0},
+		{"1", "2", "3", "0xffffffffffffffffffffffffffffffff", -1},
+		{"0xffffffffffffffff", "1", "0x10000000000000000", "0xfffffffffffffffe", 1},
+		{"0x10000000000000000", "1", "0x10000000000000001", "0xffffffffffffffff", 1},
+		{"0xffffffffffffffffffffffffffffffff", "1", "0", "0xfffffffffffffffffffffffffffffffe", 1},
+		{"0x10000000000000000", "0xffffffffffffffff", "0x1ffffffffffffffff", "1", 1},
+		{"0x20000000000000000", "0x20000000000000001", "0x40000000000000001", "0xffffffffffffffffffffffffffffffff", -1},
+	}
+	for _, tt := range tests {
+		u, v := mustParse(t, tt.u), mustParse(t, tt.v)
+		if got, want := u.Add(v), mustParse(t, tt.add); got != want {
+			t.Errorf("%v.Add(%v) = %v, want %v", u, v, got, want)
+		}
+		if got, want := u.Sub(v), mustParse(t, tt.sub); got != want {
+			t.Errorf("%v.Sub(%v) = %v, want %v", u, v, got, want)
+		}
+		if got := u.Cmp(v); got != tt.cmp {
+			t.Errorf("%v.Cmp(%v) = %v, want %v", u, v, got, tt.cmp)
+		}
+	}
+}
+
+func TestShift(t *testing.T) {
+	u := mustParse(t, "0x0123456789abcdef0fedcba987654321")
+	for n := uint(0); n <= 130; n++ {
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+		left := new(big.Int).Lsh(u.Big(), n)
+		if got, want := u.ShiftLeft(n).Big(), left.And(left, mask); got.Cmp(want) != 0 {
+			t.Errorf("%v.ShiftLeft(%v) = %v, want %v", u, n, got, want)
+		}
+		if got, want := u.ShiftRight(n).Big(), new(big.Int).Rsh(u.Big(), n); got.Cmp(want) != 0 {
+			t.Errorf("%v.ShiftRight(%v) = %v, want %v", u, n, got, want)
+		}
+	}
+}
+
+func TestBitwise(t *testing.T) {
+	u := Uint128{Hi: 0xff00, Lo: 0x0ff0}
+	v := Uint128{Hi: 0x0ff0, Lo: 0xf00f}
+	if got, want := u.And(v), (Uint128{Hi: 0x0f00, Lo: 0x0000}); got != want {
+		t.Errorf("And() = %#v, want %#v", got, want)
+	}
+	if got, want := u.Or(v), (Uint128{Hi: 0xfff0, Lo: 0xffff}); got != want {
+		t.Errorf("Or() = %#v, want %#v", got, want)
+	}
+	if got, want := u.Xor(v), (Uint128{Hi: 0xf0f0, Lo: 0xffff}); got != want {
+		t.Errorf("Xor() = %#v, want %#v", got, want)
+	}
+	if got := Zero.Not(); got != Max {
+		t.Errorf("Zero.Not() = %v, want %v", got, Max)
+	}
+}
+
+func TestZeros(t *testing.T) {
+	tests := []struct {
+		u                 Uint128
+		leading, trailing int
+	}{
+		{Zero, 128, 128},
+		{One, 127, 0},
+		{Max, 0, 0},
+		{Uint128{Hi: 1}, 63, 64},
+		{Uint128{Lo: 1 << 63}, 64, 63},
+		{Uint128{Hi: 1 << 63}, 0, 127},
+	}
+	for _, tt := range tests {
+		if got := tt.u.LeadingZeros(); got != tt.leading {
+			t.Errorf("%v.LeadingZeros() = %v, want %v", tt.u, got, tt.leading)
+		}
+		if got := tt.u.TrailingZeros(); got != tt.trailing {
+			t.Errorf("%v.TrailingZeros() = %v, want %v", tt.u, got, tt.trailing)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if got, want := Max.String(), "340282366920938463463374607431768211455"; got != want {
+		t.Errorf("Max.String() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package ipcalc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single problem found by Validate.
+type ValidationError struct {
+	// Line and Column are 1-based positions of the offending entry, Column counts characters (runes), not bytes.
+	Line   int
+	Column int
+	// Text is the offending entry.
+	Text string
+	// Reason describes why the entry is invalid.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d:%d: %v: %q", e.Line, e.Column, e.Reason, e.Text)
+}
+
+// ValidationErrors is the list of problems found by Validate, in input order.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Validate checks a config of ip[/mask] entries, as accepted by ParseIPMask, and reports every invalid entry.
+// Entries are separated by whitespace, blank lines are ignored and # starts a comment running to the end of the line.
+// It returns ValidationErrors if any entry is invalid, or the underlying error if r can't be read,
+// joined with the ValidationErrors for the entries read before it.
+func Validate(r io.Reader) error {
+	var errs ValidationErrors
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		for col := 0; col < len(text); {
+			if isListSep(rune(text[col])) {
+				col++
+				continue
+			}
+			entry, _ := nextElem(text[col:])
+			if reason := validateEntry(entry); reason != "" {
+				errs = append(errs, &ValidationError{Line: line, Column: utf8.RuneCountInString(text[:col]) + 1, Text: entry, Reason: reason})
+			}
+			col += len(entry)
+		}
+	}
+	if err := s.Err(); err != nil {
+		if errs != nil {
+			return errors.Join(errs, err)
+		}
+		return err
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// validateEntry returns why an ip[/mask] entry is invalid, or an empty string if it's valid.
+func validateEntry(entry string) string {
+	ip, mask, err := ParseIPMask(entry)
+	if err != nil {
+		if pe, ok := err.(*net.ParseError); ok {
+			return "invalid " + pe.Type
+		}
+		return err.Error()
+	}
+	if mask != nil && len(mask) != IPSize(ip) {
+		return "mask does not match address family"
+	}
+	return ""
+}
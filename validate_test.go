@@ -0,0 +1,56 @@
+package ipcalc
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestValidate(t *testing.T) {
+	config := `# ACL entries
+192.0.2.0/24
+192.0.2.300/24 2001:db8::/64   # trailing comment
+  10.0.0.0/~0.0.255.255, 10.1.0.0/33
+
+2001:db8::/255.0.0.0 foo
+`
+	want := ValidationErrors{
+		{Line: 3, Column: 1, Text: "192.0.2.300/24", Reason: "invalid IP address"},
+		{Line: 4, Column: 26, Text: "10.1.0.0/33", Reason: "invalid Mask"},
+		{Line: 6, Column: 1, Text: "2001:db8::/255.0.0.0", Reason: "mask does not match address family"},
+		{Line: 6, Column: 22, Text: "foo", Reason: "invalid IP address"},
+	}
+	err := Validate(strings.NewReader(config))
+	var got ValidationErrors
+	if !errors.As(err, &got) {
+		t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+	if err := Validate(strings.NewReader("192.0.2.0/24\n# 192.0.2.300\n")); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateColumn(t *testing.T) {
+	// "é" is 2 bytes but a single character.
+	err := Validate(strings.NewReader("é 192.0.2.1 10.1.0.0/33\n"))
+	var got ValidationErrors
+	if !errors.As(err, &got) || len(got) != 2 || got[0].Column != 1 || got[1].Column != 13 {
+		t.Errorf("Validate() = %v, want errors at columns 1 and 13", err)
+	}
+}
+
+func TestValidateReadError(t *testing.T) {
+	readErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("192.0.2.300\n"), iotest.ErrReader(readErr))
+	err := Validate(r)
+	var got ValidationErrors
+	if !errors.Is(err, readErr) || !errors.As(err, &got) || len(got) != 1 || got[0].Text != "192.0.2.300" {
+		t.Errorf("Validate() error = %v, want %v and the entries read before it", err, readErr)
+	}
+}
@@ -0,0 +1,104 @@
+// Package vlsm plans variable-length subnetting (VLSM) of a parent network.
+//
+// Requirements are sized either by number of hosts or by prefix length,
+// and allocated largest first so every subnet stays aligned:
+//
+//	p, err := vlsm.Allocate(parent, []vlsm.Requirement{
+//		{Name: "LAN1", Hosts: 100},
+//		{Name: "WAN1", PrefixLen: 30},
+//	})
+//	for _, a := range p.Allocations {
+//		fmt.Println(a.Name, &a.Subnet)
+//	}
+package vlsm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"net"
+	"sort"
+
+	"github.com/hazaelsan/ipcalc"
+)
+
+// ErrNoSpace is returned when the requirements don't fit in the parent network.
+var ErrNoSpace = errors.New("vlsm: not enough space in parent network")
+
+// Requirement is a named subnet, exactly one of Hosts and PrefixLen must be set.
+type Requirement struct {
+	Name string
+	// Hosts is the number of usable host addresses needed,
+	// IPv4 subnets reserve the network and broadcast addresses, IPv6 subnets reserve none.
+	Hosts int
+	// PrefixLen is the exact prefix length needed.
+	PrefixLen int
+}
+
+// Allocation is a subnet assigned to a Requirement.
+type Allocation struct {
+	Name   string
+	Subnet net.IPNet
+	// Hosts is the number of usable host addresses in Subnet.
+	Hosts *big.Int
+}
+
+// Plan is the result of allocating requirements in a parent network.
+type Plan struct {
+	Parent net.IPNet
+	// Allocations are in allocation order, i.e., largest first, ties keep the requirement order.
+	Allocations []Allocation
+	// Free is the minimal list of networks left unallocated, in ascending order.
+	Free []net.IPNet
+}
+
+// Allocate assigns each requirement the lowest free subnet of a parent network, largest requirements first.
+// IPv4-mapped IPv6 parents are planned as IPv4 networks, e.g., ::ffff:10.0.0.0/104 as 10.0.0.0/8.
+func Allocate(parent net.IPNet, reqs []Requirement) (Plan, error) {
+	norm := ipcalc.Normalize(parent)
+	ones, size := norm.Mask.Size()
+	base := norm.IP
+	if base == nil || size == 0 {
+		// Print the mask as given, String would truncate an IPv6 mask on an IPv4-mapped address.
+		return Plan{}, fmt.Errorf("vlsm: invalid parent network %v/%v", parent.IP, parent.Mask)
+	}
+	policy := ipcalc.ReserveNone
+	if size == 8*net.IPv4len {
+		policy = ipcalc.ReserveNetworkBroadcast
+	}
+	hostBits := make([]int, len(reqs))
+	for i, r := range reqs {
+		switch {
+		case r.Hosts > 0 && r.PrefixLen == 0:
+			hostBits[i] = bits.Len(uint(r.Hosts + policy.Head + policy.Tail - 1))
+		case r.Hosts == 0 && r.PrefixLen > 0 && r.PrefixLen <= size:
+			hostBits[i] = size - r.PrefixLen
+		default:
+			return Plan{}, fmt.Errorf("vlsm: invalid requirement %+v", r)
+		}
+	}
+	order := make([]int, len(reqs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return hostBits[order[i]] > hostBits[order[j]]
+	})
+	p := Plan{Parent: net.IPNet{IP: base, Mask: net.CIDRMask(ones, size)}}
+	offset := new(big.Int)
+	avail := new(big.Int).Lsh(big.NewInt(1), uint(size-ones))
+	var used []net.IPNet
+	for _, i := range order {
+		block := new(big.Int).Lsh(big.NewInt(1), uint(hostBits[i]))
+		if hostBits[i] > size-ones || new(big.Int).Add(offset, block).Cmp(avail) > 0 {
+			return Plan{}, fmt.Errorf("%w: %v for %+v", ErrNoSpace, &parent, reqs[i])
+		}
+		n := net.IPNet{IP: ipcalc.AddBig(base, offset), Mask: net.CIDRMask(size-hostBits[i], size)}
+		p.Allocations = append(p.Allocations, Allocation{Name: reqs[i].Name, Subnet: n, Hosts: policy.UsableHosts(n)})
+		used = append(used, n)
+		offset.Add(offset, block)
+	}
+	p.Free = ipcalc.Exclude(p.Parent, used...)
+	return p, nil
+}
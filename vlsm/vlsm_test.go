@@ -0,0 +1,120 @@
+package vlsm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, s string) net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *n
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		parent string
+		reqs   []Requirement
+		want   []string
+		free   []string
+	}{
+		{
+			parent: "192.0.2.0/24",
+			want:   nil,
+			free:   []string{"192.0.2.0/24"},
+		},
+		{
+			parent: "192.0.2.77/24",
+			reqs: []Requirement{
+				{Name: "WAN1", PrefixLen: 30},
+				{Name: "LAN1", Hosts: 100},
+				{Name: "LAN2", Hosts: 50},
+				{Name: "WAN2", PrefixLen: 30},
+			},
+			want: []string{"LAN1 192.0.2.0/25 126", "LAN2 192.0.2.128/26 62", "WAN1 192.0.2.192/30 2", "WAN2 192.0.2.196/30 2"},
+			free: []string{"192.0.2.200/29", "192.0.2.208/28", "192.0.2.224/27"},
+		},
+		{
+			parent: "192.0.2.0/24",
+			reqs:   []Requirement{{Name: "LAN1", Hosts: 254}},
+			want:   []string{"LAN1 192.0.2.0/24 254"},
+		},
+		{
+			parent: "::ffff:192.0.2.0/120",
+			reqs:   []Requirement{{Name: "LAN1", Hosts: 100}, {Name: "WAN1", PrefixLen: 30}},
+			want:   []string{"LAN1 192.0.2.0/25 126", "WAN1 192.0.2.128/30 2"},
+			free:   []string{"192.0.2.132/30", "192.0.2.136/29", "192.0.2.144/28", "192.0.2.160/27", "192.0.2.192/26"},
+		},
+		{
+			parent: "2001:db8::/123",
+			reqs: []Requirement{
+				{Name: "loopback", PrefixLen: 128},
+				{Name: "servers", PrefixLen: 124},
+				{Name: "p2p", Hosts: 2},
+			},
+			want: []string{"servers 2001:db8::/124 16", "p2p 2001:db8::10/127 2", "loopback 2001:db8::12/128 1"},
+			free: []string{"2001:db8::13/128", "2001:db8::14/126", "2001:db8::18/125"},
+		},
+	}
+	for _, tt := range tests {
+		p, err := Allocate(mustCIDR(t, tt.parent), tt.reqs)
+		if err != nil {
+			t.Errorf("Allocate(%v, %+v) error = %v", tt.parent, tt.reqs, err)
+			continue
+		}
+		var got, free []string
+		for _, a := range p.Allocations {
+			got = append(got, fmt.Sprintf("%v %v %v", a.Name, &a.Subnet, a.Hosts))
+		}
+		for _, n := range p.Free {
+			free = append(free, n.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Allocate(%v, %+v) = %v, want %v", tt.parent, tt.reqs, got, tt.want)
+		}
+		if !reflect.DeepEqual(free, tt.free) {
+			t.Errorf("Allocate(%v, %+v).Free = %v, want %v", tt.parent, tt.reqs, free, tt.free)
+		}
+	}
+}
+
+func TestAllocateErrors(t *testing.T) {
+	tests := []struct {
+		parent  string
+		reqs    []Requirement
+		noSpace bool
+	}{
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1", Hosts: 255}}, true},
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1", Hosts: 126}, {Name: "LAN2", Hosts: 127}}, true},
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1", PrefixLen: 23}}, true},
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1"}}, false},
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1", Hosts: 10, PrefixLen: 28}}, false},
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1", PrefixLen: 33}}, false},
+		{"192.0.2.0/24", []Requirement{{Name: "LAN1", Hosts: -1}}, false},
+	}
+	for _, tt := range tests {
+		p, err := Allocate(mustCIDR(t, tt.parent), tt.reqs)
+		if err == nil {
+			t.Errorf("Allocate(%v, %+v) = %+v, want error", tt.parent, tt.reqs, p)
+			continue
+		}
+		if got := errors.Is(err, ErrNoSpace); got != tt.noSpace {
+			t.Errorf("Allocate(%v, %+v) error = %v, want ErrNoSpace %v", tt.parent, tt.reqs, err, tt.noSpace)
+		}
+	}
+	bad := net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(24, 32)}
+	if _, err := Allocate(bad, nil); err == nil {
+		t.Errorf("Allocate(%v, nil) error = nil, want error", &bad)
+	}
+	mapped := net.IPNet{IP: net.ParseIP("::ffff:10.0.0.0"), Mask: append(net.CIDRMask(96, 128)[:12:12], 255, 0, 255, 0)}
+	if _, err := Allocate(mapped, nil); err == nil || !strings.Contains(err.Error(), "10.0.0.0/ffffffffffffffffffffffffff00ff00") {
+		t.Errorf("Allocate(%v, nil) error = %v, want the mask as given", mapped.IP, err)
+	}
+}
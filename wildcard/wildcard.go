@@ -15,7 +15,9 @@
 package wildcard
 
 import (
+	"iter"
 	"net"
+	"net/netip"
 
 	"github.com/hazaelsan/ipcalc"
 )
@@ -38,6 +40,12 @@ func New(ip net.IP, wildcard net.IPMask) Wildcard {
 	}
 }
 
+// FromAddr returns a Wildcard from a given netip.Addr and wildcard mask.
+// IPv4-mapped IPv6 addresses are treated as IPv4 addresses.
+func FromAddr(addr netip.Addr, wildcard netip.Addr) Wildcard {
+	return New(net.IP(addr.AsSlice()), net.IPMask(wildcard.AsSlice()))
+}
+
 // IP returns the current IP address for a Wildcard.
 func (w Wildcard) IP() net.IP {
 	return w.ip
@@ -53,6 +61,11 @@ func (w Wildcard) Matches(ip net.IP) bool {
 	return ipcalc.And(ip, w.mask).Equal(w.bits)
 }
 
+// MatchesAddr returns whether a netip.Addr matches the Wildcard.
+func (w Wildcard) MatchesAddr(addr netip.Addr) bool {
+	return addr.IsValid() && w.Matches(net.IP(addr.AsSlice()))
+}
+
 // First returns a Wildcard with the lowest IP address matching the Wildcard.
 // e.g., New(192.0.2.128, 0.0.0.254).First() -> Wildcard(192.0.2.0, 0.0.0.254).
 func (w Wildcard) First() Wildcard {
@@ -109,6 +122,24 @@ func (w *Wildcard) Prev() net.IP {
 	return w.ip
 }
 
+// All returns an iterator over every IP address matching the Wildcard, in ascending order.
+// Iteration starts at First and ends at Last, or as soon as the caller stops ranging.
+// Each yielded net.IP is a fresh copy which the caller may retain.
+// e.g., New(192.0.2.0, 0.0.0.3).All() -> 192.0.2.0, 192.0.2.1, 192.0.2.2, 192.0.2.3.
+func (w Wildcard) All() iter.Seq[net.IP] {
+	return func(yield func(net.IP) bool) {
+		cur := w.First()
+		last := w.Last().IP()
+		for {
+			ip := ipcalc.CopyIP(cur.IP())
+			if !yield(ip) || ip.Equal(last) {
+				return
+			}
+			cur.Next()
+		}
+	}
+}
+
 func bit(b byte, i uint8) bool {
 	return b>>i&1 == 1
 }
@@ -3,6 +3,8 @@ package wildcard
 import (
 	"bytes"
 	"net"
+	"net/netip"
+	"reflect"
 	"testing"
 
 	"github.com/hazaelsan/ipcalc"
@@ -58,6 +60,29 @@ func TestMatches(t *testing.T) {
 	}
 }
 
+func TestFromAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		mask    string
+		matches map[string]bool
+	}{
+		{"192.0.2.1", "0.0.255.254", map[string]bool{"192.0.9.3": true, "192.0.9.4": false, "::ffff:192.0.2.5": true}},
+		{"::ffff:192.0.2.1", "0.0.0.255", map[string]bool{"192.0.2.200": true, "192.0.3.1": false}},
+		{"2001:db8::1", "::ffff:0:0:fffe", map[string]bool{"2001:db8::ab:0:0:3": true, "2001:db8::2": false}},
+	}
+	for _, tt := range tests {
+		w := FromAddr(netip.MustParseAddr(tt.addr), netip.MustParseAddr(tt.mask))
+		for addr, want := range tt.matches {
+			if got := w.MatchesAddr(netip.MustParseAddr(addr)); got != want {
+				t.Errorf("FromAddr(%v, %v).MatchesAddr(%v) = %v, want %v", tt.addr, tt.mask, addr, got, want)
+			}
+		}
+		if w.MatchesAddr(netip.Addr{}) {
+			t.Errorf("FromAddr(%v, %v).MatchesAddr(invalid) = true", tt.addr, tt.mask)
+		}
+	}
+}
+
 func TestFirst(t *testing.T) {
 	tests := map[string]string{
 		"192.0.2.129/0.0.0.254":       "192.0.2.1",
@@ -159,6 +184,43 @@ func TestPrev(t *testing.T) {
 	}
 }
 
+func TestAll(t *testing.T) {
+	tests := map[string][]string{
+		"192.0.2.0/0.0.0.3":   {"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3"},
+		"192.0.2.7/0.0.2.0":   {"192.0.0.7", "192.0.2.7"},
+		"192.0.2.255/0.0.0.0": {"192.0.2.255"},
+		"2001:db8::1/::6":     {"2001:db8::1", "2001:db8::3", "2001:db8::5", "2001:db8::7"},
+	}
+	for addr, want := range tests {
+		ip, mask, err := ipcalc.ParseIPMask(addr)
+		if err != nil {
+			t.Errorf("ParseIPMask(%v) error = %v", addr, err)
+			continue
+		}
+		var got []string
+		for ip := range New(ip, mask).All() {
+			got = append(got, ip.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("All(%v) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	w := New(net.ParseIP("192.0.2.0"), ipcalc.ParseMask("0.0.0.255"))
+	var got []net.IP
+	for ip := range w.All() {
+		got = append(got, ip)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || !got[0].Equal(net.ParseIP("192.0.2.0")) || !got[1].Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("All() with break = %v, want [192.0.2.0 192.0.2.1]", got)
+	}
+}
+
 func TestFindWildcard(t *testing.T) {
 	tests := []struct {
 		ips   []string